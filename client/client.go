@@ -3,9 +3,13 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 )
 
 // TODO: Add support for custom http client option
@@ -17,7 +21,39 @@ type Config struct {
 type ProxyClient interface {
 	Health() error
 	GetData(ctx context.Context, cert []byte) ([]byte, error)
+	GetDataWithL1InclusionBlock(ctx context.Context, cert []byte, l1InclusionBlock uint64) ([]byte, error)
 	SetData(ctx context.Context, b []byte) ([]byte, error)
+	SetDataAsync(ctx context.Context, b []byte) (*jobs.Job, error)
+	GetPutStatus(ctx context.Context, id string) (*jobs.Job, error)
+	VerifyData(ctx context.Context, cert []byte, b []byte) (*verify.CertReports, error)
+}
+
+// ResponseError ... error response of the proxy. Code is the category of the error (e.g, "not_found",
+// "invalid_cert" or "insufficient_depth"), and is empty if the proxy didn't send one.
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("received error response, code=%d, msg = %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("received error response, code=%d (%s), msg = %s", e.StatusCode, e.Code, e.Message)
+}
+
+// newResponseError decodes the JSON error body of a response, or keeps the raw body as the message
+func newResponseError(statusCode int, body []byte) *ResponseError {
+	e := &ResponseError{StatusCode: statusCode, Message: string(body)}
+	var decoded struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &decoded); err == nil && decoded.Code != "" {
+		e.Code, e.Message = decoded.Code, decoded.Message
+	}
+	return e
 }
 
 // client is the implementation of ProxyClient
@@ -59,8 +95,16 @@ func (c *client) Health() error {
 
 // GetData fetches blob data associated with a DA certificate
 func (c *client) GetData(ctx context.Context, comm []byte) ([]byte, error) {
-	url := fmt.Sprintf("%s/get/0x%x?commitment_mode=simple", c.cfg.URL, comm)
+	return c.getData(ctx, fmt.Sprintf("%s/get/0x%x?commitment_mode=simple", c.cfg.URL, comm))
+}
+
+// GetDataWithL1InclusionBlock fetches blob data associated with a DA certificate that was included in
+// the given L1 block, which fails with status 418 if the cert is outside of the proxy's recency window
+func (c *client) GetDataWithL1InclusionBlock(ctx context.Context, comm []byte, l1InclusionBlock uint64) ([]byte, error) {
+	return c.getData(ctx, fmt.Sprintf("%s/get/0x%x?commitment_mode=simple&l1_inclusion_block=%d", c.cfg.URL, comm, l1InclusionBlock))
+}
 
+func (c *client) getData(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct http request: %w", err)
@@ -80,7 +124,7 @@ func (c *client) GetData(ctx context.Context, comm []byte) ([]byte, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received error response, code=%d, msg = %s", resp.StatusCode, string(b))
+		return nil, newResponseError(resp.StatusCode, b)
 	}
 
 	return b, nil
@@ -106,7 +150,7 @@ func (c *client) SetData(ctx context.Context, b []byte) ([]byte, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to store data: %v, err = %s", resp.StatusCode, string(b))
+		return nil, fmt.Errorf("failed to store data: %w", newResponseError(resp.StatusCode, b))
 	}
 
 	if len(b) == 0 {
@@ -115,3 +159,82 @@ func (c *client) SetData(ctx context.Context, b []byte) ([]byte, error) {
 
 	return b, err
 }
+
+// SetDataAsync queues raw byte data for dispersal to DA and returns the dispersal job,
+// whose commitment can later be polled with GetPutStatus
+func (c *client) SetDataAsync(ctx context.Context, b []byte) (*jobs.Job, error) {
+	url := fmt.Sprintf("%s/put/?commitment_mode=simple&async=true", c.cfg.URL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	return c.doJobRequest(req, http.StatusAccepted)
+}
+
+// GetPutStatus fetches the state of an asynchronous dispersal job
+func (c *client) GetPutStatus(ctx context.Context, id string) (*jobs.Job, error) {
+	url := fmt.Sprintf("%s/put/status/%s", c.cfg.URL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	return c.doJobRequest(req, http.StatusOK)
+}
+
+// VerifyData runs every verification step of a DA certificate, and of the raw byte data against it
+// unless it's nil, and returns the report of each step
+func (c *client) VerifyData(ctx context.Context, comm []byte, b []byte) (*verify.CertReports, error) {
+	url := fmt.Sprintf("%s/verify/0x%x?commitment_mode=simple", c.cfg.URL, comm)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp.StatusCode, body)
+	}
+
+	var reports verify.CertReports
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode verification reports: %w", err)
+	}
+	return &reports, nil
+}
+
+func (c *client) doJobRequest(req *http.Request, expectedCode int) (*jobs.Job, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != expectedCode {
+		return nil, newResponseError(resp.StatusCode, b)
+	}
+
+	var job jobs.Job
+	if err := json.Unmarshal(b, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode dispersal job: %w", err)
+	}
+	return &job, nil
+}
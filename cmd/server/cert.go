@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/server"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/clients/codecs"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+)
+
+const (
+	commitmentModeFlagName     = "commitment-mode"
+	blobFlagName               = "blob"
+	ethRPCFlagName             = "eth-rpc"
+	svcManagerAddrFlagName     = "svc-manager-addr"
+	confirmationDepthFlagName  = "confirmation-depth"
+	g1PathFlagName             = "g1-path"
+	g2PowerOf2PathFlagName     = "g2-power-of-2-path"
+	cachePathFlagName          = "cache-path"
+	jsonFlagName               = "json"
+	detectCommitmentModeString = "auto"
+)
+
+// certCommand ... offline inspection and verification of the certs in commitments returned by the proxy
+func certCommand() *cli.Command {
+	commitmentModeFlag := &cli.StringFlag{
+		Name: commitmentModeFlagName,
+		Usage: fmt.Sprintf("Commitment mode of the commitment: %q, %q, %q or %q. %q tells them apart by their prefix bytes, "+
+			"except for %q certs which share the prefix of %q commitments.",
+			commitments.OptimismGeneric, commitments.SimpleCommitmentMode, commitments.OptimismKeccak, commitments.ArbitrumNitro,
+			detectCommitmentModeString, commitments.ArbitrumNitro, commitments.OptimismGeneric),
+		Value: detectCommitmentModeString,
+	}
+
+	return &cli.Command{
+		Name:  "cert",
+		Usage: "Inspect and verify EigenDA certs",
+		Before: func(_ *cli.Context) error {
+			// stdout is reserved for the output of the subcommands
+			oplog.SetGlobalLogHandler(log.LogfmtHandlerWithLevel(os.Stderr, log.LevelWarn))
+			return nil
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "decode",
+				Usage:     "Decode the cert of a commitment into JSON",
+				ArgsUsage: "<hex commitment>",
+				Flags:     []cli.Flag{commitmentModeFlag},
+				Action:    decodeCert,
+			},
+			{
+				Name:  "verify",
+				Usage: "Verify the cert of a commitment, and print a step by step report",
+				Description: "Checks the merkle inclusion proof of the cert. With a blob, checks the blob against the kzg commitment of the cert. " +
+					"With an eth rpc and the EigenDA service manager address, checks the batch metadata hash and security params of the cert against the contract.",
+				ArgsUsage: "<hex commitment>",
+				Flags: []cli.Flag{
+					commitmentModeFlag,
+					&cli.StringFlag{
+						Name:  blobFlagName,
+						Usage: "Path of a file holding the payload of the cert, as posted to the proxy.",
+					},
+					&cli.StringFlag{
+						Name:  ethRPCFlagName,
+						Usage: "JSON RPC node endpoint for the Ethereum network the cert was confirmed on.",
+					},
+					&cli.StringFlag{
+						Name:  svcManagerAddrFlagName,
+						Usage: "The deployed EigenDA service manager address.",
+					},
+					&cli.StringFlag{
+						Name:  confirmationDepthFlagName,
+						Usage: fmt.Sprintf("Number of blocks the confirmation block of the cert must be below the head, or %q.", verify.FinalizedConfirmationDepth),
+						Value: "0",
+						Action: func(_ *cli.Context, depth string) error {
+							_, _, err := verify.ParseConfirmationDepth(depth)
+							return err
+						},
+					},
+					&cli.StringFlag{
+						Name:  g1PathFlagName,
+						Usage: "Directory path to g1.point file.",
+						Value: "resources/g1.point",
+					},
+					&cli.StringFlag{
+						Name:  g2PowerOf2PathFlagName,
+						Usage: "Path to g2.point.powerOf2 file.",
+						Value: "resources/g2.point.powerOf2",
+					},
+					&cli.StringFlag{
+						Name:  cachePathFlagName,
+						Usage: "Path to SRS tables for caching.",
+						Value: "resources/SRSTables/",
+					},
+					&cli.BoolFlag{
+						Name:  jsonFlagName,
+						Usage: "Print the report as JSON.",
+					},
+				},
+				Action: verifyCert,
+			},
+		},
+	}
+}
+
+// decodedKey ... the cert key of a commitment, and the prefixes it was decoded from
+type decodedKey struct {
+	CommitmentMode commitments.CommitmentMode `json:"commitment_mode"`
+	CertVersion    byte                       `json:"cert_version"`
+	*server.CertKey
+}
+
+// decodeCommitment strips the prefixes of a hex encoded commitment, and decodes the key that follows
+func decodeCommitment(s string, mode string) (*decodedKey, error) {
+	b, err := hexutil.Decode(ensureHexPrefix(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode hex commitment: %w", err)
+	}
+	if len(b) < 3 {
+		return nil, fmt.Errorf("commitment is too short")
+	}
+
+	var decoded decodedKey
+	if mode == detectCommitmentModeString {
+		decoded.CommitmentMode, err = detectCommitmentMode(b)
+	} else {
+		decoded.CommitmentMode, err = commitments.StringToCommitmentMode(mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	switch decoded.CommitmentMode {
+	case commitments.OptimismKeccak:
+		return nil, fmt.Errorf("%s commitments are the keccak256 hash of the payload, not a cert", commitments.OptimismKeccak)
+	case commitments.OptimismGeneric: // [op_type, da_provider, cert_version, ...]
+		decoded.CertVersion, key = b[2], b[3:]
+	case commitments.SimpleCommitmentMode: // [cert_version, ...]
+		decoded.CertVersion, key = b[0], b[1:]
+	case commitments.ArbitrumNitro: // [header_flag, cert_version, ...]
+		var version commitments.CertEncodingCommitment
+		if version, key, err = commitments.DecodeNitroDACert(b); err != nil {
+			return nil, err
+		}
+		decoded.CertVersion = byte(version)
+	}
+	decoded.CertKey, err = server.DecodeVersionedCertKey(commitments.CertEncodingCommitment(decoded.CertVersion), key)
+	if err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// detectCommitmentMode tells commitment modes apart by their prefix bytes. Keccak commitments start
+// with a 0 byte like simple commitments of V0 certs, but never hold a cert.
+func detectCommitmentMode(b []byte) (commitments.CommitmentMode, error) {
+	switch {
+	case b[0] == byte(commitments.GenericCommitmentType) && b[1] == byte(commitments.EigenDACommitmentType):
+		return commitments.OptimismGeneric, nil
+	case b[0] == byte(commitments.CertV0):
+		return commitments.SimpleCommitmentMode, nil
+	default:
+		return "", fmt.Errorf("cannot detect commitment mode from prefix %#x, set the commitment mode", b[:2])
+	}
+}
+
+func ensureHexPrefix(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s
+	}
+	return "0x" + s
+}
+
+func commitmentArg(ctx *cli.Context) (string, error) {
+	if ctx.NArg() != 1 {
+		return "", fmt.Errorf("expected a single hex commitment argument, got %d arguments", ctx.NArg())
+	}
+	return ctx.Args().First(), nil
+}
+
+func decodeCert(ctx *cli.Context) error {
+	arg, err := commitmentArg(ctx)
+	if err != nil {
+		return err
+	}
+	decoded, err := decodeCommitment(arg, ctx.String(commitmentModeFlagName))
+	if err != nil {
+		return err
+	}
+	return printJSON(ctx.App.Writer, decoded)
+}
+
+func verifyCert(ctx *cli.Context) error {
+	arg, err := commitmentArg(ctx)
+	if err != nil {
+		return err
+	}
+	decoded, err := decodeCommitment(arg, ctx.String(commitmentModeFlagName))
+	if err != nil {
+		return err
+	}
+
+	// the blob of the frame of an aggregated blob or of the part of a chunked payload isn't known
+	var encodedBlob []byte
+	if path := ctx.String(blobFlagName); path != "" {
+		if decoded.Frame != nil || decoded.Manifest != nil {
+			return fmt.Errorf("blobs can only be verified against commitments of a single cert, not of an aggregated frame or chunked payload")
+		}
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read blob: %w", err)
+		}
+		encodedBlob, err = codecs.NewIFFTCodec(codecs.NewDefaultBlobCodec()).EncodeBlob(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode blob: %w", err)
+		}
+	}
+
+	v, err := newCertVerifier(ctx, encodedBlob)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*verify.Report, len(decoded.Certs))
+	valid := true
+	for i, cert := range decoded.Certs {
+		reports[i] = v.ReportCert(ctx.Context, cert, encodedBlob)
+		valid = valid && reports[i].Valid
+	}
+
+	switch {
+	case ctx.Bool(jsonFlagName) && len(reports) == 1:
+		err = printJSON(ctx.App.Writer, reports[0])
+	case ctx.Bool(jsonFlagName):
+		err = printJSON(ctx.App.Writer, reports)
+	default:
+		printReports(ctx.App.Writer, reports)
+	}
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return cli.Exit("cert is invalid", 1)
+	}
+	return nil
+}
+
+// newCertVerifier only loads as much of the SRS as the blob needs, and only verifies certs against
+// the service manager when an eth rpc is set
+func newCertVerifier(ctx *cli.Context, encodedBlob []byte) (*verify.Verifier, error) {
+	// the flag value was already validated by its action
+	depth, finalized, _ := verify.ParseConfirmationDepth(ctx.String(confirmationDepthFlagName))
+	cfg := &verify.Config{
+		VerifyCerts:           ctx.String(ethRPCFlagName) != "",
+		RPCURL:                ctx.String(ethRPCFlagName),
+		SvcManagerAddr:        ctx.String(svcManagerAddrFlagName),
+		ReadConfirmationDepth: depth,
+		ReadWaitForFinalized:  finalized,
+	}
+	if cfg.VerifyCerts && cfg.SvcManagerAddr == "" {
+		return nil, fmt.Errorf("the service manager address is required to verify certs against an eth rpc")
+	}
+
+	if encodedBlob != nil {
+		points := uint64((len(encodedBlob) + 31) / 32) // #nosec G115
+		cfg.KzgConfig = &kzg.KzgConfig{
+			G1Path:          ctx.String(g1PathFlagName),
+			G2PowerOf2Path:  ctx.String(g2PowerOf2PathFlagName),
+			CacheDir:        ctx.String(cachePathFlagName),
+			SRSOrder:        uint64(verify.MaxSRSPoints),
+			SRSNumberToLoad: points,
+			NumWorker:       uint64(runtime.GOMAXPROCS(0)), // #nosec G115
+		}
+	}
+
+	l := oplog.NewLogger(ctx.App.ErrWriter, oplog.DefaultCLIConfig())
+	return verify.NewVerifier(cfg, l, metrics.NoopMetrics)
+}
+
+func printReports(w io.Writer, reports []*verify.Report) {
+	for i, r := range reports {
+		if len(reports) > 1 {
+			fmt.Fprintf(w, "part %d:\n", i)
+		}
+		for _, step := range r.Steps {
+			line := fmt.Sprintf("  %-24s %s", step.Name, step.Status)
+			if step.Detail != "" {
+				line += ": " + step.Detail
+			}
+			fmt.Fprintln(w, line)
+			if step.Expected != "" {
+				fmt.Fprintf(w, "    expected: %s\n", step.Expected)
+			}
+			if step.Actual != "" {
+				fmt.Fprintf(w, "    actual:   %s\n", step.Actual)
+			}
+			if step.BlockNumber != 0 {
+				fmt.Fprintf(w, "    block:    %d\n", step.BlockNumber)
+			}
+		}
+		if r.Valid {
+			fmt.Fprintln(w, "valid")
+		} else {
+			fmt.Fprintln(w, "invalid")
+		}
+	}
+}
+
+func printJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
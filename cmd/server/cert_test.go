@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// testCert ... RLP encoded cert whose reference block number tells it apart in decoded output
+func testCert(t *testing.T) []byte {
+	cert, err := rlp.EncodeToBytes(&verify.Certificate{
+		BlobHeader: &disperser.BlobHeader{Commitment: &common.G1Commitment{X: []byte{1}, Y: []byte{2}}},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:         &disperser.BatchHeader{BatchRoot: make([]byte, 32), ReferenceBlockNumber: 1234},
+				SignatoryRecordHash: make([]byte, 32),
+			},
+		},
+	})
+	require.NoError(t, err)
+	return cert
+}
+
+func TestDecodeCommitment(t *testing.T) {
+	cert := hex.EncodeToString(testCert(t))
+
+	tests := []struct {
+		name         string
+		commitment   string
+		mode         string
+		expectedMode commitments.CommitmentMode
+		expectedErr  string
+	}{
+		{"GenericDetected", "0x010000" + cert, detectCommitmentModeString, commitments.OptimismGeneric, ""},
+		{"Generic", "0x010000" + cert, string(commitments.OptimismGeneric), commitments.OptimismGeneric, ""},
+		{"SimpleDetected", "0x00" + cert, detectCommitmentModeString, commitments.SimpleCommitmentMode, ""},
+		{"Simple", "0x00" + cert, string(commitments.SimpleCommitmentMode), commitments.SimpleCommitmentMode, ""},
+		{"Nitro", "0x0100" + cert, string(commitments.ArbitrumNitro), commitments.ArbitrumNitro, ""},
+		{"MissingHexPrefix", "00" + cert, detectCommitmentModeString, commitments.SimpleCommitmentMode, ""},
+		{"UppercaseHexPrefix", "0X00" + cert, detectCommitmentModeString, commitments.SimpleCommitmentMode, ""},
+		{"SurroundingWhitespace", " 0x00" + cert + "\n", detectCommitmentModeString, commitments.SimpleCommitmentMode, ""},
+
+		// nitro certs share the prefix of generic commitments, and are only decoded with their mode set
+		{"NitroDetected", "0x0100" + cert, detectCommitmentModeString, "", "unsupported cert version"},
+		{"NitroUnknownHeaderFlag", "0x0200" + cert, string(commitments.ArbitrumNitro), "", "header flag"},
+		{"Keccak", "0x00" + cert, string(commitments.OptimismKeccak), "", "not a cert"},
+		{"UnknownMode", "0x00" + cert, "unknown", "", "unknown commitment mode"},
+		{"UndetectablePrefix", "0x02" + cert, detectCommitmentModeString, "", "cannot detect commitment mode"},
+		{"BadHex", "0x00zz", detectCommitmentModeString, "", "failed to decode hex commitment"},
+		{"OddLengthHex", "0x000", detectCommitmentModeString, "", "failed to decode hex commitment"},
+		{"UnknownVersionSimple", "0x07" + cert, string(commitments.SimpleCommitmentMode), "", "unsupported cert version 7"},
+		{"UnknownVersionGeneric", "0x010007" + cert, detectCommitmentModeString, "", "unsupported cert version 7"},
+		{"UnknownVersionNitro", "0x0107" + cert, string(commitments.ArbitrumNitro), "", "unsupported cert version 7"},
+		{"Truncated", "0x0100", detectCommitmentModeString, "", "commitment is too short"},
+		{"TruncatedCert", "0x00" + cert[:len(cert)/2], detectCommitmentModeString, "", "failed to decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeCommitment(tt.commitment, tt.mode)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedMode, decoded.CommitmentMode)
+			require.Equal(t, byte(commitments.CertV0), decoded.CertVersion)
+			require.Len(t, decoded.Certs, 1)
+			require.Equal(t, uint32(1234), decoded.Cert.BlobVerificationProof.BatchMetadata.BatchHeader.ReferenceBlockNumber)
+		})
+	}
+}
+
+func TestDetectCommitmentMode(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected commitments.CommitmentMode
+	}{
+		{[]byte{1, 0, 0}, commitments.OptimismGeneric},
+		{[]byte{0, 1, 2}, commitments.SimpleCommitmentMode},
+		{[]byte{1, 1, 0}, ""},
+		{[]byte{2, 0, 0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%x", tt.prefix), func(t *testing.T) {
+			mode, err := detectCommitmentMode(tt.prefix)
+			if tt.expected == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestEnsureHexPrefix(t *testing.T) {
+	require.Equal(t, "0xabcd", ensureHexPrefix("abcd"))
+	require.Equal(t, "0xabcd", ensureHexPrefix("0xabcd"))
+	require.Equal(t, "0Xabcd", ensureHexPrefix("0Xabcd"))
+	require.Equal(t, "0xabcd", ensureHexPrefix("  abcd\n"))
+	require.Equal(t, "0x", ensureHexPrefix(""))
+}
+
+// runCertCommand runs the cert subcommand with the args, and returns what it printed
+func runCertCommand(t *testing.T, args ...string) (string, error) {
+	var out bytes.Buffer
+	app := &cli.App{
+		Commands:  []*cli.Command{certCommand()},
+		Writer:    &out,
+		ErrWriter: &bytes.Buffer{},
+		// keep cli.Exit errors from exiting the test binary
+		ExitErrHandler: func(*cli.Context, error) {},
+	}
+	err := app.Run(append([]string{"eigenda-proxy", "cert"}, args...))
+	return out.String(), err
+}
+
+func TestCertCommand(t *testing.T) {
+	commitment := "0x00" + hex.EncodeToString(testCert(t))
+
+	t.Run("Decode", func(t *testing.T) {
+		out, err := runCertCommand(t, "decode", commitment)
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+		require.Equal(t, string(commitments.SimpleCommitmentMode), decoded["commitment_mode"])
+		require.Contains(t, decoded, "cert")
+	})
+
+	t.Run("DecodeNitro", func(t *testing.T) {
+		out, err := runCertCommand(t, "decode", "--commitment-mode", string(commitments.ArbitrumNitro),
+			"0x0100"+commitment[4:])
+		require.NoError(t, err)
+		require.Contains(t, out, `"commitment_mode": "arbitrum_nitro"`)
+	})
+
+	t.Run("DecodeArgs", func(t *testing.T) {
+		_, err := runCertCommand(t, "decode")
+		require.ErrorContains(t, err, "expected a single hex commitment argument")
+		_, err = runCertCommand(t, "decode", commitment, commitment)
+		require.ErrorContains(t, err, "expected a single hex commitment argument")
+	})
+
+	// the merkle inclusion proof of the test cert doesn't hold, and only that step runs without a blob
+	// or an eth rpc
+	t.Run("Verify", func(t *testing.T) {
+		out, err := runCertCommand(t, "verify", "--json", commitment)
+		require.ErrorContains(t, err, "cert is invalid")
+
+		var report verify.Report
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		require.False(t, report.Valid)
+		require.NotEmpty(t, report.Steps)
+	})
+
+	t.Run("VerifyRequiresSvcManager", func(t *testing.T) {
+		_, err := runCertCommand(t, "verify", "--eth-rpc", "http://localhost:8545", commitment)
+		require.ErrorContains(t, err, "service manager address is required")
+	})
+}
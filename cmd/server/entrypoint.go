@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/Layr-Labs/eigenda-proxy/flags"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
 	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/server"
 	"github.com/ethereum/go-ethereum/log"
@@ -32,12 +33,17 @@ func StartProxySvr(cliCtx *cli.Context) error {
 	ctx, ctxCancel := context.WithCancel(cliCtx.Context)
 	defer ctxCancel()
 
-	daRouter, err := server.LoadStoreRouter(ctx, cfg, log)
+	m := metrics.NewMetrics("default")
+	daRouter, err := server.LoadStoreRouter(ctx, cfg, log, m)
 	if err != nil {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
-	m := metrics.NewMetrics("default")
-	server := server.NewServer(cliCtx.String(flags.ListenAddrFlagName), cliCtx.Int(flags.PortFlagName), daRouter, log, m)
+	jobStore, err := jobs.NewStore(cfg.EigenDAConfig.JobsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create job store: %w", err)
+	}
+	server := server.NewServerWithJobStore(cliCtx.String(flags.ListenAddrFlagName), cliCtx.Int(flags.PortFlagName), daRouter, jobStore,
+		cfg.EigenDAConfig.JobsConfig, cfg.EigenDAConfig.SecurityOverrides, cfg.EigenDAConfig.CertRecencyWindow, log, m)
 
 	if err := server.Start(); err != nil {
 		return fmt.Errorf("failed to start the DA server: %w", err)
@@ -78,6 +84,11 @@ func prettyPrintConfig(cliCtx *cli.Context, log log.Logger) error {
 	cfg := server.ReadCLIConfig(cliCtx)
 	cfg.EigenDAConfig.EdaClientConfig.SignerPrivateKeyHex = "HIDDEN"
 	cfg.EigenDAConfig.VerifierConfig.RPCURL = "HIDDEN"
+	for i := range cfg.EigenDAConfig.VerifierConfig.AdditionalRPCURLs {
+		cfg.EigenDAConfig.VerifierConfig.AdditionalRPCURLs[i] = "HIDDEN"
+	}
+	cfg.EigenDAConfig.MemstoreConfig.RedisPassword = "HIDDEN"
+	cfg.EigenDAConfig.JobsConfig.RedisPassword = "HIDDEN"
 
 	configJSON, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
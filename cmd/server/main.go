@@ -37,6 +37,7 @@ func main() {
 			Name:        "doc",
 			Subcommands: doc.NewSubcommands(metrics.NewMetrics("default")),
 		},
+		certCommand(),
 	}
 
 	// load env file (if applicable)
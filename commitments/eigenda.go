@@ -37,3 +37,8 @@ func (c CertCommitmentV0) CommitmentType() CertEncodingCommitment {
 func (c CertCommitmentV0) Encode() []byte {
 	return append([]byte{byte(CertV0)}, c...)
 }
+
+// EncodeCertCommitment adds the cert version prefix to a cert of any version.
+func EncodeCertCommitment(cert []byte, version CertEncodingCommitment) []byte {
+	return append([]byte{byte(version)}, cert...)
+}
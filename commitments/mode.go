@@ -17,6 +17,8 @@ const (
 	OptimismKeccak       CommitmentMode = "optimism_keccak256"
 	OptimismGeneric      CommitmentMode = "optimism_generic"
 	SimpleCommitmentMode CommitmentMode = "simple"
+	// ArbitrumNitro ... DA certificates of the Arbitrum Nitro external DA provider API
+	ArbitrumNitro CommitmentMode = "arbitrum_nitro"
 )
 
 func StringToCommitmentMode(s string) (CommitmentMode, error) {
@@ -27,6 +29,8 @@ func StringToCommitmentMode(s string) (CommitmentMode, error) {
 		return OptimismGeneric, nil
 	case string(SimpleCommitmentMode):
 		return SimpleCommitmentMode, nil
+	case string(ArbitrumNitro):
+		return ArbitrumNitro, nil
 	default:
 		return "", fmt.Errorf("unknown commitment mode: %s", s)
 	}
@@ -42,7 +46,12 @@ func StringToDecodedCommitment(key string, c CommitmentMode) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	return DecodeCommitment(b, c)
+}
 
+// DecodeCommitment strips the prefixes of the commitment mode from a commitment, which leaves the key
+// of the storage backend
+func DecodeCommitment(b []byte, c CommitmentMode) ([]byte, error) {
 	if len(b) < 3 {
 		return nil, fmt.Errorf("commitment is too short")
 	}
@@ -57,24 +66,33 @@ func StringToDecodedCommitment(key string, c CommitmentMode) ([]byte, error) {
 	case SimpleCommitmentMode: // [cert_version, ...]
 		return b[1:], nil
 
+	case ArbitrumNitro: // [header_flag, cert_version, ...]
+		_, cert, err := DecodeNitroDACert(b)
+		return cert, err
+
 	default:
 		return nil, fmt.Errorf("unknown commitment type")
 	}
 }
 
-func EncodeCommitment(b []byte, c CommitmentMode) ([]byte, error) {
+// EncodeCommitment adds the prefixes of the commitment mode to a key. Certs are prefixed with their cert
+// version, which keccak256 commitments don't have.
+func EncodeCommitment(b []byte, c CommitmentMode, v CertEncodingCommitment) ([]byte, error) {
 	switch c {
 	case OptimismKeccak:
 		return Keccak256Commitment(b).Encode(), nil
 
 	case OptimismGeneric:
-		certCommit := NewV0CertCommitment(b).Encode()
+		certCommit := EncodeCertCommitment(b, v)
 		svcCommit := EigenDASvcCommitment(certCommit).Encode()
 		altDACommit := NewGenericCommitment(svcCommit).Encode()
 		return altDACommit, nil
 
 	case SimpleCommitmentMode:
-		return NewV0CertCommitment(b).Encode(), nil
+		return EncodeCertCommitment(b, v), nil
+
+	case ArbitrumNitro:
+		return append([]byte{NitroDACertHeaderFlag}, EncodeCertCommitment(b, v)...), nil
 	}
 
 	return nil, fmt.Errorf("unknown commitment mode")
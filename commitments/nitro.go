@@ -0,0 +1,23 @@
+package commitments
+
+import "fmt"
+
+// NitroDACertHeaderFlag ... header byte of the DA certificates that Arbitrum Nitro posts in sequencer
+// messages in place of the batch data
+const NitroDACertHeaderFlag byte = 0x01
+
+// NitroSequencerMsgHeaderLen ... length of the header of Nitro sequencer messages that precedes the
+// batch data, which holds the min and max timestamps and L1 blocks and the number of delayed messages read
+const NitroSequencerMsgHeaderLen = 40
+
+// DecodeNitroDACert ... splits a DA certificate of the form [header_flag, cert_version, cert...] into its
+// cert version and cert
+func DecodeNitroDACert(b []byte) (CertEncodingCommitment, []byte, error) {
+	if len(b) < 3 {
+		return 0, nil, fmt.Errorf("DA certificate is too short")
+	}
+	if b[0] != NitroDACertHeaderFlag {
+		return 0, nil, fmt.Errorf("unknown DA certificate header flag %#x", b[0])
+	}
+	return CertEncodingCommitment(b[1]), b[2:], nil
+}
@@ -1,17 +1,29 @@
 package e2e_test
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Layr-Labs/eigenda-proxy/client"
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
 
 	"github.com/Layr-Labs/eigenda-proxy/e2e"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/server"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	altda "github.com/ethereum-optimism/optimism/op-alt-da"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -250,6 +262,339 @@ func TestProxyClient(t *testing.T) {
 	require.Equal(t, testPreimage, preimage)
 }
 
+func TestProxyClientWithMockDisperser(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(false)
+	testCfg.UseMockDisperser = true
+	tsConfig := e2e.TestSuiteConfig(t, testCfg)
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+
+	testPreimage := []byte(e2e.RandString(100))
+
+	t.Log("Setting input data on proxy server...")
+	blobInfo, err := daClient.SetData(ts.Ctx, testPreimage)
+	require.NoError(t, err)
+
+	// completed dispersals are removed from the journal
+	dispersalJournal, err := journal.New(tsConfig.EigenDAConfig.JournalConfig.Dir)
+	require.NoError(t, err)
+	entries, err := dispersalJournal.Entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	t.Log("Getting input data from proxy server...")
+	preimage, err := daClient.GetData(ts.Ctx, blobInfo)
+	require.NoError(t, err)
+	require.Equal(t, testPreimage, preimage)
+}
+
+func TestProxyClientWithSimulatedCertVerification(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(true)
+	testCfg.UseSimulatedCertVerification = true
+	tsConfig := e2e.TestSuiteConfig(t, testCfg)
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+
+	testPreimage := []byte(e2e.RandString(100))
+
+	t.Log("Setting input data on proxy server...")
+	blobInfo, err := daClient.SetData(ts.Ctx, testPreimage)
+	require.NoError(t, err)
+
+	t.Log("Getting input data from proxy server once the cert is confirmation depth deep...")
+	require.Eventually(t, func() bool {
+		preimage, err := daClient.GetData(ts.Ctx, blobInfo)
+		return err == nil && bytes.Equal(testPreimage, preimage)
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestProxyClientVerifyWithSimulatedCertVerification(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(true)
+	testCfg.UseSimulatedCertVerification = true
+	tsConfig := e2e.TestSuiteConfig(t, testCfg)
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+
+	testPreimage := []byte(e2e.RandString(100))
+
+	t.Log("Setting input data on proxy server...")
+	blobInfo, err := daClient.SetData(ts.Ctx, testPreimage)
+	require.NoError(t, err)
+
+	t.Log("Verifying the cert and payload once the cert is confirmation depth deep...")
+	require.Eventually(t, func() bool {
+		reports, err := daClient.VerifyData(ts.Ctx, blobInfo, testPreimage)
+		return err == nil && reports.Valid
+	}, 10*time.Second, 100*time.Millisecond)
+
+	reports, err := daClient.VerifyData(ts.Ctx, blobInfo, testPreimage)
+	require.NoError(t, err)
+	require.Len(t, reports.Reports, 1)
+	for _, step := range reports.Reports[0].Steps {
+		require.Equal(t, verify.StepPassed, step.Status, step.Name)
+		if step.Name != verify.SecurityParamsStep {
+			require.Equal(t, step.Expected, step.Actual, step.Name)
+		}
+	}
+	require.NotZero(t, reports.Reports[0].Steps[2].BlockNumber)
+
+	t.Log("Verifying another payload against the cert...")
+	reports, err = daClient.VerifyData(ts.Ctx, blobInfo, []byte(e2e.RandString(100)))
+	require.NoError(t, err)
+	require.False(t, reports.Valid)
+	require.Equal(t, verify.KzgCommitmentStep, reports.Reports[0].Steps[1].Name)
+	require.Equal(t, verify.StepFailed, reports.Reports[0].Steps[1].Status)
+
+	t.Log("Verifying a malformed cert...")
+	_, err = daClient.VerifyData(ts.Ctx, blobInfo[:len(blobInfo)-1], nil)
+	require.ErrorContains(t, err, "code=400")
+}
+
+// TestNitroDAProviderWithMockDisperser stores a batch and recovers it through the Arbitrum Nitro external
+// DA provider API, and reads the returned DA certificate through the REST routes
+func TestNitroDAProviderWithMockDisperser(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(false)
+	testCfg.UseMockDisperser = true
+	ts, kill := e2e.CreateTestSuite(t, e2e.TestSuiteConfig(t, testCfg))
+	defer kill()
+
+	rpcClient, err := rpc.DialContext(ts.Ctx, ts.Address()+server.RPCRoute)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+
+	batch := []byte(e2e.RandString(100))
+
+	t.Log("Storing batch through the nitro DA provider API...")
+	var stored server.StoreResult
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &stored, "daprovider_store", hexutil.Bytes(batch), hexutil.Uint64(0)))
+	require.Equal(t, commitments.NitroDACertHeaderFlag, stored.SerializedDACert[0])
+
+	t.Log("Recovering batch from a sequencer message holding the DA certificate...")
+	sequencerMsg := append(make([]byte, commitments.NitroSequencerMsgHeaderLen), stored.SerializedDACert...)
+	var recovered server.PayloadResult
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &recovered, "daprovider_recoverPayload",
+		hexutil.Uint64(1), common.Hash{}, hexutil.Bytes(sequencerMsg)))
+	require.Equal(t, batch, []byte(recovered.Payload))
+
+	resp, err := http.Get(fmt.Sprintf("%s/get/%s?commitment_mode=%s", ts.Address(), stored.SerializedDACert, commitments.ArbitrumNitro))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, batch, body)
+}
+
+// TestDAJSONRPCWithMockDisperser puts and reads a payload through the da JSON-RPC API
+func TestDAJSONRPCWithMockDisperser(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(false)
+	testCfg.UseMockDisperser = true
+	ts, kill := e2e.CreateTestSuite(t, e2e.TestSuiteConfig(t, testCfg))
+	defer kill()
+
+	rpcClient, err := rpc.DialContext(ts.Ctx, ts.Address()+server.RPCRoute)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+
+	payload := []byte(e2e.RandString(100))
+	opts := server.GetOptions{Encoding: server.Base64PayloadEncoding}
+
+	t.Log("Putting payload through the da API...")
+	var put server.PutResult
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &put, "da_put", base64.StdEncoding.EncodeToString(payload),
+		server.PutOptions{Encoding: server.Base64PayloadEncoding}))
+
+	t.Log("Getting payload through the da API...")
+	var batch []*server.BatchGetResult
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &batch, "da_batchGet", []hexutil.Bytes{put.Commitment, put.Commitment}, opts))
+	require.Len(t, batch, 2)
+	for _, res := range batch {
+		require.Nil(t, res.Error)
+		require.Equal(t, base64.StdEncoding.EncodeToString(payload), res.Payload)
+	}
+
+	var info server.CertInfoResult
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &info, "da_getCertInfo", put.Commitment))
+	require.NotNil(t, info.Cert)
+
+	var report verify.CertReports
+	require.NoError(t, rpcClient.CallContext(ts.Ctx, &report, "da_verify", put.Commitment,
+		base64.StdEncoding.EncodeToString(payload), opts))
+	require.True(t, report.Valid)
+}
+
+func TestProxyClientErrorResponses(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	ts, kill := e2e.CreateTestSuite(t, e2e.TestSuiteConfig(t, e2e.TestConfig(useMemory())))
+	defer kill()
+
+	daClient := client.New(&client.Config{URL: ts.Address()})
+
+	blobInfo, err := daClient.SetData(ts.Ctx, []byte(e2e.RandString(100)))
+	require.NoError(t, err)
+
+	// the commitment is the cert version byte followed by the RLP encoded cert
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(blobInfo[1:], &cert))
+	tamper := func(f func(cert *verify.Certificate)) []byte {
+		var tampered verify.Certificate
+		require.NoError(t, rlp.DecodeBytes(blobInfo[1:], &tampered))
+		f(&tampered)
+		b, err := rlp.EncodeToBytes(&tampered)
+		require.NoError(t, err)
+		return append([]byte{blobInfo[0]}, b...)
+	}
+
+	t.Log("Getting data of a cert that was never stored...")
+	_, err = daClient.GetData(ts.Ctx, tamper(func(cert *verify.Certificate) {
+		cert.BlobVerificationProof.InclusionProof = bytes.Repeat([]byte{1}, 32)
+	}))
+	var respErr *client.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	require.Equal(t, "not_found", respErr.Code, respErr.Message)
+
+	t.Log("Getting data of a tampered cert...")
+	_, err = daClient.GetData(ts.Ctx, tamper(func(cert *verify.Certificate) {
+		cert.BlobVerificationProof.BatchId++
+	}))
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusUnprocessableEntity, respErr.StatusCode)
+	require.Equal(t, "invalid_cert", respErr.Code)
+}
+
+func TestProxyClientAsyncDispersal(t *testing.T) {
+	if !runIntegrationTests && !runTestnetIntegrationTests {
+		t.Skip("Skipping test as INTEGRATION or TESTNET env var not set")
+	}
+
+	t.Parallel()
+
+	tsConfig := e2e.TestSuiteConfig(t, e2e.TestConfig(useMemory()))
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+
+	testPreimage := []byte(e2e.RandString(100))
+
+	t.Log("Queueing input data for dispersal on proxy server...")
+	job, err := daClient.SetDataAsync(ts.Ctx, testPreimage)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StatusQueued, job.Status)
+
+	t.Log("Polling dispersal job until done...")
+	require.Eventually(t, func() bool {
+		job, err = daClient.GetPutStatus(ts.Ctx, job.ID)
+		require.NoError(t, err)
+		return job.Done()
+	}, 30*time.Minute, time.Second)
+	require.NotEqual(t, jobs.StatusFailed, job.Status, job.Error)
+
+	t.Log("Getting input data from proxy server...")
+	preimage, err := daClient.GetData(ts.Ctx, job.Commitment)
+	require.NoError(t, err)
+	require.Equal(t, testPreimage, preimage)
+}
+
+func TestProxyClientWithAggregation(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(true)
+	testCfg.UseAggregation = true
+	tsConfig := e2e.TestSuiteConfig(t, testCfg)
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+
+	t.Log("Setting input data on proxy server concurrently...")
+	preimages := make([][]byte, 4)
+	commitments := make([][]byte, len(preimages))
+	errs := make(chan error, len(preimages))
+	for i := range preimages {
+		preimages[i] = []byte(e2e.RandString(100))
+		go func(i int) {
+			var err error
+			commitments[i], err = daClient.SetData(ts.Ctx, preimages[i])
+			errs <- err
+		}(i)
+	}
+	for range preimages {
+		require.NoError(t, <-errs)
+	}
+
+	// all payloads were packed into a single blob
+	require.Equal(t, 1, ts.Server.GetEigenDAStats().Entries)
+
+	t.Log("Getting input data from proxy server...")
+	for i, commitment := range commitments {
+		preimage, err := daClient.GetData(ts.Ctx, commitment)
+		require.NoError(t, err)
+		require.Equal(t, preimages[i], preimage)
+	}
+}
+
 func TestProxyServerWithLargeBlob(t *testing.T) {
 	if !runIntegrationTests && !runTestnetIntegrationTests {
 		t.Skip("Skipping test as INTEGRATION or TESTNET env var not set")
@@ -278,6 +623,39 @@ func TestProxyServerWithLargeBlob(t *testing.T) {
 	require.Equal(t, testPreimage, preimage)
 }
 
+func TestProxyServerWithChunkedBlob(t *testing.T) {
+	if !runIntegrationTests || runTestnetIntegrationTests {
+		t.Skip("Skipping test as TESTNET env set or INTEGRATION var not set")
+	}
+
+	t.Parallel()
+
+	testCfg := e2e.TestConfig(true)
+	testCfg.UseChunking = true
+	tsConfig := e2e.TestSuiteConfig(t, testCfg)
+	ts, kill := e2e.CreateTestSuite(t, tsConfig)
+	defer kill()
+
+	cfg := &client.Config{
+		URL: ts.Address(),
+	}
+	daClient := client.New(cfg)
+	//  20MB blob, larger than the 16MiB max blob size
+	testPreimage := []byte(e2e.RandString(20_000_000))
+
+	t.Log("Setting input data on proxy server...")
+	commitment, err := daClient.SetData(ts.Ctx, testPreimage)
+	require.NoError(t, err)
+
+	// the payload was split across two blobs
+	require.Equal(t, 2, ts.Server.GetEigenDAStats().Entries)
+
+	t.Log("Getting input data from proxy server...")
+	preimage, err := daClient.GetData(ts.Ctx, commitment)
+	require.NoError(t, err)
+	require.Equal(t, testPreimage, preimage)
+}
+
 func TestProxyServerWithOversizedBlob(t *testing.T) {
 	if !runIntegrationTests && !runTestnetIntegrationTests {
 		t.Skip("Skipping test as INTEGRATION or TESTNET env var not set")
@@ -3,6 +3,7 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
 	"testing"
@@ -10,6 +11,9 @@ import (
 
 	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/server"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/mockdisperser"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/memstore"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/redis"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/s3"
@@ -35,11 +39,21 @@ const (
 	svcName    = "eigenda_proxy"
 	host       = "127.0.0.1"
 	holeskyDA  = "disperser-holesky.eigenda.xyz:443"
+
+	simulatedConfirmationDepth = 2
 )
 
 type Cfg struct {
 	UseMemory  bool
 	Expiration time.Duration
+	// disperse against a local mock disperser instead of memstore or holesky
+	UseMockDisperser bool
+	// verify memstore certs against a simulated Ethereum chain
+	UseSimulatedCertVerification bool
+	// pack small payloads into shared blobs
+	UseAggregation bool
+	// split payloads larger than the max blob size across several blobs
+	UseChunking bool
 	// at most one of the below options should be true
 	UseKeccak256ModeS3 bool
 	UseS3Caching       bool
@@ -49,12 +63,16 @@ type Cfg struct {
 
 func TestConfig(useMemory bool) *Cfg {
 	return &Cfg{
-		UseMemory:          useMemory,
-		Expiration:         14 * 24 * time.Hour,
-		UseKeccak256ModeS3: false,
-		UseS3Caching:       false,
-		UseRedisCaching:    false,
-		UseS3Fallback:      false,
+		UseMemory:                    useMemory,
+		Expiration:                   14 * 24 * time.Hour,
+		UseMockDisperser:             false,
+		UseSimulatedCertVerification: false,
+		UseAggregation:               false,
+		UseChunking:                  false,
+		UseKeccak256ModeS3:           false,
+		UseS3Caching:                 false,
+		UseRedisCaching:              false,
+		UseS3Fallback:                false,
 	}
 }
 
@@ -95,18 +113,18 @@ func createS3Config(eigendaCfg server.Config) server.CLIConfig {
 func TestSuiteConfig(t *testing.T, testCfg *Cfg) server.CLIConfig {
 	// load signer key from environment
 	pk := os.Getenv(privateKey)
-	if pk == "" && !testCfg.UseMemory {
+	if pk == "" && !testCfg.UseMemory && !testCfg.UseMockDisperser {
 		t.Fatal("SIGNER_PRIVATE_KEY environment variable not set")
 	}
 
 	// load node url from environment
 	ethRPC := os.Getenv(ethRPC)
-	if ethRPC == "" && !testCfg.UseMemory {
+	if ethRPC == "" && !testCfg.UseMemory && !testCfg.UseMockDisperser {
 		t.Fatal("ETHEREUM_RPC environment variable is not set")
 	}
 
 	var pollInterval time.Duration
-	if testCfg.UseMemory {
+	if testCfg.UseMemory || testCfg.UseMockDisperser {
 		pollInterval = time.Second * 1
 	} else {
 		pollInterval = time.Minute * 1
@@ -143,10 +161,40 @@ func TestSuiteConfig(t *testing.T, testCfg *Cfg) server.CLIConfig {
 		},
 	}
 
-	if testCfg.UseMemory {
+	if testCfg.UseMemory || testCfg.UseMockDisperser {
 		eigendaCfg.EdaClientConfig.SignerPrivateKeyHex = "0000000000000000000100000000000000000000000000000000000000000000"
 	}
 
+	if testCfg.UseMemory && testCfg.UseSimulatedCertVerification {
+		eigendaCfg.VerifierConfig.VerifyCerts = true
+		eigendaCfg.VerifierConfig.EthConfirmationDepth = simulatedConfirmationDepth
+		eigendaCfg.VerifierConfig.ReadConfirmationDepth = simulatedConfirmationDepth
+		eigendaCfg.MemstoreConfig.SimulatedBlockTime = 100 * time.Millisecond
+	}
+
+	if testCfg.UseAggregation {
+		eigendaCfg.AggregatorConfig = aggregator.Config{
+			Enabled:  true,
+			Window:   500 * time.Millisecond,
+			MaxBytes: 64 * 1024,
+		}
+	}
+
+	if testCfg.UseChunking {
+		eigendaCfg.ChunkerConfig = chunker.Config{
+			Enabled:   true,
+			MaxChunks: 4,
+		}
+	}
+
+	if testCfg.UseMockDisperser {
+		eigendaCfg.MemstoreEnabled = false
+		eigendaCfg.EdaClientConfig.RPC = startMockDisperser(t, eigendaCfg.VerifierConfig, maxBlobLengthBytes)
+		eigendaCfg.EdaClientConfig.DisableTLS = true
+		eigendaCfg.JournalConfig.Dir = t.TempDir()
+		eigendaCfg.JournalConfig.Retention = time.Hour
+	}
+
 	var cfg server.CLIConfig
 	switch {
 	case testCfg.UseKeccak256ModeS3:
@@ -174,6 +222,21 @@ func TestSuiteConfig(t *testing.T, testCfg *Cfg) server.CLIConfig {
 	return cfg
 }
 
+// startMockDisperser runs a local mock disperser for the duration of the test and returns its endpoint
+func startMockDisperser(t *testing.T, vCfg verify.Config, maxBlobLengthBytes uint64) string {
+	verifier, err := verify.NewVerifier(&vCfg, nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	cfg := mockdisperser.DefaultConfig()
+	cfg.MaxBlobSizeBytes = maxBlobLengthBytes
+
+	disperser := mockdisperser.New(cfg, verifier, log.New("role", "mock_disperser"))
+	require.NoError(t, disperser.Start(net.JoinHostPort(host, "0")))
+	t.Cleanup(disperser.Stop)
+
+	return disperser.Endpoint()
+}
+
 type TestSuite struct {
 	Ctx    context.Context
 	Log    log.Logger
@@ -192,6 +255,7 @@ func CreateTestSuite(t *testing.T, testSuiteCfg server.CLIConfig) (TestSuite, fu
 		ctx,
 		testSuiteCfg,
 		log,
+		metrics.NoopMetrics,
 	)
 	require.NoError(t, err)
 	server := server.NewServer(host, 0, store, log, metrics.NoopMetrics)
@@ -1,7 +1,18 @@
 package flags
 
 import (
+	"fmt"
+	"math"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
 	"github.com/Layr-Labs/eigenda-proxy/flags/eigendaflags"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/signer"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/memstore"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/redis"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/s3"
@@ -21,6 +32,15 @@ const (
 	S3Category                 = "S3 Cache/Fallback"
 	VerifierCategory           = "KZG and Cert Verifier"
 	VerifierDeprecatedCategory = "DEPRECATED VERIFIER FLAGS -- THESE WILL BE REMOVED IN V2.0.0"
+	JobsCategory               = "Async Dispersal Jobs"
+	JournalCategory            = "EigenDA Dispersal Journal"
+	RetryCategory              = "EigenDA Dispersal Retries"
+	DisconnectCategory         = "EigenDA Client Disconnects"
+	EndpointsCategory          = "EigenDA Disperser Endpoints"
+	SignerCategory             = "EigenDA Disperser Signer"
+	AggregationCategory        = "Small Payload Aggregation"
+	ChunkingCategory           = "Large Payload Chunking"
+	SecurityOverridesCategory  = "Per-Request Security Overrides"
 )
 
 const (
@@ -30,6 +50,15 @@ const (
 	// routing flags
 	FallbackTargetsFlagName = "routing.fallback-targets"
 	CacheTargetsFlagName    = "routing.cache-targets"
+	PutCertVersionFlagName  = "routing.put-cert-version"
+
+	// rollup derivation flags
+	CertRecencyWindowFlagName = "cert-recency-window"
+
+	// per-request security override flags
+	SecurityOverridesAllowedQuorumsFlagName           = "security-overrides.allowed-quorums"
+	SecurityOverridesMaxAdversaryThresholdFlagName    = "security-overrides.max-adversary-threshold"
+	SecurityOverridesMaxConfirmationThresholdFlagName = "security-overrides.max-confirmation-threshold"
 )
 
 const EnvVarPrefix = "EIGENDA_PROXY"
@@ -65,6 +94,55 @@ func CLIFlags() []cli.Flag {
 			Value:   cli.NewStringSlice(),
 			EnvVars: prefixEnvVars("CACHE_TARGETS"),
 		},
+		&cli.UintFlag{
+			Name:    PutCertVersionFlagName,
+			Usage:   "Cert version of the commitments returned for puts. Gets are served for every supported cert version.",
+			Value:   uint(commitments.CertV0),
+			EnvVars: prefixEnvVars("PUT_CERT_VERSION"),
+			Action: func(_ *cli.Context, version uint) error {
+				if version > math.MaxUint8 {
+					return fmt.Errorf("cert version %d is out of range", version)
+				}
+				return nil
+			},
+		},
+		&cli.Uint64Flag{
+			Name: CertRecencyWindowFlagName,
+			Usage: "Max number of L1 blocks between the reference block of a cert and the L1 block it was included in. " +
+				"Gets with the l1_inclusion_block query param of certs outside of the window are rejected with status 418, " +
+				"so that rollup derivation drops their batch. 0 disables the check.",
+			Value:   0,
+			EnvVars: prefixEnvVars("CERT_RECENCY_WINDOW"),
+		},
+		&cli.UintSliceFlag{
+			Name:     SecurityOverridesAllowedQuorumsFlagName,
+			Usage:    "Quorum IDs that put requests may disperse their blob to, in addition to the custom quorum IDs.",
+			Value:    cli.NewUintSlice(),
+			EnvVars:  prefixEnvVars("SECURITY_OVERRIDES_ALLOWED_QUORUMS"),
+			Category: SecurityOverridesCategory,
+			Action: func(_ *cli.Context, ids []uint) error {
+				for _, id := range ids {
+					if id > math.MaxUint8 {
+						return fmt.Errorf("invalid quorum ID %d", id)
+					}
+				}
+				return nil
+			},
+		},
+		&cli.UintFlag{
+			Name:     SecurityOverridesMaxAdversaryThresholdFlagName,
+			Usage:    "Highest adversary threshold percentage that put requests may require for every quorum of their blob. 0 disallows the override.",
+			Value:    0,
+			EnvVars:  prefixEnvVars("SECURITY_OVERRIDES_MAX_ADVERSARY_THRESHOLD"),
+			Category: SecurityOverridesCategory,
+		},
+		&cli.UintFlag{
+			Name:     SecurityOverridesMaxConfirmationThresholdFlagName,
+			Usage:    "Highest confirmation threshold percentage (signed stake) that put requests may require for every quorum of their blob. 0 disallows the override.",
+			Value:    0,
+			EnvVars:  prefixEnvVars("SECURITY_OVERRIDES_MAX_CONFIRMATION_THRESHOLD"),
+			Category: SecurityOverridesCategory,
+		},
 	}
 
 	return flags
@@ -84,4 +162,12 @@ func init() {
 	Flags = append(Flags, memstore.CLIFlags(EnvVarPrefix, MemstoreFlagsCategory)...)
 	Flags = append(Flags, verify.CLIFlags(EnvVarPrefix, VerifierCategory)...)
 	Flags = append(Flags, verify.DeprecatedCLIFlags(EnvVarPrefix, VerifierDeprecatedCategory)...)
+	Flags = append(Flags, jobs.CLIFlags(EnvVarPrefix, JobsCategory)...)
+	Flags = append(Flags, journal.CLIFlags(EnvVarPrefix, JournalCategory)...)
+	Flags = append(Flags, eigenda.CLIFlags(EnvVarPrefix, RetryCategory)...)
+	Flags = append(Flags, eigenda.DisconnectCLIFlags(EnvVarPrefix, DisconnectCategory)...)
+	Flags = append(Flags, endpoints.CLIFlags(EnvVarPrefix, EndpointsCategory)...)
+	Flags = append(Flags, signer.CLIFlags(EnvVarPrefix, SignerCategory)...)
+	Flags = append(Flags, aggregator.CLIFlags(EnvVarPrefix, AggregationCategory)...)
+	Flags = append(Flags, chunker.CLIFlags(EnvVarPrefix, ChunkingCategory)...)
 }
@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	BackendFlagName       = withFlagPrefix("backend")
+	ExpirationFlagName    = withFlagPrefix("expiration")
+	WorkersFlagName       = withFlagPrefix("workers")
+	QueueSizeFlagName     = withFlagPrefix("queue-size")
+	RedisEndpointFlagName = withFlagPrefix("redis-endpoint")
+	RedisPasswordFlagName = withFlagPrefix("redis-password")
+	RedisDBFlagName       = withFlagPrefix("redis-db")
+)
+
+func withFlagPrefix(s string) string {
+	return "jobs." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_JOBS_" + s}
+}
+
+// CLIFlags ... used for asynchronous dispersal job store configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     BackendFlagName,
+			Usage:    "Where asynchronous dispersal jobs are kept, options are [memory, redis]. Use redis to let clients poll jobs through any proxy replica.",
+			Value:    string(MemoryBackend),
+			EnvVars:  withEnvPrefix(envPrefix, "BACKEND"),
+			Category: category,
+			Action: func(_ *cli.Context, s string) error {
+				_, err := StringToBackendType(s)
+				return err
+			},
+		},
+		&cli.DurationFlag{
+			Name:     ExpirationFlagName,
+			Usage:    "Duration that an asynchronous dispersal job is kept after its last update.",
+			Value:    1 * time.Hour,
+			EnvVars:  withEnvPrefix(envPrefix, "EXPIRATION"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     WorkersFlagName,
+			Usage:    "Number of asynchronous dispersal jobs run concurrently.",
+			Value:    8,
+			EnvVars:  withEnvPrefix(envPrefix, "WORKERS"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     QueueSizeFlagName,
+			Usage:    "Max number of asynchronous dispersal jobs waiting for a worker. Async puts are rejected with a 503 once it's reached.",
+			Value:    100,
+			EnvVars:  withEnvPrefix(envPrefix, "QUEUE_SIZE"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     RedisEndpointFlagName,
+			Usage:    "Redis endpoint used when the job store backend is redis.",
+			EnvVars:  withEnvPrefix(envPrefix, "REDIS_ENDPOINT"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     RedisPasswordFlagName,
+			Usage:    "Redis password used when the job store backend is redis.",
+			EnvVars:  withEnvPrefix(envPrefix, "REDIS_PASSWORD"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     RedisDBFlagName,
+			Usage:    "Redis database used when the job store backend is redis.",
+			Value:    0,
+			EnvVars:  withEnvPrefix(envPrefix, "REDIS_DB"),
+			Category: category,
+		},
+	}
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	return Config{
+		Backend:       BackendType(ctx.String(BackendFlagName)),
+		Expiration:    ctx.Duration(ExpirationFlagName),
+		Workers:       ctx.Int(WorkersFlagName),
+		QueueSize:     ctx.Int(QueueSizeFlagName),
+		RedisEndpoint: ctx.String(RedisEndpointFlagName),
+		RedisPassword: ctx.String(RedisPasswordFlagName),
+		RedisDB:       ctx.Int(RedisDBFlagName),
+	}
+}
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Status ... state of an asynchronous dispersal job
+type Status string
+
+const (
+	// StatusQueued ... job was accepted but dispersal hasn't started yet
+	StatusQueued Status = "queued"
+	// StatusDispersing ... blob is being dispersed
+	StatusDispersing Status = "dispersing"
+	// StatusConfirmed ... blob batch was confirmed on Ethereum. If the commitment is set, the job is
+	// done but the cert wasn't yet confirmation depth deep when it was returned.
+	StatusConfirmed Status = "confirmed"
+	// StatusVerified ... the cert was verified and the commitment is set
+	StatusVerified Status = "verified"
+	// StatusFailed ... dispersal failed, see the job error
+	StatusFailed Status = "failed"
+)
+
+type BackendType string
+
+const (
+	MemoryBackend BackendType = "memory"
+	RedisBackend  BackendType = "redis"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+type Config struct {
+	Backend BackendType
+	// how long a job is kept after its last update
+	Expiration time.Duration
+	// number of jobs dispersed concurrently
+	Workers int
+	// max number of jobs waiting for a worker, puts beyond it are rejected
+	QueueSize int
+
+	RedisEndpoint string
+	RedisPassword string
+	RedisDB       int
+}
+
+func StringToBackendType(s string) (BackendType, error) {
+	switch strings.ToLower(s) {
+	case "", string(MemoryBackend):
+		return MemoryBackend, nil
+	case string(RedisBackend):
+		return RedisBackend, nil
+	default:
+		return "", fmt.Errorf("unknown job store backend: %s", s)
+	}
+}
+
+// Job ... record of an asynchronous dispersal that clients poll for its final commitment
+type Job struct {
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+	// commitment as it would've been returned by a synchronous put (only set once the job is done)
+	Commitment hexutil.Bytes `json:"commitment,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// Done ... returns true once the job has either failed or produced a commitment
+func (j *Job) Done() bool {
+	return j.Status == StatusFailed || len(j.Commitment) > 0
+}
+
+// NewJob ... creates a queued job with a random ID
+func NewJob() (*Job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	now := time.Now()
+	return &Job{
+		ID:        hex.EncodeToString(id),
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Store persists jobs so that their state survives client disconnects. Shared implementations
+// (e.g, redis) also allow a client to poll a job through any proxy replica.
+type Store interface {
+	// Put inserts or overwrites the job
+	Put(ctx context.Context, job *Job) error
+	// Get returns the job with the given ID or ErrJobNotFound
+	Get(ctx context.Context, id string) (*Job, error)
+}
+
+// NewStore ... creates the job store backend selected in the config
+func NewStore(cfg Config) (Store, error) {
+	kind, err := StringToBackendType(string(cfg.Backend))
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case RedisBackend:
+		return NewRedisStore(cfg)
+	case MemoryBackend:
+		fallthrough
+	default:
+		return NewMemoryStore(cfg.Expiration), nil
+	}
+}
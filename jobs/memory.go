@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore ... process-local job store, jobs are lost on restart and can only be polled
+// through the proxy instance that accepted them
+type MemoryStore struct {
+	sync.RWMutex
+
+	expiration time.Duration
+	jobs       map[string]*Job
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore ... constructor. Jobs that haven't been updated for the expiration are
+// evicted, a zero expiration keeps them forever.
+func NewMemoryStore(expiration time.Duration) *MemoryStore {
+	return &MemoryStore{
+		expiration: expiration,
+		jobs:       make(map[string]*Job),
+	}
+}
+
+func (m *MemoryStore) Put(_ context.Context, job *Job) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.prune()
+
+	cpy := *job
+	m.jobs[job.ID] = &cpy
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*Job, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	job, ok := m.jobs[id]
+	if !ok || m.expired(job) {
+		return nil, ErrJobNotFound
+	}
+
+	cpy := *job
+	return &cpy, nil
+}
+
+func (m *MemoryStore) expired(job *Job) bool {
+	return m.expiration != 0 && time.Since(job.UpdatedAt) >= m.expiration
+}
+
+// prune ... evicts expired jobs, must be called with the lock held
+func (m *MemoryStore) prune() {
+	for id, job := range m.jobs {
+		if m.expired(job) {
+			delete(m.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorePutGet(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+
+	_, err := s.Get(ctx, "unknown")
+	require.ErrorIs(t, err, ErrJobNotFound)
+
+	job, err := NewJob()
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, job))
+
+	stored, err := s.Get(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, stored.Status)
+	require.False(t, stored.Done())
+
+	// the store keeps its own copy of the job
+	job.Status = StatusDispersing
+	stored, err = s.Get(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, stored.Status)
+
+	job.Status = StatusVerified
+	job.Commitment = []byte{0x1}
+	require.NoError(t, s.Put(ctx, job))
+
+	stored, err = s.Get(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusVerified, stored.Status)
+	require.True(t, stored.Done())
+}
+
+func TestMemoryStoreExpiration(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(10 * time.Millisecond)
+
+	job, err := NewJob()
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, job))
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = s.Get(ctx, job.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+
+	// expired jobs are evicted on the next write
+	other, err := NewJob()
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, other))
+	require.Len(t, s.jobs, 1)
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const redisKeyPrefix = "jobs:"
+
+// RedisStore ... job store shared between proxy replicas, expiration is handled by redis key TTLs
+type RedisStore struct {
+	client     *redis.Client
+	expiration time.Duration
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func NewRedisStore(cfg Config) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisEndpoint,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis server: %w", err)
+	}
+
+	return &RedisStore{
+		client:     client,
+		expiration: cfg.Expiration,
+	}, nil
+}
+
+func (r *RedisStore) Put(ctx context.Context, job *Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	// a zero expiration means the key never expires
+	return r.client.Set(ctx, redisKeyPrefix+job.ID, value, r.expiration).Err()
+}
+
+func (r *RedisStore) Get(ctx context.Context, id string) (*Job, error) {
+	value, err := r.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(value, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &job, nil
+}
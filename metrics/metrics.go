@@ -3,6 +3,7 @@ package metrics
 import (
 	"net"
 	"strconv"
+	"time"
 
 	ophttp "github.com/ethereum-optimism/optimism/op-service/httputil"
 
@@ -15,6 +16,8 @@ import (
 const (
 	namespace           = "eigenda_proxy"
 	httpServerSubsystem = "http_server"
+	eigendaSubsystem    = "eigenda"
+	verifierSubsystem   = "verifier"
 )
 
 // Config ... Metrics server configuration
@@ -30,6 +33,13 @@ type Metricer interface {
 	RecordInfo(version string)
 	RecordUp()
 	RecordRPCServerRequest(method string) func(status string, commitmentMode string, version string)
+	RecordDispersalAttempt(result string)
+	RecordDisperserRequest(endpoint string, method string, result string)
+	RecordDisperserHealth(endpoint string, healthy bool)
+	RecordContractCacheLookup(cache string, hit bool)
+	RecordEthRPCRequest(endpoint string, method string, result string)
+	RecordSRSLoad(duration time.Duration, mappedBytes uint64)
+	RecordSRSDecodedPoints(points uint64, bytes uint64)
 
 	Document() []metrics.DocumentedMetric
 }
@@ -43,6 +53,17 @@ type Metrics struct {
 	HTTPServerBadRequestHeader       *prometheus.CounterVec
 	HTTPServerRequestDurationSeconds *prometheus.HistogramVec
 
+	EigenDADispersalAttemptsTotal *prometheus.CounterVec
+	EigenDADisperserRequestsTotal *prometheus.CounterVec
+	EigenDADisperserHealthy       *prometheus.GaugeVec
+
+	VerifierContractCacheLookupsTotal *prometheus.CounterVec
+	VerifierEthRPCRequestsTotal       *prometheus.CounterVec
+	VerifierSRSLoadDurationSeconds    prometheus.Gauge
+	VerifierSRSMappedBytes            prometheus.Gauge
+	VerifierSRSDecodedPoints          prometheus.Gauge
+	VerifierSRSDecodedBytes           prometheus.Gauge
+
 	registry *prometheus.Registry
 	factory  metrics.Factory
 }
@@ -101,6 +122,70 @@ func NewMetrics(subsystem string) *Metrics {
 		}, []string{
 			"method", // no status on histograms because those are very expensive
 		}),
+		EigenDADispersalAttemptsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: eigendaSubsystem,
+			Name:      "dispersal_attempts_total",
+			Help:      "Total blob dispersal attempts to EigenDA by result (success or the error class of the failure)",
+		}, []string{
+			"result",
+		}),
+		EigenDADisperserRequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: eigendaSubsystem,
+			Name:      "disperser_requests_total",
+			Help:      "Total requests to each disperser endpoint by method and result (success, error or unavailable)",
+		}, []string{
+			"endpoint", "method", "result",
+		}),
+		EigenDADisperserHealthy: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: eigendaSubsystem,
+			Name:      "disperser_healthy",
+			Help:      "1 if the disperser endpoint is healthy, 0 if requests are failed over to the next endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		VerifierContractCacheLookupsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "contract_cache_lookups_total",
+			Help:      "Total lookups of cached contract reads made while verifying certs by cache and result (hit or miss)",
+		}, []string{
+			"cache", "result",
+		}),
+		VerifierEthRPCRequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "eth_rpc_requests_total",
+			Help:      "Total reads from each eth rpc endpoint used for cert verification by method and result (success, error or disagreement)",
+		}, []string{
+			"endpoint", "method", "result",
+		}),
+		VerifierSRSLoadDurationSeconds: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "srs_load_duration_seconds",
+			Help:      "Time taken at startup to check, map and (in eager mode) decode the SRS",
+		}),
+		VerifierSRSMappedBytes: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "srs_mapped_bytes",
+			Help:      "Bytes of the g1.point file memory-mapped by the verifier",
+		}),
+		VerifierSRSDecodedPoints: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "srs_decoded_points",
+			Help:      "Number of G1 points of the SRS decoded so far",
+		}),
+		VerifierSRSDecodedBytes: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: verifierSubsystem,
+			Name:      "srs_decoded_bytes",
+			Help:      "Resident memory held by the decoded G1 points of the SRS",
+		}),
 		registry: registry,
 		factory:  factory,
 	}
@@ -131,6 +216,51 @@ func (m *Metrics) RecordRPCServerRequest(method string) func(status string, mode
 	}
 }
 
+// RecordDispersalAttempt bumps the dispersal attempts metric with the result of an attempt.
+func (m *Metrics) RecordDispersalAttempt(result string) {
+	m.EigenDADispersalAttemptsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordDisperserRequest bumps the requests metric of a disperser endpoint.
+func (m *Metrics) RecordDisperserRequest(endpoint string, method string, result string) {
+	m.EigenDADisperserRequestsTotal.WithLabelValues(endpoint, method, result).Inc()
+}
+
+// RecordDisperserHealth sets the health metric of a disperser endpoint.
+func (m *Metrics) RecordDisperserHealth(endpoint string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	m.EigenDADisperserHealthy.WithLabelValues(endpoint).Set(value)
+}
+
+// RecordContractCacheLookup bumps the lookups metric of a contract read cache.
+func (m *Metrics) RecordContractCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.VerifierContractCacheLookupsTotal.WithLabelValues(cache, result).Inc()
+}
+
+// RecordEthRPCRequest bumps the requests metric of an eth rpc endpoint.
+func (m *Metrics) RecordEthRPCRequest(endpoint string, method string, result string) {
+	m.VerifierEthRPCRequestsTotal.WithLabelValues(endpoint, method, result).Inc()
+}
+
+// RecordSRSLoad sets the SRS load time and the size of the mapped g1.point file.
+func (m *Metrics) RecordSRSLoad(duration time.Duration, mappedBytes uint64) {
+	m.VerifierSRSLoadDurationSeconds.Set(duration.Seconds())
+	m.VerifierSRSMappedBytes.Set(float64(mappedBytes))
+}
+
+// RecordSRSDecodedPoints sets the number of decoded G1 points and the memory they hold.
+func (m *Metrics) RecordSRSDecodedPoints(points uint64, bytes uint64) {
+	m.VerifierSRSDecodedPoints.Set(float64(points))
+	m.VerifierSRSDecodedBytes.Set(float64(bytes))
+}
+
 // StartServer starts the metrics server on the given hostname and port.
 func (m *Metrics) StartServer(hostname string, port int) (*ophttp.HTTPServer, error) {
 	addr := net.JoinHostPort(hostname, strconv.Itoa(port))
@@ -162,3 +292,24 @@ func (n *noopMetricer) RecordUp() {
 func (n *noopMetricer) RecordRPCServerRequest(string) func(status, mode, ver string) {
 	return func(string, string, string) {}
 }
+
+func (n *noopMetricer) RecordDispersalAttempt(_ string) {
+}
+
+func (n *noopMetricer) RecordDisperserRequest(_ string, _ string, _ string) {
+}
+
+func (n *noopMetricer) RecordDisperserHealth(_ string, _ bool) {
+}
+
+func (n *noopMetricer) RecordContractCacheLookup(_ string, _ bool) {
+}
+
+func (n *noopMetricer) RecordEthRPCRequest(_ string, _ string, _ string) {
+}
+
+func (n *noopMetricer) RecordSRSLoad(_ time.Duration, _ uint64) {
+}
+
+func (n *noopMetricer) RecordSRSDecodedPoints(_ uint64, _ uint64) {
+}
@@ -50,6 +50,21 @@ func (mr *MockIRouterMockRecorder) Caches() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Caches", reflect.TypeOf((*MockIRouter)(nil).Caches))
 }
 
+// CertHandler mocks base method.
+func (m *MockIRouter) CertHandler(arg0 commitments.CertEncodingCommitment) (store.CertHandler, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CertHandler", arg0)
+	ret0, _ := ret[0].(store.CertHandler)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CertHandler indicates an expected call of CertHandler.
+func (mr *MockIRouterMockRecorder) CertHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CertHandler", reflect.TypeOf((*MockIRouter)(nil).CertHandler), arg0)
+}
+
 // Fallbacks mocks base method.
 func (m *MockIRouter) Fallbacks() []store.PrecomputedKeyStore {
 	m.ctrl.T.Helper()
@@ -65,7 +80,7 @@ func (mr *MockIRouterMockRecorder) Fallbacks() *gomock.Call {
 }
 
 // Get mocks base method.
-func (m *MockIRouter) Get(arg0 context.Context, arg1 []byte, arg2 commitments.CommitmentMode) ([]byte, error) {
+func (m *MockIRouter) Get(arg0 context.Context, arg1 []byte, arg2 commitments.CommitmentMeta) ([]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]byte)
@@ -108,7 +123,7 @@ func (mr *MockIRouterMockRecorder) GetS3Store() *gomock.Call {
 }
 
 // Put mocks base method.
-func (m *MockIRouter) Put(arg0 context.Context, arg1 commitments.CommitmentMode, arg2, arg3 []byte) ([]byte, error) {
+func (m *MockIRouter) Put(arg0 context.Context, arg1 commitments.CommitmentMeta, arg2, arg3 []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]byte)
@@ -121,3 +136,17 @@ func (mr *MockIRouterMockRecorder) Put(arg0, arg1, arg2, arg3 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockIRouter)(nil).Put), arg0, arg1, arg2, arg3)
 }
+
+// PutCertVersion mocks base method.
+func (m *MockIRouter) PutCertVersion() commitments.CertEncodingCommitment {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutCertVersion")
+	ret0, _ := ret[0].(commitments.CertEncodingCommitment)
+	return ret0
+}
+
+// PutCertVersion indicates an expected call of PutCertVersion.
+func (mr *MockIRouterMockRecorder) PutCertVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutCertVersion", reflect.TypeOf((*MockIRouter)(nil).PutCertVersion))
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+)
+
+// jobStoreTimeout bounds job store writes, which must still go through after the server
+// context is canceled so that interrupted jobs get marked as failed
+const jobStoreTimeout = 5 * time.Second
+
+// ReadAsync ... returns whether the put request asked for an asynchronous dispersal
+func ReadAsync(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get(AsyncKey)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// putJob ... a queued asynchronous dispersal along with its input. Updates of the job go through
+// its mutex and are ignored once the job is done, since stores may still report statuses after
+// the put returned (e.g. the aggregator flushing a batch after the job was canceled).
+type putJob struct {
+	mu   sync.Mutex
+	job  *jobs.Job
+	done bool
+
+	meta   commitments.CommitmentMeta
+	comm   []byte
+	input  []byte
+	params verify.SecurityParams
+}
+
+// update applies fn to the job and records it, unless the job is already done
+func (p *putJob) update(svr *Server, fn func(job *jobs.Job)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return
+	}
+	fn(p.job)
+	svr.updateJob(p.job)
+}
+
+// finish applies fn to the job and records it as its last update
+func (p *putJob) finish(svr *Server, fn func(job *jobs.Job)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return
+	}
+	p.done = true
+	fn(p.job)
+	svr.updateJob(p.job)
+}
+
+// handleAsyncPut queues the dispersal of the input as a job and immediately responds with the
+// job, whose state can then be polled through the put status route. Puts are rejected while
+// the job queue is full.
+func (svr *Server) handleAsyncPut(
+	w http.ResponseWriter, meta commitments.CommitmentMeta, comm []byte, input []byte, params verify.SecurityParams,
+) (commitments.CommitmentMeta, error) {
+	job, err := jobs.NewJob()
+	if err == nil {
+		err = svr.putJob(job)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to create dispersal job: %w", err)
+		svr.WriteInternalError(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
+		}
+	}
+
+	// marshal before handing the job over to the workers which mutate it
+	body, err := json.Marshal(job)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal dispersal job: %w", err)
+		svr.WriteInternalError(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
+		}
+	}
+
+	p := &putJob{job: job, meta: meta, comm: comm, input: input, params: params}
+	svr.jobsWg.Add(1)
+	select {
+	case svr.jobQueue <- p:
+	default:
+		svr.jobsWg.Done()
+		err = fmt.Errorf("dispersal job queue is full: %w", store.ErrBackendUnavailable)
+		svr.failJob(p, err)
+		svr.WriteError(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
+		}
+	}
+
+	svr.log.Info("queued dispersal job", "id", job.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	svr.WriteResponse(w, body)
+	return meta, nil
+}
+
+// runJobWorker runs queued jobs until the server stops, after which the jobs left in the queue
+// are run against the canceled context so that they get marked as failed
+func (svr *Server) runJobWorker() {
+	for {
+		select {
+		case p := <-svr.jobQueue:
+			svr.runPutJob(p)
+		case <-svr.jobsCtx.Done():
+			for {
+				select {
+				case p := <-svr.jobQueue:
+					svr.runPutJob(p)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// runPutJob writes the input through the router, recording the progress reported by the
+// stores in the job store
+func (svr *Server) runPutJob(p *putJob) {
+	defer svr.jobsWg.Done()
+
+	if err := svr.jobsCtx.Err(); err != nil {
+		svr.failJob(p, fmt.Errorf("dispersal job canceled before it started: %w", err))
+		return
+	}
+
+	p.update(svr, func(job *jobs.Job) {
+		job.Status = jobs.StatusDispersing
+	})
+
+	ctx := store.WithPutStatusReporter(verify.WithSecurityParams(svr.jobsCtx, p.params), func(status store.PutStatus) {
+		p.update(svr, func(job *jobs.Job) {
+			// store put statuses are a subset of the job statuses
+			job.Status = jobs.Status(status)
+		})
+	})
+
+	commitment, err := svr.router.Put(ctx, p.meta, p.comm, p.input)
+	if err != nil {
+		svr.failJob(p, fmt.Errorf("put request failed with commitment %v (commitment mode %v): %w", p.comm, p.meta.Mode, err))
+		return
+	}
+
+	responseCommit, err := commitments.EncodeCommitment(commitment, p.meta.Mode, commitments.CertEncodingCommitment(p.meta.CertVersion))
+	if err != nil {
+		svr.failJob(p, fmt.Errorf("failed to encode commitment %v (commitment mode %v): %w", commitment, p.meta.Mode, err))
+		return
+	}
+
+	p.finish(svr, func(job *jobs.Job) {
+		// a confirmed status is kept when the cert wasn't verified yet, e.g. because it
+		// didn't reach the confirmation depth before being returned
+		if job.Status != jobs.StatusConfirmed {
+			job.Status = jobs.StatusVerified
+		}
+		job.Commitment = responseCommit
+	})
+
+	svr.log.Info("dispersal job done", "id", p.job.ID, "status", p.job.Status, "commitment", fmt.Sprintf("%x", responseCommit))
+}
+
+func (svr *Server) failJob(p *putJob, err error) {
+	svr.log.Error("dispersal job failed", "id", p.job.ID, "err", err)
+	p.finish(svr, func(job *jobs.Job) {
+		job.Status = jobs.StatusFailed
+		job.Error = err.Error()
+	})
+}
+
+func (svr *Server) updateJob(job *jobs.Job) {
+	job.UpdatedAt = time.Now()
+	if err := svr.putJob(job); err != nil {
+		svr.log.Error("failed to update dispersal job", "id", job.ID, "status", job.Status, "err", err)
+	}
+}
+
+func (svr *Server) putJob(job *jobs.Job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), jobStoreTimeout)
+	defer cancel()
+	return svr.jobs.Put(ctx, job)
+}
+
+// HandlePutStatus handles the GET request for the state of an asynchronous dispersal job.
+func (svr *Server) HandlePutStatus(w http.ResponseWriter, r *http.Request) error {
+	id := path.Base(r.URL.Path)
+	job, err := svr.jobs.Get(r.Context(), id)
+	if err != nil {
+		err = fmt.Errorf("failed to get dispersal job %s: %w", id, err)
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			svr.WriteNotFound(w, err)
+		} else {
+			svr.WriteInternalError(w, err)
+		}
+		return err
+	}
+
+	body, err := json.Marshal(job)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal dispersal job %s: %w", id, err)
+		svr.WriteInternalError(w, err)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	svr.WriteResponse(w, body)
+	return nil
+}
@@ -2,12 +2,21 @@ package server
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
 	"github.com/Layr-Labs/eigenda-proxy/flags"
 	"github.com/Layr-Labs/eigenda-proxy/flags/eigendaflags"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/signer"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/memstore"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/redis"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/s3"
@@ -28,26 +37,84 @@ type Config struct {
 	// routing
 	FallbackTargets []string
 	CacheTargets    []string
+	// cert version of the commitments returned for puts
+	PutCertVersion commitments.CertEncodingCommitment
+
+	// max number of L1 blocks between the reference block of a cert and its L1 inclusion block
+	CertRecencyWindow uint64
 
 	// secondary storage
 	RedisConfig redis.Config
 	S3Config    s3.Config
+
+	// asynchronous dispersal job store
+	JobsConfig jobs.Config
+
+	// write-ahead journal of in-flight eigenda dispersals
+	JournalConfig journal.Config
+
+	// retry policy of eigenda dispersals
+	RetryConfig eigenda.RetryConfig
+
+	// cancel or detach eigenda dispersals whose client goes away
+	DisconnectConfig eigenda.DisconnectConfig
+
+	// backup disperser endpoints and their health checks
+	EndpointsConfig endpoints.Config
+
+	// keys authenticating eigenda dispersals, replacing the client's signer private key hex
+	SignerConfig signer.Config
+
+	// packing of small payloads into shared blobs
+	AggregatorConfig aggregator.Config
+
+	// splitting of large payloads across several blobs
+	ChunkerConfig chunker.Config
+
+	// security params that put requests may require of their blob
+	SecurityOverrides SecurityOverridesConfig
 }
 
 // ReadConfig ... parses the Config from the provided flags or environment variables.
 func ReadConfig(ctx *cli.Context) Config {
 	return Config{
-		RedisConfig:     redis.ReadConfig(ctx),
-		S3Config:        s3.ReadConfig(ctx),
-		EdaClientConfig: eigendaflags.ReadConfig(ctx),
-		VerifierConfig:  verify.ReadConfig(ctx),
-		MemstoreEnabled: ctx.Bool(memstore.EnabledFlagName),
-		MemstoreConfig:  memstore.ReadConfig(ctx),
-		FallbackTargets: ctx.StringSlice(flags.FallbackTargetsFlagName),
-		CacheTargets:    ctx.StringSlice(flags.CacheTargetsFlagName),
+		RedisConfig:       redis.ReadConfig(ctx),
+		S3Config:          s3.ReadConfig(ctx),
+		EdaClientConfig:   eigendaflags.ReadConfig(ctx),
+		VerifierConfig:    verify.ReadConfig(ctx),
+		MemstoreEnabled:   ctx.Bool(memstore.EnabledFlagName),
+		MemstoreConfig:    memstore.ReadConfig(ctx),
+		FallbackTargets:   ctx.StringSlice(flags.FallbackTargetsFlagName),
+		CacheTargets:      ctx.StringSlice(flags.CacheTargetsFlagName),
+		PutCertVersion:    commitments.CertEncodingCommitment(ctx.Uint(flags.PutCertVersionFlagName)), // #nosec G115
+		CertRecencyWindow: ctx.Uint64(flags.CertRecencyWindowFlagName),
+		JobsConfig:        jobs.ReadConfig(ctx),
+		JournalConfig:     journal.ReadConfig(ctx),
+		RetryConfig:       eigenda.ReadRetryConfig(ctx),
+		DisconnectConfig:  eigenda.ReadDisconnectConfig(ctx),
+		EndpointsConfig:   endpoints.ReadConfig(ctx),
+		SignerConfig:      signer.ReadConfig(ctx),
+		AggregatorConfig:  aggregator.ReadConfig(ctx),
+		ChunkerConfig:     chunker.ReadConfig(ctx),
+		SecurityOverrides: SecurityOverridesConfig{
+			AllowedQuorumIDs:         toQuorumIDs(ctx.UintSlice(flags.SecurityOverridesAllowedQuorumsFlagName)),
+			MaxAdversaryThreshold:    uint8(min(ctx.Uint(flags.SecurityOverridesMaxAdversaryThresholdFlagName), math.MaxUint8)),    // #nosec G115
+			MaxConfirmationThreshold: uint8(min(ctx.Uint(flags.SecurityOverridesMaxConfirmationThresholdFlagName), math.MaxUint8)), // #nosec G115
+		},
 	}
 }
 
+// toQuorumIDs ... out of range quorum IDs are rejected by the flag action
+func toQuorumIDs(ids []uint) []uint8 {
+	quorums := make([]uint8, 0, len(ids))
+	for _, id := range ids {
+		if id <= math.MaxUint8 {
+			quorums = append(quorums, uint8(id))
+		}
+	}
+	return quorums
+}
+
 // checkTargets ... verifies that a backend target slice is constructed correctly
 func (cfg *Config) checkTargets(targets []string) error {
 	if len(targets) == 0 {
@@ -73,22 +140,93 @@ func (cfg *Config) Check() error {
 		if cfg.EdaClientConfig.RPC == "" {
 			return fmt.Errorf("using eigenda backend (memstore.enabled=false) but eigenda disperser rpc url is not set")
 		}
+		if err := cfg.RetryConfig.Check(); err != nil {
+			return err
+		}
+		if err := cfg.DisconnectConfig.Check(); err != nil {
+			return err
+		}
+		if err := cfg.EndpointsConfig.Check(); err != nil {
+			return err
+		}
+		if cfg.SignerConfig.Enabled() && cfg.EdaClientConfig.SignerPrivateKeyHex != "" {
+			return fmt.Errorf("eigenda signer private key hex can't be set along with the keystore, remote or KMS signers")
+		}
+		if err := cfg.SignerConfig.Check(); err != nil {
+			return err
+		}
+	} else {
+		backend, err := memstore.StringToBackendType(string(cfg.MemstoreConfig.Backend))
+		if err != nil {
+			return err
+		}
+		if backend == memstore.RedisBackend && cfg.MemstoreConfig.RedisEndpoint == "" {
+			return fmt.Errorf("memstore backend is redis but memstore redis endpoint is not set")
+		}
+		if backend == memstore.FilesystemBackend && cfg.MemstoreConfig.FSPath == "" {
+			return fmt.Errorf("memstore backend is filesystem but memstore fs path is not set")
+		}
+		if cfg.JournalConfig.Dir != "" {
+			return fmt.Errorf("dispersal journal is only supported by the eigenda backend (memstore.enabled=false)")
+		}
 	}
 
 	// cert verification is enabled
 	// TODO: move this verification logic to verify/cli.go
 	if cfg.VerifierConfig.VerifyCerts {
 		if cfg.MemstoreEnabled {
-			return fmt.Errorf("cannot enable cert verification when memstore is enabled")
+			// memstore certs are confirmed on a process-local simulated chain instead of ethereum
+			backend, err := memstore.StringToBackendType(string(cfg.MemstoreConfig.Backend))
+			if err != nil {
+				return err
+			}
+			if backend != memstore.MemoryBackend {
+				return fmt.Errorf("cert verification with memstore requires the memory backend since certs are confirmed on a process-local simulated chain")
+			}
+			if cfg.MemstoreConfig.SimulatedBlockTime <= 0 {
+				return fmt.Errorf("cert verification with memstore enabled but simulated block time is not set")
+			}
+		} else {
+			if err := cfg.VerifierConfig.CheckRPCs(); err != nil {
+				return err
+			}
+			if cfg.VerifierConfig.SvcManagerAddr == "" {
+				return fmt.Errorf("cert verification enabled but svc manager address is not set")
+			}
 		}
-		if cfg.VerifierConfig.RPCURL == "" {
-			return fmt.Errorf("cert verification enabled but eth rpc is not set")
+	}
+
+	jobsBackend, err := jobs.StringToBackendType(string(cfg.JobsConfig.Backend))
+	if err != nil {
+		return err
+	}
+	if jobsBackend == jobs.RedisBackend && cfg.JobsConfig.RedisEndpoint == "" {
+		return fmt.Errorf("job store backend is redis but job store redis endpoint is not set")
+	}
+	if cfg.JobsConfig.Workers < 1 {
+		return fmt.Errorf("number of job workers must be at least 1")
+	}
+	if cfg.JobsConfig.QueueSize < 1 {
+		return fmt.Errorf("job queue size must be at least 1")
+	}
+
+	if cfg.AggregatorConfig.Enabled {
+		if cfg.AggregatorConfig.Window <= 0 {
+			return fmt.Errorf("aggregation enabled but aggregation window is not set")
 		}
-		if cfg.VerifierConfig.SvcManagerAddr == "" {
-			return fmt.Errorf("cert verification enabled but svc manager address is not set")
+		if cfg.AggregatorConfig.MaxBytes == 0 {
+			return fmt.Errorf("aggregation enabled but aggregation max bytes is not set")
 		}
 	}
 
+	if err := cfg.SecurityOverrides.Check(); err != nil {
+		return err
+	}
+
+	if cfg.ChunkerConfig.Enabled && cfg.ChunkerConfig.MaxChunks < 2 {
+		return fmt.Errorf("chunking enabled but max chunks is lower than 2")
+	}
+
 	if cfg.S3Config.CredentialType == s3.CredentialTypeUnknown && cfg.S3Config.Endpoint != "" {
 		return fmt.Errorf("s3 credential type must be set")
 	}
@@ -102,7 +240,7 @@ func (cfg *Config) Check() error {
 		return fmt.Errorf("redis password is set, but endpoint is not")
 	}
 
-	err := cfg.checkTargets(cfg.FallbackTargets)
+	err = cfg.checkTargets(cfg.FallbackTargets)
 	if err != nil {
 		return err
 	}
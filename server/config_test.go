@@ -4,6 +4,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/memstore"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/redis"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/s3"
@@ -57,9 +62,22 @@ func validCfg() *Config {
 			RPCURL:               "http://localhost:8545",
 			EthConfirmationDepth: 12,
 		},
+		RetryConfig: eigenda.RetryConfig{
+			MaxAttempts: 1,
+		},
+		EndpointsConfig: endpoints.Config{
+			HealthCheckInterval: 10 * time.Second,
+			FailureThreshold:    3,
+		},
 		MemstoreEnabled: true,
 		MemstoreConfig: memstore.Config{
-			BlobExpiration: 25 * time.Minute,
+			BlobExpiration:     25 * time.Minute,
+			SimulatedBlockTime: 12 * time.Second,
+		},
+		JobsConfig: jobs.Config{
+			Backend:   jobs.MemoryBackend,
+			Workers:   8,
+			QueueSize: 100,
 		},
 	}
 }
@@ -97,14 +115,279 @@ func TestConfigVerification(t *testing.T) {
 			require.Error(t, err)
 		})
 
-		t.Run("CantDoCertVerificationWhenMemstoreEnabled", func(t *testing.T) {
+		t.Run("EthRPCQuorumNotMajority", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.VerifierConfig.VerifyCerts = true
+			cfg.VerifierConfig.AdditionalRPCURLs = []string{"http://localhost:8546", "http://localhost:8547"}
+			cfg.VerifierConfig.RPCMode = verify.ConsensusRPCMode
+			cfg.VerifierConfig.RPCQuorum = 1
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("CertVerificationWithMemstoreUsesSimulatedChain", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = true
+			cfg.VerifierConfig.VerifyCerts = true
+			cfg.VerifierConfig.RPCURL = ""
+			cfg.VerifierConfig.SvcManagerAddr = ""
+
+			err := cfg.Check()
+			require.NoError(t, err)
+		})
+
+		t.Run("CantDoCertVerificationWithSharedMemstoreBackend", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = true
+			cfg.VerifierConfig.VerifyCerts = true
+			cfg.MemstoreConfig.Backend = memstore.FilesystemBackend
+			cfg.MemstoreConfig.FSPath = "/tmp/memstore"
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingSimulatedBlockTime", func(t *testing.T) {
 			cfg := validCfg()
 			cfg.MemstoreEnabled = true
 			cfg.VerifierConfig.VerifyCerts = true
+			cfg.MemstoreConfig.SimulatedBlockTime = 0
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("SharedMemstoreBackend", func(t *testing.T) {
+		t.Run("UnknownBackend", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreConfig.Backend = "postgres"
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingRedisEndpoint", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreConfig.Backend = memstore.RedisBackend
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingFSPath", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreConfig.Backend = memstore.FilesystemBackend
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("JobStoreBackend", func(t *testing.T) {
+		t.Run("UnknownBackend", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.JobsConfig.Backend = "postgres"
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingRedisEndpoint", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.JobsConfig.Backend = jobs.RedisBackend
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("NoWorkers", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.JobsConfig.Workers = 0
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("NoQueue", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.JobsConfig.QueueSize = 0
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("DispersalRetries", func(t *testing.T) {
+		t.Run("MissingMaxAttempts", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.RetryConfig.MaxAttempts = 0
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingBackoff", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.RetryConfig.MaxAttempts = 3
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("DisperserEndpoints", func(t *testing.T) {
+		t.Run("InvalidBackupRPC", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EndpointsConfig.BackupRPCs = []string{"disperser-holesky.eigenda.xyz"}
 
 			err := cfg.Check()
 			require.Error(t, err)
 		})
+
+		t.Run("MissingHealthCheckInterval", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EndpointsConfig.BackupRPCs = []string{"disperser-holesky.eigenda.xyz:443"}
+			cfg.EndpointsConfig.HealthCheckInterval = 0
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("ValidBackupRPC", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EndpointsConfig.BackupRPCs = []string{"disperser-holesky.eigenda.xyz:443"}
+
+			err := cfg.Check()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		t.Run("MissingResultTTL", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.DisconnectConfig = eigenda.DisconnectConfig{Policy: eigenda.DisconnectDetach}
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("Detach", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.DisconnectConfig = eigenda.DisconnectConfig{Policy: eigenda.DisconnectDetach, ResultTTL: time.Hour}
+
+			err := cfg.Check()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("SecurityOverrides", func(t *testing.T) {
+		t.Run("MaxThresholdAbove100", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.SecurityOverrides.MaxConfirmationThreshold = 101
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("Signer", func(t *testing.T) {
+		t.Run("ConflictingPrivateKeyHex", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.SignerConfig.RemoteURLs = []string{"http://localhost:9000"}
+			cfg.SignerConfig.RemoteTimeout = 10 * time.Second
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingKeystorePassword", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EdaClientConfig.SignerPrivateKeyHex = ""
+			cfg.SignerConfig.KeystoreFiles = []string{"/keys/disperser.json"}
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("UnknownKMSProvider", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EdaClientConfig.SignerPrivateKeyHex = ""
+			cfg.SignerConfig.KMSProvider = "unknown"
+			cfg.SignerConfig.KMSKeyIDs = []string{"key-1"}
+			cfg.SignerConfig.RemoteTimeout = 10 * time.Second
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("ValidKeystore", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.EdaClientConfig.SignerPrivateKeyHex = ""
+			cfg.SignerConfig.KeystoreFiles = []string{"/keys/disperser.json"}
+			cfg.SignerConfig.KeystorePasswordFile = "/keys/password"
+
+			err := cfg.Check()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("Aggregation", func(t *testing.T) {
+		t.Run("MissingWindow", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.AggregatorConfig = aggregator.Config{Enabled: true, MaxBytes: 1024}
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("MissingMaxBytes", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.AggregatorConfig = aggregator.Config{Enabled: true, Window: time.Second}
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("ChunkingWithTooFewChunks", func(t *testing.T) {
+		cfg := validCfg()
+		cfg.ChunkerConfig = chunker.Config{Enabled: true, MaxChunks: 1}
+
+		err := cfg.Check()
+		require.Error(t, err)
+	})
+
+	t.Run("DispersalJournal", func(t *testing.T) {
+		t.Run("CantUseJournalWithMemstore", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.JournalConfig.Dir = t.TempDir()
+
+			err := cfg.Check()
+			require.Error(t, err)
+		})
+
+		t.Run("JournalWithEigenDABackend", func(t *testing.T) {
+			cfg := validCfg()
+			cfg.MemstoreEnabled = false
+			cfg.JournalConfig.Dir = t.TempDir()
+
+			err := cfg.Check()
+			require.NoError(t, err)
+		})
 	})
 
 	t.Run("MissingS3AccessKeys", func(t *testing.T) {
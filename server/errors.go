@@ -1,9 +1,13 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 )
 
 // MetaError includes both an error and commitment metadata
@@ -19,7 +23,67 @@ func (me MetaError) Error() string {
 		me.Meta.CertVersion)
 }
 
+func (me MetaError) Unwrap() error {
+	return me.Err
+}
+
 // NewMetaError creates a new MetaError
 func NewMetaError(err error, meta commitments.CommitmentMeta) MetaError {
 	return MetaError{Err: err, Meta: meta}
 }
+
+// ErrorResponse ... JSON body of error responses
+type ErrorResponse struct {
+	// category of the error, which clients can react to without parsing the message
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// ErrorCode ... category of an error response, each of which has a distinct HTTP status
+type ErrorCode string
+
+const (
+	ErrorCodeBadRequest            ErrorCode = "bad_request"
+	ErrorCodeNotFound              ErrorCode = "not_found"
+	ErrorCodeExpired               ErrorCode = "expired"
+	ErrorCodeInvalidCert           ErrorCode = "invalid_cert"
+	ErrorCodeRecencyWindowExceeded ErrorCode = "recency_window_exceeded"
+	ErrorCodeCommitmentMismatch    ErrorCode = "commitment_mismatch"
+	ErrorCodeInsufficientDepth     ErrorCode = "insufficient_depth"
+	ErrorCodeBackendUnavailable    ErrorCode = "backend_unavailable"
+	ErrorCodeInternal              ErrorCode = "internal_error"
+)
+
+// errorCategories ... HTTP status and code of each error category. Errors can match several
+// categories (e.g, a fallback read that failed after EigenDA did), the first match wins.
+var errorCategories = []struct {
+	err    error
+	status int
+	code   ErrorCode
+}{
+	// certs included on L1 too late after their reference block, whose batch rollup derivation drops
+	{verify.ErrRecencyWindowExceeded, http.StatusTeapot, ErrorCodeRecencyWindowExceeded},
+	// certs of a version that's served, but can't be decoded to be reported on
+	{ErrUnsupportedCertVersion, http.StatusBadRequest, ErrorCodeBadRequest},
+	// certs that will never verify, so that clients can drop them
+	{verify.ErrInvalidCert, http.StatusUnprocessableEntity, ErrorCodeInvalidCert},
+	// the backend served a blob that doesn't match the cert, another replica or backend may not
+	{verify.ErrCommitmentMismatch, http.StatusBadGateway, ErrorCodeCommitmentMismatch},
+	// the cert may verify once its batch is deep enough
+	{verify.ErrInsufficientDepth, http.StatusTooEarly, ErrorCodeInsufficientDepth},
+	{store.ErrExpired, http.StatusGone, ErrorCodeExpired},
+	{store.ErrBackendUnavailable, http.StatusServiceUnavailable, ErrorCodeBackendUnavailable},
+	{verify.ErrEthRPCUnavailable, http.StatusServiceUnavailable, ErrorCodeBackendUnavailable},
+	{store.ErrNotFound, http.StatusNotFound, ErrorCodeNotFound},
+}
+
+// errorStatus returns the HTTP status and code of the category of an error, which defaults to an
+// internal error
+func errorStatus(err error) (int, ErrorCode) {
+	for _, c := range errorCategories {
+		if errors.Is(err, c.err) {
+			return c.status, c.code
+		}
+	}
+	return http.StatusInternalServerError, ErrorCodeInternal
+}
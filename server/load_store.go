@@ -4,13 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/signer"
 	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/memstore"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/redis"
 	"github.com/Layr-Labs/eigenda-proxy/store/precomputed_key/s3"
 	"github.com/Layr-Labs/eigenda-proxy/verify"
-	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/Layr-Labs/eigenda-proxy/verify/simulated"
+	"github.com/Layr-Labs/eigenda/core"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -49,7 +57,7 @@ func populateTargets(targets []string, s3 store.PrecomputedKeyStore, redis *redi
 }
 
 // LoadStoreRouter ... creates storage backend clients and instruments them into a storage routing abstraction
-func LoadStoreRouter(ctx context.Context, cfg CLIConfig, log log.Logger) (store.IRouter, error) {
+func LoadStoreRouter(ctx context.Context, cfg CLIConfig, log log.Logger, m metrics.Metricer) (store.IRouter, error) {
 	// create S3 backend store (if enabled)
 	var err error
 	var s3Store store.PrecomputedKeyStore
@@ -76,9 +84,34 @@ func LoadStoreRouter(ctx context.Context, cfg CLIConfig, log log.Logger) (store.
 	daCfg := cfg.EigenDAConfig
 	vCfg := daCfg.VerifierConfig
 
-	verifier, err := verify.NewVerifier(&vCfg, log)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create verifier: %w", err)
+	var verifier *verify.Verifier
+	var registrar memstore.BatchRegistrar
+	if daCfg.MemstoreEnabled && vCfg.VerifyCerts {
+		// memstore certs are confirmed on a local simulated chain rather than ethereum
+		log.Info("Using simulated Ethereum chain for mem-store cert verification", "block_time", daCfg.MemstoreConfig.SimulatedBlockTime)
+		sim, err := simulated.NewBackend(log.With("subsystem", "simulated-eth"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create simulated ethereum backend: %w", err)
+		}
+		sim.Start(daCfg.MemstoreConfig.SimulatedBlockTime)
+		go func() {
+			<-ctx.Done()
+			if err := sim.Close(); err != nil {
+				log.Warn("failed to close simulated ethereum backend", "err", err)
+			}
+		}()
+
+		vCfg.SvcManagerAddr = simulated.ServiceManagerAddr.Hex()
+		verifier, err = verify.NewVerifierWithClient(&vCfg, sim.Client(), log, m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create verifier: %w", err)
+		}
+		registrar = sim
+	} else {
+		verifier, err = verify.NewVerifier(&vCfg, log, m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create verifier: %w", err)
+		}
 	}
 
 	if vCfg.VerifyCerts {
@@ -91,35 +124,100 @@ func LoadStoreRouter(ctx context.Context, cfg CLIConfig, log log.Logger) (store.
 	var eigenDA store.GeneratedKeyStore
 	if cfg.EigenDAConfig.MemstoreEnabled {
 		log.Info("Using mem-store backend for EigenDA")
-		eigenDA, err = memstore.New(ctx, verifier, log, cfg.EigenDAConfig.MemstoreConfig)
+		eigenDA, err = memstore.NewWithRegistrar(ctx, verifier, registrar, log, cfg.EigenDAConfig.MemstoreConfig)
 	} else {
-		var client *clients.EigenDAClient
-		log.Info("Using EigenDA backend")
-		client, err = clients.NewEigenDAClient(log.With("subsystem", "eigenda-client"), daCfg.EdaClientConfig)
+		var signers []core.BlobRequestSigner
+		signers, err = signer.New(ctx, daCfg.SignerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load disperser signers: %w", err)
+		}
+		if len(signers) > 0 {
+			log.Info("Authenticating dispersals with signers", "keys", len(signers))
+		}
+
+		var pool *endpoints.Pool
+		log.Info("Using EigenDA backend", "disperser", daCfg.EdaClientConfig.RPC, "backup_dispersers", daCfg.EndpointsConfig.BackupRPCs)
+		pool, err = endpoints.New(daCfg.EdaClientConfig, daCfg.EndpointsConfig, signers, log.With("subsystem", "eigenda-client"), m)
 		if err != nil {
 			return nil, err
 		}
+		pool.Start(ctx)
+
+		var dispersalJournal *journal.Journal
+		if daCfg.JournalConfig.Dir != "" {
+			log.Info("Using dispersal journal", "dir", daCfg.JournalConfig.Dir)
+			dispersalJournal, err = journal.New(daCfg.JournalConfig.Dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create dispersal journal: %w", err)
+			}
+		}
 
-		eigenDA, err = eigenda.NewStore(
-			client,
+		var daStore *eigenda.Store
+		daStore, err = eigenda.NewStoreWithEndpoints(
+			pool,
 			verifier,
+			dispersalJournal,
 			log,
+			m,
 			&eigenda.StoreConfig{
 				MaxBlobSizeBytes:     cfg.EigenDAConfig.MemstoreConfig.MaxBlobSizeBytes,
 				EthConfirmationDepth: cfg.EigenDAConfig.VerifierConfig.EthConfirmationDepth,
 				StatusQueryTimeout:   cfg.EigenDAConfig.EdaClientConfig.StatusQueryTimeout,
+				JournalRetention:     daCfg.JournalConfig.Retention,
+				Retry:                daCfg.RetryConfig,
+				Disconnect:           daCfg.DisconnectConfig,
 			},
 		)
+		if err != nil {
+			return nil, err
+		}
+		eigenDA = daStore
+
+		// finish the dispersals that a previous run was interrupted in
+		go func() {
+			if err := daStore.Resume(ctx); err != nil {
+				log.Error("failed to resume interrupted dispersals", "err", err)
+			}
+		}()
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	// chunking wraps the backend first so that aggregation passes large payloads through to it
+	if daCfg.ChunkerConfig.Enabled {
+		log.Info("Splitting large payloads across blobs", "max_chunks", daCfg.ChunkerConfig.MaxChunks)
+		eigenDA, err = chunker.New(eigenDA, daCfg.ChunkerConfig, daCfg.MemstoreConfig.MaxBlobSizeBytes, log.With("subsystem", "chunker"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create chunker: %w", err)
+		}
+	}
+
+	if daCfg.AggregatorConfig.Enabled {
+		log.Info("Aggregating small payloads into shared blobs", "window", daCfg.AggregatorConfig.Window, "max_bytes", daCfg.AggregatorConfig.MaxBytes)
+		eigenDA = aggregator.New(eigenDA, daCfg.AggregatorConfig, log.With("subsystem", "aggregator"))
+	}
+
 	// determine read fallbacks
 	fallbacks := populateTargets(cfg.EigenDAConfig.FallbackTargets, s3Store, redisStore)
 	caches := populateTargets(cfg.EigenDAConfig.CacheTargets, s3Store, redisStore)
 
-	log.Info("Creating storage router", "eigenda backend type", eigenDA != nil, "s3 backend type", s3Store != nil)
-	return store.NewRouter(eigenDA, s3Store, log, caches, fallbacks)
+	// V0 certs are the only ones dispersed and served by the EigenDA backend, newer cert versions are
+	// registered next to them once a backend emits them
+	certs := store.NewCertRegistry()
+	if eigenDA != nil {
+		err = certs.Register(commitments.CertV0, certV0Handler(eigenDA))
+		if err != nil {
+			return nil, err
+		}
+		err = certs.SetPutVersion(cfg.EigenDAConfig.PutCertVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid put cert version: %w", err)
+		}
+	}
+
+	log.Info("Creating storage router", "eigenda backend type", eigenDA != nil, "s3 backend type", s3Store != nil,
+		"cert versions", certs.Versions(), "put cert version", certs.PutVersion())
+	return store.NewRouterWithCerts(certs, s3Store, log, caches, fallbacks)
 }
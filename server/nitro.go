@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// NitroNamespace ... JSON-RPC namespace of the Arbitrum Nitro external DA provider API
+const NitroNamespace = "daprovider"
+
+// NitroAPI ... Arbitrum Nitro external DA provider API, which stores batches as blobs on EigenDA and
+// returns DA certificates that Nitro posts in sequencer messages in place of the batch data
+type NitroAPI struct {
+	svr *Server
+}
+
+// SupportedHeaderBytesResult ... header bytes of the sequencer messages that the DA provider reads
+type SupportedHeaderBytesResult struct {
+	HeaderBytes hexutil.Bytes `json:"headerBytes,omitempty"`
+}
+
+// StoreResult ... DA certificate of a stored batch, serialized as [header_flag, cert_version, cert...]
+type StoreResult struct {
+	SerializedDACert hexutil.Bytes `json:"serialized-da-cert,omitempty"`
+}
+
+// PayloadResult ... batch data that a DA certificate was stored for
+type PayloadResult struct {
+	Payload hexutil.Bytes `json:"payload,omitempty"`
+}
+
+// GetSupportedHeaderBytes returns the header flag of the DA certificates returned by Store
+func (api *NitroAPI) GetSupportedHeaderBytes(_ context.Context) (*SupportedHeaderBytesResult, error) {
+	return &SupportedHeaderBytesResult{HeaderBytes: hexutil.Bytes{commitments.NitroDACertHeaderFlag}}, nil
+}
+
+// Store disperses a batch and returns its DA certificate. The timeout is the expiry of the batch in
+// AnyTrust, which doesn't apply to EigenDA since blobs are retained for the same period.
+func (api *NitroAPI) Store(ctx context.Context, message hexutil.Bytes, _ hexutil.Uint64) (res *StoreResult, err error) {
+	meta := commitments.CommitmentMeta{
+		Mode:        commitments.ArbitrumNitro,
+		CertVersion: byte(api.svr.router.PutCertVersion()),
+	}
+	record := api.svr.recordRPC(NitroNamespace + "_store")
+	defer func() { err = record(meta, err) }()
+
+	key, err := api.svr.router.Put(ctx, meta, nil, message)
+	if err != nil {
+		return nil, fmt.Errorf("store request failed: %w", err)
+	}
+	cert, err := commitments.EncodeCommitment(key, meta.Mode, commitments.CertEncodingCommitment(meta.CertVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode DA certificate: %w", err)
+	}
+	return &StoreResult{SerializedDACert: cert}, nil
+}
+
+// RecoverPayload returns the batch data of the DA certificate in a sequencer message, which starts with
+// the header of the message
+func (api *NitroAPI) RecoverPayload(ctx context.Context, _ hexutil.Uint64, _ common.Hash,
+	sequencerMsg hexutil.Bytes) (res *PayloadResult, err error) {
+	meta := commitments.CommitmentMeta{Mode: commitments.ArbitrumNitro}
+	record := api.svr.recordRPC(NitroNamespace + "_recoverPayload")
+	defer func() { err = record(meta, err) }()
+
+	if len(sequencerMsg) < commitments.NitroSequencerMsgHeaderLen {
+		return nil, fmt.Errorf("%w: sequencer message of %d bytes is shorter than its header",
+			verify.ErrInvalidCert, len(sequencerMsg))
+	}
+	cert := sequencerMsg[commitments.NitroSequencerMsgHeaderLen:]
+	version, key, err := commitments.DecodeNitroDACert(cert)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode DA certificate: %w", verify.ErrInvalidCert, err)
+	}
+	meta.CertVersion = byte(version)
+
+	payload, err := api.svr.router.Get(ctx, key, meta)
+	if err != nil {
+		return nil, fmt.Errorf("recover payload request failed with DA certificate %x: %w", cert, err)
+	}
+	return &PayloadResult{Payload: payload}, nil
+}
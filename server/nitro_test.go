@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNitroAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
+	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
+
+	rpcServer, err := server.newRPCServer()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(rpcServer)
+	defer httpServer.Close()
+
+	client, err := rpc.Dial(httpServer.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	nitroMeta := commitments.CommitmentMeta{Mode: commitments.ArbitrumNitro, CertVersion: byte(commitments.CertV0)}
+	payload := []byte("batch data")
+	cert := []byte("cert")
+
+	t.Run("GetSupportedHeaderBytes", func(t *testing.T) {
+		var res SupportedHeaderBytesResult
+		require.NoError(t, client.CallContext(ctx, &res, "daprovider_getSupportedHeaderBytes"))
+		require.Equal(t, hexutil.Bytes{commitments.NitroDACertHeaderFlag}, res.HeaderBytes)
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		mockRouter.EXPECT().Put(gomock.Any(), nitroMeta, gomock.Nil(), payload).Return(cert, nil)
+
+		var res StoreResult
+		require.NoError(t, client.CallContext(ctx, &res, "daprovider_store", hexutil.Bytes(payload), hexutil.Uint64(0)))
+		require.Equal(t, append([]byte{commitments.NitroDACertHeaderFlag, byte(commitments.CertV0)}, cert...),
+			[]byte(res.SerializedDACert))
+	})
+
+	t.Run("RecoverPayload", func(t *testing.T) {
+		mockRouter.EXPECT().Get(gomock.Any(), cert, nitroMeta).Return(payload, nil)
+
+		sequencerMsg := make([]byte, commitments.NitroSequencerMsgHeaderLen)
+		sequencerMsg = append(sequencerMsg, commitments.NitroDACertHeaderFlag, byte(commitments.CertV0))
+		sequencerMsg = append(sequencerMsg, cert...)
+
+		var res PayloadResult
+		require.NoError(t, client.CallContext(ctx, &res, "daprovider_recoverPayload",
+			hexutil.Uint64(1), common.Hash{}, hexutil.Bytes(sequencerMsg)))
+		require.Equal(t, payload, []byte(res.Payload))
+	})
+
+	t.Run("RecoverPayloadMalformedCert", func(t *testing.T) {
+		// errors are returned before calling the router
+		for _, sequencerMsg := range [][]byte{
+			make([]byte, commitments.NitroSequencerMsgHeaderLen-1),
+			append(make([]byte, commitments.NitroSequencerMsgHeaderLen), commitments.NitroDACertHeaderFlag, 0),
+			append(make([]byte, commitments.NitroSequencerMsgHeaderLen), 0xed, 0, 1, 2),
+		} {
+			var res PayloadResult
+			err := client.CallContext(ctx, &res, "daprovider_recoverPayload",
+				hexutil.Uint64(1), common.Hash{}, hexutil.Bytes(sequencerMsg))
+			require.ErrorContains(t, err, "invalid cert")
+		}
+	})
+}
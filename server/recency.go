@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+)
+
+// L1InclusionBlockKey ... query param of get requests holding the L1 block that the commitment was
+// included in, which certs are checked against the recency window with
+const L1InclusionBlockKey = "l1_inclusion_block"
+
+// ReadL1InclusionBlock ... reads the L1 inclusion block of a get request, which is optional
+func ReadL1InclusionBlock(r *http.Request) (block uint64, ok bool, err error) {
+	value := r.URL.Query().Get(L1InclusionBlockKey)
+	if value == "" {
+		return 0, false, nil
+	}
+	block, err = strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return block, true, nil
+}
+
+// checkRecency verifies that every cert of the commitment was included on L1 within the recency window
+// after its reference block, which the handler of the cert version reads. keccak256 commitments have
+// no cert, and are never checked, nor are the certs of versions without reference blocks.
+func (svr *Server) checkRecency(meta commitments.CommitmentMeta, comm []byte, inclusionBlock uint64) error {
+	if svr.recencyWindow == 0 || meta.Mode == commitments.OptimismKeccak {
+		return nil
+	}
+
+	// certs of unregistered versions are rejected the same way gets reject them
+	handler, err := svr.router.CertHandler(commitments.CertEncodingCommitment(meta.CertVersion))
+	if err != nil {
+		return err
+	}
+	if handler.ReferenceBlocks == nil {
+		svr.log.Debug("Certs of the version have no reference block, skipping recency check", "version", meta.CertVersion)
+		return nil
+	}
+
+	referenceBlocks, err := handler.ReferenceBlocks(comm)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode cert: %w", verify.ErrInvalidCert, err)
+	}
+	for _, referenceBlock := range referenceBlocks {
+		if err := verify.CheckRecency(referenceBlock, inclusionBlock, svr.recencyWindow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecencyWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().CertHandler(commitments.CertV0).Return(certV0Handler(nil), nil).AnyTimes()
+	server := NewServerWithJobStore("localhost", 8080, mockRouter, jobs.NewMemoryStore(DefaultJobExpiration), jobs.Config{},
+		SecurityOverridesConfig{}, 100, log.New(), metrics.NoopMetrics)
+
+	cert, err := rlp.EncodeToBytes(&verify.Certificate{
+		BlobHeader: &disperser.BlobHeader{Commitment: &common.G1Commitment{X: []byte{1}, Y: []byte{2}}},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:         &disperser.BatchHeader{BatchRoot: make([]byte, 32), ReferenceBlockNumber: 1000},
+				SignatoryRecordHash: make([]byte, 32),
+			},
+		},
+	})
+	require.NoError(t, err)
+	certURL := fmt.Sprintf("/get/0x010000%x", cert)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		_, _ = server.HandleGet(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	t.Run("WithinWindow", func(t *testing.T) {
+		for _, block := range []uint64{1001, 1100} {
+			mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("payload"), nil)
+			rec := get(fmt.Sprintf("%s?l1_inclusion_block=%d", certURL, block))
+			require.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("NoInclusionBlock", func(t *testing.T) {
+		mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("payload"), nil)
+		require.Equal(t, http.StatusOK, get(certURL).Code)
+	})
+
+	// the blob isn't read for certs outside of the window
+	t.Run("OutsideWindow", func(t *testing.T) {
+		rec := get(certURL + "?l1_inclusion_block=1101")
+		require.Equal(t, http.StatusTeapot, rec.Code)
+
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Equal(t, ErrorCodeRecencyWindowExceeded, body.Code)
+	})
+
+	// certs can't be included at or before their reference block
+	t.Run("NotAfterReferenceBlock", func(t *testing.T) {
+		for _, block := range []uint64{999, 1000} {
+			rec := get(fmt.Sprintf("%s?l1_inclusion_block=%d", certURL, block))
+			require.Equal(t, http.StatusTeapot, rec.Code)
+		}
+	})
+
+	t.Run("InvalidInclusionBlock", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, get(certURL+"?l1_inclusion_block=latest").Code)
+	})
+
+	t.Run("MalformedCert", func(t *testing.T) {
+		rec := get(fmt.Sprintf("/get/0x010000%s?l1_inclusion_block=1001", testCommitStr))
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	// certs of other versions are checked against the reference blocks their handler reads
+	t.Run("RegisteredCertVersion", func(t *testing.T) {
+		v1URL := fmt.Sprintf("/get/0x010001%s", testCommitStr)
+		mockRouter.EXPECT().CertHandler(commitments.CertEncodingCommitment(1)).Return(store.CertHandler{
+			ReferenceBlocks: func([]byte) ([]uint64, error) { return []uint64{1000}, nil },
+		}, nil).Times(2)
+
+		mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("payload"), nil)
+		require.Equal(t, http.StatusOK, get(v1URL+"?l1_inclusion_block=1100").Code)
+		require.Equal(t, http.StatusTeapot, get(v1URL+"?l1_inclusion_block=1101").Code)
+	})
+
+	// and aren't checked when their handler can't read them, rather than being reported as invalid
+	t.Run("CertVersionWithoutReferenceBlocks", func(t *testing.T) {
+		mockRouter.EXPECT().CertHandler(commitments.CertEncodingCommitment(2)).Return(store.CertHandler{}, nil)
+		mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("payload"), nil)
+		require.Equal(t, http.StatusOK, get(fmt.Sprintf("/get/0x010002%s?l1_inclusion_block=5000", testCommitStr)).Code)
+	})
+
+	t.Run("UnregisteredCertVersion", func(t *testing.T) {
+		mockRouter.EXPECT().CertHandler(commitments.CertEncodingCommitment(3)).
+			Return(store.CertHandler{}, fmt.Errorf("%w: unsupported cert version 3", verify.ErrInvalidCert))
+		rec := get(fmt.Sprintf("/get/0x010003%s?l1_inclusion_block=1001", testCommitStr))
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	// keccak256 commitments have no cert to check
+	t.Run("Keccak", func(t *testing.T) {
+		mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("payload"), nil)
+		rec := get(fmt.Sprintf("/get/0x00%s?l1_inclusion_block=5000", testCommitStr))
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+}
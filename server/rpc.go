@@ -0,0 +1,433 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// RPCRoute ... route of the JSON-RPC APIs
+	RPCRoute = "/rpc"
+	// DANamespace ... JSON-RPC namespace of the API equivalent to the REST routes
+	DANamespace = "da"
+	// MaxBatchGetSize ... max number of commitments read by a single da_batchGet call
+	MaxBatchGetSize = 100
+
+	// JSON-RPC request bodies hold hex or base64 encoded payloads, which are larger than the payload
+	rpcBodyLimit = 128 * 1024 * 1024
+
+	// JSON-RPC error codes of calls with invalid params, and of calls that failed otherwise
+	rpcInvalidParamsCode = -32602
+	rpcServerErrorCode   = -32000
+)
+
+// newRPCServer ... JSON-RPC server of the APIs that share the router and metrics of the REST routes
+func (svr *Server) newRPCServer() (*rpc.Server, error) {
+	rpcServer := rpc.NewServer()
+	rpcServer.SetHTTPBodyLimit(rpcBodyLimit)
+	apis := map[string]interface{}{
+		NitroNamespace: &NitroAPI{svr: svr},
+		DANamespace:    &DAAPI{svr: svr},
+	}
+	for namespace, api := range apis {
+		if err := rpcServer.RegisterName(namespace, api); err != nil {
+			return nil, fmt.Errorf("failed to register %s API: %w", namespace, err)
+		}
+	}
+	return rpcServer, nil
+}
+
+// RPCErrorData ... data of the errors of failed JSON-RPC calls, which holds the category of the error
+// that the REST routes respond with
+type RPCErrorData struct {
+	Code   ErrorCode `json:"code"`
+	Status int       `json:"status"`
+}
+
+// rpcError ... error of a failed JSON-RPC call, whose code and data are read by the rpc server
+type rpcError struct {
+	err  error
+	data RPCErrorData
+}
+
+func (e *rpcError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rpcError) Unwrap() error {
+	return e.err
+}
+
+func (e *rpcError) ErrorCode() int {
+	if e.data.Code == ErrorCodeBadRequest {
+		return rpcInvalidParamsCode
+	}
+	return rpcServerErrorCode
+}
+
+func (e *rpcError) ErrorData() interface{} {
+	return e.data
+}
+
+// badParams marks the error of a call with invalid params, which is a bad request
+func badParams(err error) error {
+	return &rpcError{err: err, data: RPCErrorData{Code: ErrorCodeBadRequest, Status: http.StatusBadRequest}}
+}
+
+// newRPCError returns the error with the category of the error it wraps (see errorCategories). The rpc
+// server only reads the code and data of errors that aren't wrapped.
+func newRPCError(err error) *rpcError {
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		return &rpcError{err: err, data: rpcErr.data}
+	}
+	status, code := errorStatus(err)
+	return &rpcError{err: err, data: RPCErrorData{Code: code, Status: status}}
+}
+
+// recordRPC starts recording the metrics of a JSON-RPC method call. Once the call returns, its metrics
+// are labeled with the HTTP status of the category of its error, which is returned as a JSON-RPC error.
+func (svr *Server) recordRPC(method string) func(meta commitments.CommitmentMeta, err error) error {
+	recordDur := svr.m.RecordRPCServerRequest(method)
+	return func(meta commitments.CommitmentMeta, err error) error {
+		status := http.StatusOK
+		if err != nil {
+			rpcErr := newRPCError(err)
+			status = rpcErr.data.Status
+			svr.log.Info("rpc request failed", "method", method, "code", rpcErr.data.Code, "err", err)
+			err = rpcErr
+		}
+		recordDur(strconv.Itoa(status), string(meta.Mode), string(meta.CertVersion))
+		return err
+	}
+}
+
+// PayloadEncoding ... encoding of the payloads of JSON-RPC requests and responses
+type PayloadEncoding string
+
+const (
+	// HexPayloadEncoding ... 0x prefixed hex strings, the default
+	HexPayloadEncoding PayloadEncoding = "hex"
+	// Base64PayloadEncoding ... standard base64 strings, which are a third smaller than hex strings
+	Base64PayloadEncoding PayloadEncoding = "base64"
+)
+
+// StringToPayloadEncoding ... parses a payload encoding, which defaults to hex
+func StringToPayloadEncoding(s string) (PayloadEncoding, error) {
+	switch PayloadEncoding(s) {
+	case "", HexPayloadEncoding:
+		return HexPayloadEncoding, nil
+	case Base64PayloadEncoding:
+		return Base64PayloadEncoding, nil
+	default:
+		return "", fmt.Errorf("unknown payload encoding %q, expected %q or %q", s, HexPayloadEncoding, Base64PayloadEncoding)
+	}
+}
+
+func (e PayloadEncoding) decode(s string) ([]byte, error) {
+	if e == Base64PayloadEncoding {
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return hexutil.Decode(s)
+}
+
+func (e PayloadEncoding) encode(b []byte) string {
+	if e == Base64PayloadEncoding {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return hexutil.Encode(b)
+}
+
+// PutOptions ... optional params of da_put, equivalent to the query params of put requests
+type PutOptions struct {
+	// defaults to simple
+	CommitmentMode commitments.CommitmentMode `json:"commitment_mode,omitempty"`
+	Encoding       PayloadEncoding            `json:"encoding,omitempty"`
+
+	// security params that the blob must be dispersed with (see SecurityOverridesConfig)
+	Quorums               []uint8 `json:"quorums,omitempty"`
+	AdversaryThreshold    uint8   `json:"adversary_threshold,omitempty"`
+	ConfirmationThreshold uint8   `json:"confirmation_threshold,omitempty"`
+}
+
+// GetOptions ... optional params of the da methods that read commitments, equivalent to the query params
+// of get requests
+type GetOptions struct {
+	// defaults to simple
+	CommitmentMode commitments.CommitmentMode `json:"commitment_mode,omitempty"`
+	Encoding       PayloadEncoding            `json:"encoding,omitempty"`
+	// L1 block that the commitment was included in, which its certs are checked against the recency window with
+	L1InclusionBlock *uint64 `json:"l1_inclusion_block,omitempty"`
+}
+
+// PutResult ... commitment of a put payload
+type PutResult struct {
+	Commitment hexutil.Bytes `json:"commitment"`
+}
+
+// GetResult ... payload of a commitment
+type GetResult struct {
+	Payload  string          `json:"payload"`
+	Encoding PayloadEncoding `json:"encoding"`
+}
+
+// BatchGetResult ... payload of a commitment of a da_batchGet call, or why it couldn't be read
+type BatchGetResult struct {
+	Payload string         `json:"payload,omitempty"`
+	Error   *ErrorResponse `json:"error,omitempty"`
+}
+
+// CertInfoResult ... certs of a commitment
+type CertInfoResult struct {
+	CommitmentMode commitments.CommitmentMode `json:"commitment_mode"`
+	CertVersion    byte                       `json:"cert_version"`
+	*CertKey
+}
+
+// DAAPI ... JSON-RPC API equivalent to the REST routes, whose commitment modes are set in the options of
+// every call instead of in query params
+type DAAPI struct {
+	svr *Server
+}
+
+// readRPCMeta returns the commitment meta of a commitment and the key it holds
+func readRPCMeta(commitment []byte, mode commitments.CommitmentMode) (commitments.CommitmentMeta, []byte, error) {
+	if mode == "" {
+		mode = commitments.SimpleCommitmentMode
+	}
+	mode, err := commitments.StringToCommitmentMode(string(mode))
+	if err != nil {
+		return commitments.CommitmentMeta{}, nil, badParams(err)
+	}
+	meta := commitments.CommitmentMeta{Mode: mode}
+
+	meta.CertVersion, err = commitmentCertVersion(commitment, mode)
+	if err != nil {
+		return meta, nil, badParams(err)
+	}
+	key, err := commitments.DecodeCommitment(commitment, mode)
+	if err != nil {
+		return meta, nil, badParams(fmt.Errorf("failed to decode commitment %x (commitment mode %v): %w", commitment, mode, err))
+	}
+	return meta, key, nil
+}
+
+// Put disperses a payload and returns its commitment
+func (api *DAAPI) Put(ctx context.Context, data string, opts *PutOptions) (res *PutResult, err error) {
+	if opts == nil {
+		opts = &PutOptions{}
+	}
+	meta := commitments.CommitmentMeta{Mode: opts.CommitmentMode}
+	record := api.svr.recordRPC(DANamespace + "_put")
+	defer func() { err = record(meta, err) }()
+
+	if meta.Mode == "" {
+		meta.Mode = commitments.SimpleCommitmentMode
+	}
+	if meta.Mode, err = commitments.StringToCommitmentMode(string(meta.Mode)); err != nil {
+		return nil, badParams(err)
+	}
+	encoding, err := StringToPayloadEncoding(string(opts.Encoding))
+	if err != nil {
+		return nil, badParams(err)
+	}
+	payload, err := encoding.decode(data)
+	if err != nil {
+		return nil, badParams(fmt.Errorf("failed to decode payload: %w", err))
+	}
+
+	// keccak256 commitments are the hash of the payload, other commitments hold a cert of the version
+	// configured on the router
+	var key []byte
+	if meta.Mode == commitments.OptimismKeccak {
+		key = crypto.Keccak256(payload)
+	} else {
+		meta.CertVersion = byte(api.svr.router.PutCertVersion())
+	}
+
+	params := verify.SecurityParams{
+		QuorumIDs:             opts.Quorums,
+		AdversaryThreshold:    opts.AdversaryThreshold,
+		ConfirmationThreshold: opts.ConfirmationThreshold,
+	}
+	err = params.Check()
+	if err == nil {
+		err = api.svr.securityOverrides.allow(params)
+	}
+	if err != nil {
+		return nil, badParams(fmt.Errorf("invalid security params: %w", err))
+	}
+
+	commit, err := api.svr.router.Put(verify.WithSecurityParams(ctx, params), meta, key, payload)
+	if err != nil {
+		err = fmt.Errorf("put request failed (commitment mode %v): %w", meta.Mode, err)
+		if errors.Is(err, store.ErrEigenDAOversizedBlob) || errors.Is(err, store.ErrProxyOversizedBlob) {
+			return nil, badParams(err)
+		}
+		return nil, err
+	}
+
+	commitment, err := commitments.EncodeCommitment(commit, meta.Mode, commitments.CertEncodingCommitment(meta.CertVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode commitment %x (commitment mode %v): %w", commit, meta.Mode, err)
+	}
+	return &PutResult{Commitment: commitment}, nil
+}
+
+// get reads the payload of a commitment, after checking its certs against the recency window
+func (api *DAAPI) get(ctx context.Context, commitment []byte, opts *GetOptions) (commitments.CommitmentMeta, []byte, error) {
+	meta, key, err := readRPCMeta(commitment, opts.CommitmentMode)
+	if err != nil {
+		return meta, nil, err
+	}
+
+	if opts.L1InclusionBlock != nil {
+		if err := api.svr.checkRecency(meta, key, *opts.L1InclusionBlock); err != nil {
+			return meta, nil, fmt.Errorf("recency check failed for commitment %x (l1 inclusion block %d): %w",
+				commitment, *opts.L1InclusionBlock, err)
+		}
+	}
+
+	payload, err := api.svr.router.Get(ctx, key, meta)
+	if err != nil {
+		return meta, nil, fmt.Errorf("get request failed with commitment %x (commitment mode %v): %w", commitment, meta.Mode, err)
+	}
+	return meta, payload, nil
+}
+
+// Get returns the payload of a commitment
+func (api *DAAPI) Get(ctx context.Context, commitment hexutil.Bytes, opts *GetOptions) (res *GetResult, err error) {
+	if opts == nil {
+		opts = &GetOptions{}
+	}
+	var meta commitments.CommitmentMeta
+	record := api.svr.recordRPC(DANamespace + "_get")
+	defer func() { err = record(meta, err) }()
+
+	encoding, err := StringToPayloadEncoding(string(opts.Encoding))
+	if err != nil {
+		return nil, badParams(err)
+	}
+	meta, payload, err := api.get(ctx, commitment, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &GetResult{Payload: encoding.encode(payload), Encoding: encoding}, nil
+}
+
+// BatchGet returns the payloads of several commitments of the same commitment mode, which are read
+// concurrently. Commitments that can't be read have the error they failed with instead of a payload.
+func (api *DAAPI) BatchGet(ctx context.Context, commits []hexutil.Bytes, opts *GetOptions) (res []*BatchGetResult, err error) {
+	if opts == nil {
+		opts = &GetOptions{}
+	}
+	meta := commitments.CommitmentMeta{Mode: opts.CommitmentMode}
+	if meta.Mode == "" {
+		meta.Mode = commitments.SimpleCommitmentMode
+	}
+	record := api.svr.recordRPC(DANamespace + "_batchGet")
+	defer func() { err = record(meta, err) }()
+
+	if len(commits) > MaxBatchGetSize {
+		return nil, badParams(fmt.Errorf("%d commitments are more than the max batch size %d", len(commits), MaxBatchGetSize))
+	}
+	encoding, err := StringToPayloadEncoding(string(opts.Encoding))
+	if err != nil {
+		return nil, badParams(err)
+	}
+
+	res = make([]*BatchGetResult, len(commits))
+	var wg sync.WaitGroup
+	for i, commitment := range commits {
+		wg.Add(1)
+		go func(i int, commitment []byte) {
+			defer wg.Done()
+			_, payload, err := api.get(ctx, commitment, opts)
+			if err != nil {
+				data := newRPCError(err).data
+				res[i] = &BatchGetResult{Error: &ErrorResponse{Code: data.Code, Message: err.Error()}}
+				return
+			}
+			res[i] = &BatchGetResult{Payload: encoding.encode(payload)}
+		}(i, commitment)
+	}
+	wg.Wait()
+	return res, nil
+}
+
+// GetCertInfo decodes the certs of a commitment, without reading or verifying its blob
+func (api *DAAPI) GetCertInfo(_ context.Context, commitment hexutil.Bytes, opts *GetOptions) (res *CertInfoResult, err error) {
+	if opts == nil {
+		opts = &GetOptions{}
+	}
+	var meta commitments.CommitmentMeta
+	record := api.svr.recordRPC(DANamespace + "_getCertInfo")
+	defer func() { err = record(meta, err) }()
+
+	meta, certKey, err := decodeRPCCerts(commitment, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CertInfoResult{CommitmentMode: meta.Mode, CertVersion: meta.CertVersion, CertKey: certKey}, nil
+}
+
+// Verify runs every verification step on the certs of a commitment, and on the payload against the cert's
+// commitment if one is provided. The report is returned even if the cert is invalid.
+func (api *DAAPI) Verify(ctx context.Context, commitment hexutil.Bytes, data *string, opts *GetOptions) (res *verify.CertReports, err error) {
+	if opts == nil {
+		opts = &GetOptions{}
+	}
+	var meta commitments.CommitmentMeta
+	record := api.svr.recordRPC(DANamespace + "_verify")
+	defer func() { err = record(meta, err) }()
+
+	meta, certKey, err := decodeRPCCerts(commitment, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if data != nil {
+		encoding, err := StringToPayloadEncoding(string(opts.Encoding))
+		if err != nil {
+			return nil, badParams(err)
+		}
+		if payload, err = encoding.decode(*data); err != nil {
+			return nil, badParams(fmt.Errorf("failed to decode payload: %w", err))
+		}
+		// the blob of an aggregated frame or chunked payload part isn't the payload of the key
+		if certKey.Frame != nil || certKey.Manifest != nil {
+			return nil, badParams(fmt.Errorf("payloads can only be verified against commitments of a single cert, not of an aggregated frame or chunked payload"))
+		}
+	}
+	return api.svr.reportCerts(ctx, certKey, payload)
+}
+
+// decodeRPCCerts decodes the certs of a commitment, which keccak256 commitments don't have and only
+// commitments of V0 certs can be decoded into
+func decodeRPCCerts(commitment []byte, opts *GetOptions) (commitments.CommitmentMeta, *CertKey, error) {
+	meta, key, err := readRPCMeta(commitment, opts.CommitmentMode)
+	if err != nil {
+		return meta, nil, err
+	}
+	if meta.Mode == commitments.OptimismKeccak {
+		return meta, nil, badParams(fmt.Errorf("%s commitments are the keccak256 hash of the payload, not a cert", meta.Mode))
+	}
+	certKey, err := DecodeVersionedCertKey(commitments.CertEncodingCommitment(meta.CertVersion), key)
+	if err != nil {
+		return meta, nil, err
+	}
+	return meta, certKey, nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDAAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
+	mockRouter.EXPECT().CertHandler(commitments.CertV0).Return(certV0Handler(nil), nil).AnyTimes()
+	server := NewServerWithJobStore("localhost", 8080, mockRouter, jobs.NewMemoryStore(DefaultJobExpiration), jobs.Config{},
+		SecurityOverridesConfig{AllowedQuorumIDs: []uint8{2}}, 100, log.New(), metrics.NoopMetrics)
+
+	rpcServer, err := server.newRPCServer()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(rpcServer)
+	defer httpServer.Close()
+
+	client, err := rpc.Dial(httpServer.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	cert, err := rlp.EncodeToBytes(&verify.Certificate{
+		BlobHeader: &disperser.BlobHeader{Commitment: &common.G1Commitment{X: []byte{1}, Y: []byte{2}}},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:         &disperser.BatchHeader{BatchRoot: make([]byte, 32), ReferenceBlockNumber: 1000},
+				SignatoryRecordHash: make([]byte, 32),
+			},
+		},
+	})
+	require.NoError(t, err)
+	commitment := hexutil.Bytes(append([]byte{byte(commitments.CertV0)}, cert...))
+	simpleMeta := commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode, CertVersion: byte(commitments.CertV0)}
+	payload := []byte("payload")
+
+	ctx := context.Background()
+	requireCode := func(t *testing.T, err error, code ErrorCode) {
+		var dataErr rpc.DataError
+		require.True(t, errors.As(err, &dataErr), err)
+		data, ok := dataErr.ErrorData().(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, string(code), data["code"], err)
+	}
+
+	t.Run("Put", func(t *testing.T) {
+		mockRouter.EXPECT().Put(gomock.Any(), simpleMeta, gomock.Nil(), payload).Return(cert, nil).Times(2)
+
+		var res PutResult
+		require.NoError(t, client.CallContext(ctx, &res, "da_put", hexutil.Encode(payload)))
+		require.Equal(t, commitment, res.Commitment)
+
+		require.NoError(t, client.CallContext(ctx, &res, "da_put", base64.StdEncoding.EncodeToString(payload),
+			PutOptions{Encoding: Base64PayloadEncoding, Quorums: []uint8{2}}))
+		require.Equal(t, commitment, res.Commitment)
+	})
+
+	t.Run("PutInvalidParams", func(t *testing.T) {
+		// errors are returned before calling the router
+		for _, tt := range []struct {
+			data string
+			opts PutOptions
+		}{
+			{hexutil.Encode(payload), PutOptions{CommitmentMode: "unknown"}},
+			{hexutil.Encode(payload), PutOptions{Encoding: "unknown"}},
+			{"payload", PutOptions{}},
+			{"!payload", PutOptions{Encoding: Base64PayloadEncoding}},
+			{hexutil.Encode(payload), PutOptions{Quorums: []uint8{3}}},
+		} {
+			var res PutResult
+			err := client.CallContext(ctx, &res, "da_put", tt.data, tt.opts)
+			requireCode(t, err, ErrorCodeBadRequest)
+		}
+	})
+
+	t.Run("PutOversizedBlob", func(t *testing.T) {
+		mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, store.ErrProxyOversizedBlob)
+
+		var res PutResult
+		err := client.CallContext(ctx, &res, "da_put", hexutil.Encode(payload))
+		requireCode(t, err, ErrorCodeBadRequest)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		mockRouter.EXPECT().Get(gomock.Any(), []byte(cert), simpleMeta).Return(payload, nil).Times(2)
+
+		var res GetResult
+		require.NoError(t, client.CallContext(ctx, &res, "da_get", commitment))
+		require.Equal(t, GetResult{Payload: hexutil.Encode(payload), Encoding: HexPayloadEncoding}, res)
+
+		inclusionBlock := uint64(1100)
+		require.NoError(t, client.CallContext(ctx, &res, "da_get", commitment,
+			GetOptions{Encoding: Base64PayloadEncoding, L1InclusionBlock: &inclusionBlock}))
+		require.Equal(t, GetResult{Payload: base64.StdEncoding.EncodeToString(payload), Encoding: Base64PayloadEncoding}, res)
+	})
+
+	t.Run("GetErrors", func(t *testing.T) {
+		mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, store.ErrNotFound)
+
+		var res GetResult
+		err := client.CallContext(ctx, &res, "da_get", commitment)
+		requireCode(t, err, ErrorCodeNotFound)
+
+		// errors are returned before calling the router
+		inclusionBlock := uint64(1101)
+		err = client.CallContext(ctx, &res, "da_get", commitment, GetOptions{L1InclusionBlock: &inclusionBlock})
+		requireCode(t, err, ErrorCodeRecencyWindowExceeded)
+
+		err = client.CallContext(ctx, &res, "da_get", hexutil.Bytes{0}, GetOptions{})
+		requireCode(t, err, ErrorCodeBadRequest)
+	})
+
+	t.Run("BatchGet", func(t *testing.T) {
+		missing := hexutil.Bytes(append([]byte{byte(commitments.CertV0)}, 1, 2, 3))
+		mockRouter.EXPECT().Get(gomock.Any(), []byte(cert), simpleMeta).Return(payload, nil)
+		mockRouter.EXPECT().Get(gomock.Any(), []byte{1, 2, 3}, simpleMeta).Return(nil, store.ErrNotFound)
+
+		var res []*BatchGetResult
+		require.NoError(t, client.CallContext(ctx, &res, "da_batchGet", []hexutil.Bytes{commitment, missing, {0}}))
+		require.Len(t, res, 3)
+		require.Equal(t, &BatchGetResult{Payload: hexutil.Encode(payload)}, res[0])
+		require.Equal(t, ErrorCodeNotFound, res[1].Error.Code)
+		require.Equal(t, ErrorCodeBadRequest, res[2].Error.Code)
+
+		err := client.CallContext(ctx, &res, "da_batchGet", make([]hexutil.Bytes, MaxBatchGetSize+1))
+		requireCode(t, err, ErrorCodeBadRequest)
+	})
+
+	t.Run("GetCertInfo", func(t *testing.T) {
+		var res CertInfoResult
+		require.NoError(t, client.CallContext(ctx, &res, "da_getCertInfo", commitment))
+		require.Equal(t, commitments.SimpleCommitmentMode, res.CommitmentMode)
+		require.NotNil(t, res.Cert)
+		require.Equal(t, uint32(1000), res.Cert.BlobVerificationProof.BatchMetadata.BatchHeader.ReferenceBlockNumber)
+
+		err := client.CallContext(ctx, &res, "da_getCertInfo", commitment,
+			GetOptions{CommitmentMode: commitments.OptimismKeccak})
+		requireCode(t, err, ErrorCodeBadRequest)
+
+		err = client.CallContext(ctx, &res, "da_getCertInfo", hexutil.Bytes{0, 1, 2})
+		requireCode(t, err, ErrorCodeInvalidCert)
+
+		// certs of other versions can't be decoded, but aren't invalid
+		err = client.CallContext(ctx, &res, "da_getCertInfo", append(hexutil.Bytes{1}, cert...))
+		requireCode(t, err, ErrorCodeBadRequest)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		mockRouter.EXPECT().GetEigenDAStore().Return(nil)
+
+		var res verify.CertReports
+		err := client.CallContext(ctx, &res, "da_verify", commitment, hexutil.Encode(payload))
+		requireCode(t, err, ErrorCodeInternal)
+	})
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+)
+
+// query params and their equivalent headers through which put requests require more security
+// than the network's for their blob
+const (
+	QuorumsKey               = "quorums"
+	AdversaryThresholdKey    = "adversary_threshold"
+	ConfirmationThresholdKey = "confirmation_threshold"
+
+	QuorumsHeader               = "X-EigenDA-Quorums"
+	AdversaryThresholdHeader    = "X-EigenDA-Adversary-Threshold"
+	ConfirmationThresholdHeader = "X-EigenDA-Confirmation-Threshold"
+)
+
+// SecurityOverridesConfig ... security params that put requests are allowed to require
+type SecurityOverridesConfig struct {
+	// quorums that requests may disperse their blob to, in addition to the custom quorums
+	AllowedQuorumIDs []uint8
+	// highest thresholds that requests may require, 0 disallows requiring the threshold
+	MaxAdversaryThreshold    uint8
+	MaxConfirmationThreshold uint8
+}
+
+// Check ... verifies that the max thresholds are percentages
+func (c SecurityOverridesConfig) Check() error {
+	if c.MaxAdversaryThreshold > 100 || c.MaxConfirmationThreshold > 100 {
+		return fmt.Errorf("max security override thresholds must be percentages")
+	}
+	return nil
+}
+
+// allow returns an error if the params aren't within the allowlist
+func (c SecurityOverridesConfig) allow(p verify.SecurityParams) error {
+	for _, q := range p.QuorumIDs {
+		if !slices.Contains(c.AllowedQuorumIDs, q) {
+			return fmt.Errorf("quorum %d isn't allowed, allowed quorums are %v", q, c.AllowedQuorumIDs)
+		}
+	}
+	if p.AdversaryThreshold > c.MaxAdversaryThreshold {
+		return fmt.Errorf("adversary threshold %d is above the allowed %d", p.AdversaryThreshold, c.MaxAdversaryThreshold)
+	}
+	if p.ConfirmationThreshold > c.MaxConfirmationThreshold {
+		return fmt.Errorf("confirmation threshold %d is above the allowed %d", p.ConfirmationThreshold, c.MaxConfirmationThreshold)
+	}
+	return nil
+}
+
+// ReadSecurityParams ... parses the security params that the put request requires, from its query
+// params or headers (query params take precedence)
+func ReadSecurityParams(r *http.Request) (verify.SecurityParams, error) {
+	read := func(key, header string) string {
+		if v := r.URL.Query().Get(key); v != "" {
+			return v
+		}
+		return r.Header.Get(header)
+	}
+
+	var p verify.SecurityParams
+	if v := read(QuorumsKey, QuorumsHeader); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			q, err := strconv.ParseUint(strings.TrimSpace(s), 10, 8)
+			if err != nil {
+				return verify.SecurityParams{}, fmt.Errorf("invalid %s: %w", QuorumsKey, err)
+			}
+			if !slices.Contains(p.QuorumIDs, uint8(q)) {
+				p.QuorumIDs = append(p.QuorumIDs, uint8(q))
+			}
+		}
+	}
+
+	thresholds := []struct {
+		key, header string
+		dst         *uint8
+	}{
+		{AdversaryThresholdKey, AdversaryThresholdHeader, &p.AdversaryThreshold},
+		{ConfirmationThresholdKey, ConfirmationThresholdHeader, &p.ConfirmationThreshold},
+	}
+	for _, t := range thresholds {
+		v := read(t.key, t.header)
+		if v == "" {
+			continue
+		}
+		threshold, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return verify.SecurityParams{}, fmt.Errorf("invalid %s: %w", t.key, err)
+		}
+		*t.dst = uint8(threshold)
+	}
+
+	return p, p.Check()
+}
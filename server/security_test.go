@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSecurityParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		headers  map[string]string
+		expected verify.SecurityParams
+		err      bool
+	}{
+		{
+			name: "None",
+			url:  "/put/",
+		},
+		{
+			name:     "QueryParams",
+			url:      "/put/?quorums=2,3,2&adversary_threshold=40&confirmation_threshold=80",
+			expected: verify.SecurityParams{QuorumIDs: []uint8{2, 3}, AdversaryThreshold: 40, ConfirmationThreshold: 80},
+		},
+		{
+			name:     "Headers",
+			url:      "/put/",
+			headers:  map[string]string{QuorumsHeader: "2", ConfirmationThresholdHeader: "80"},
+			expected: verify.SecurityParams{QuorumIDs: []uint8{2}, ConfirmationThreshold: 80},
+		},
+		{
+			name:     "QueryParamsTakePrecedence",
+			url:      "/put/?confirmation_threshold=90",
+			headers:  map[string]string{ConfirmationThresholdHeader: "80"},
+			expected: verify.SecurityParams{ConfirmationThreshold: 90},
+		},
+		{
+			name: "InvalidQuorum",
+			url:  "/put/?quorums=256",
+			err:  true,
+		},
+		{
+			name: "ThresholdAbove100",
+			url:  "/put/?confirmation_threshold=101",
+			err:  true,
+		},
+		{
+			name: "AdversaryAboveConfirmation",
+			url:  "/put/?adversary_threshold=60&confirmation_threshold=55",
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			params, err := ReadSecurityParams(req)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+// securityParamsMatcher matches contexts carrying the expected security params
+type securityParamsMatcher verify.SecurityParams
+
+func (m securityParamsMatcher) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+	p := verify.SecurityParamsFromContext(ctx)
+	return gomock.Eq(verify.SecurityParams(m)).Matches(p)
+}
+
+func (m securityParamsMatcher) String() string {
+	return "context with security params"
+}
+
+func TestPutSecurityOverrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
+	server := NewServerWithJobStore("localhost", 8080, mockRouter, jobs.NewMemoryStore(DefaultJobExpiration), jobs.Config{}, SecurityOverridesConfig{
+		AllowedQuorumIDs:         []uint8{2},
+		MaxConfirmationThreshold: 90,
+	}, 0, log.New(), metrics.NoopMetrics)
+
+	put := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader([]byte("tenant data")))
+		rec := httptest.NewRecorder()
+		_, _ = server.HandlePut(rec, req)
+		return rec
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		expected := verify.SecurityParams{QuorumIDs: []uint8{2}, ConfirmationThreshold: 90}
+		mockRouter.EXPECT().
+			Put(securityParamsMatcher(expected), commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode}, gomock.Any(), gomock.Any()).
+			Return([]byte(testCommitStr), nil)
+
+		rec := put("/put/?commitment_mode=simple&quorums=2&confirmation_threshold=90")
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	// the router isn't called for requests outside of the allowlist
+	t.Run("QuorumNotAllowed", func(t *testing.T) {
+		rec := put("/put/?commitment_mode=simple&quorums=3")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ThresholdNotAllowed", func(t *testing.T) {
+		rec := put("/put/?commitment_mode=simple&adversary_threshold=40")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,25 +11,38 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
 	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound = store.ErrNotFound
 )
 
 const (
-	GetRoute = "/get/"
-	PutRoute = "/put/"
-	Put      = "put"
+	GetRoute       = "/get/"
+	PutRoute       = "/put/"
+	PutStatusRoute = "/put/status/"
+	VerifyRoute    = "/verify/"
+	Put            = "put"
 
 	CommitmentModeKey = "commitment_mode"
+	AsyncKey          = "async"
+
+	// how long jobs are kept by the default in-memory job store
+	DefaultJobExpiration = 1 * time.Hour
+	// number of jobs run concurrently and waiting for a worker when the jobs config leaves them unset
+	DefaultJobWorkers   = 8
+	DefaultJobQueueSize = 100
 )
 
 type Server struct {
@@ -38,12 +52,42 @@ type Server struct {
 	m          metrics.Metricer
 	httpServer *http.Server
 	listener   net.Listener
+	rpcServer  *rpc.Server
+
+	// asynchronous dispersal jobs outlive the request that created them and are only
+	// canceled when the server stops
+	jobs       jobs.Store
+	jobQueue   chan *putJob
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	jobsWg     sync.WaitGroup
+
+	// security params that put requests may require of their blob
+	securityOverrides SecurityOverridesConfig
+	// max number of L1 blocks between the reference block of a cert and its L1 inclusion block (0 disables the check)
+	recencyWindow uint64
 }
 
 func NewServer(host string, port int, router store.IRouter, log log.Logger,
 	m metrics.Metricer) *Server {
+	return NewServerWithJobStore(host, port, router, jobs.NewMemoryStore(DefaultJobExpiration), jobs.Config{}, SecurityOverridesConfig{}, 0, log, m)
+}
+
+// NewServerWithJobStore ... constructs a server which keeps asynchronous dispersal jobs in the provided store
+// and runs them with the workers and queue size of jobsCfg, lets put requests require the security params within the overrides allowlist, and rejects gets of certs
+// included on L1 more than recencyWindow blocks after their reference block (unless it's 0)
+func NewServerWithJobStore(host string, port int, router store.IRouter, jobStore jobs.Store, jobsCfg jobs.Config,
+	securityOverrides SecurityOverridesConfig, recencyWindow uint64, log log.Logger, m metrics.Metricer) *Server {
 	endpoint := net.JoinHostPort(host, strconv.Itoa(port))
-	return &Server{
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	workers, queueSize := jobsCfg.Workers, jobsCfg.QueueSize
+	if workers <= 0 {
+		workers = DefaultJobWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultJobQueueSize
+	}
+	svr := &Server{
 		m:        m,
 		log:      log,
 		endpoint: endpoint,
@@ -54,7 +98,17 @@ func NewServer(host string, port int, router store.IRouter, log log.Logger,
 			// aligned with existing blob finalization times
 			WriteTimeout: 40 * time.Minute,
 		},
+		jobs:              jobStore,
+		jobQueue:          make(chan *putJob, queueSize),
+		jobsCtx:           jobsCtx,
+		cancelJobs:        cancelJobs,
+		securityOverrides: securityOverrides,
+		recencyWindow:     recencyWindow,
 	}
+	for i := 0; i < workers; i++ {
+		go svr.runJobWorker()
+	}
+	return svr
 }
 
 // WithMetrics is a middleware that records metrics for the route path.
@@ -81,28 +135,58 @@ func WithMetrics(
 	}
 }
 
-// WithLogging is a middleware that logs the request method and URL.
+// WithLogging is a middleware that logs the request method and URL. Errors of handlers that didn't
+// write a response are written as the JSON error body of their category.
 func WithLogging(
 	handleFn func(http.ResponseWriter, *http.Request) error,
 	log log.Logger,
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log.Info("request", "method", r.Method, "url", r.URL)
-		err := handleFn(w, r)
-		if err != nil { // #nosec G104
-			w.Write([]byte(err.Error())) //nolint:errcheck // ignore error
+		tw := &trackingWriter{ResponseWriter: w}
+		err := handleFn(tw, r)
+		if err != nil {
+			if !tw.written {
+				status, code := errorStatus(err)
+				writeErrorResponse(w, status, code, err)
+			}
 			log.Error(err.Error())
 		}
 	}
 }
 
+// trackingWriter ... records whether a response was written
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *trackingWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
 func (svr *Server) Start() error {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc(GetRoute, WithLogging(WithMetrics(svr.HandleGet, svr.m), svr.log))
 	mux.HandleFunc(PutRoute, WithLogging(WithMetrics(svr.HandlePut, svr.m), svr.log))
+	mux.HandleFunc(PutStatusRoute, WithLogging(svr.HandlePutStatus, svr.log))
+	mux.HandleFunc(VerifyRoute, WithLogging(WithMetrics(svr.HandleVerify, svr.m), svr.log))
 	mux.HandleFunc("/health", WithLogging(svr.Health, svr.log))
 
+	rpcServer, err := svr.newRPCServer()
+	if err != nil {
+		return err
+	}
+	svr.rpcServer = rpcServer
+	mux.Handle(RPCRoute, rpcServer)
+
 	svr.httpServer.Handler = mux
 
 	listener, err := net.Listen("tcp", svr.endpoint)
@@ -144,6 +228,23 @@ func (svr *Server) Stop() error {
 		svr.log.Error("Failed to shutdown proxy server", "err", err)
 		return err
 	}
+	if svr.rpcServer != nil {
+		svr.rpcServer.Stop()
+	}
+
+	// in-flight dispersal jobs are canceled and given until the shutdown deadline to record their failure
+	svr.cancelJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		svr.jobsWg.Wait()
+		close(jobsDone)
+	}()
+
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		svr.log.Warn("Timed out waiting for dispersal jobs to stop")
+	}
 	return nil
 }
 func (svr *Server) Health(w http.ResponseWriter, _ *http.Request) error {
@@ -173,14 +274,31 @@ func (svr *Server) HandleGet(w http.ResponseWriter, r *http.Request) (commitment
 		}
 	}
 
-	input, err := svr.router.Get(r.Context(), comm, meta.Mode)
+	inclusionBlock, ok, err := ReadL1InclusionBlock(r)
 	if err != nil {
-		err = fmt.Errorf("get request failed with commitment %v (commitment mode %v): %w", comm, meta.Mode, err)
-		if errors.Is(err, ErrNotFound) {
-			svr.WriteNotFound(w, err)
-		} else {
-			svr.WriteInternalError(w, err)
+		err = fmt.Errorf("invalid %s query param: %w", L1InclusionBlockKey, err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
 		}
+	}
+	if ok {
+		// certs outside of the recency window are rejected before their blob is read
+		if err := svr.checkRecency(meta, comm, inclusionBlock); err != nil {
+			err = fmt.Errorf("recency check failed for commitment %v (l1 inclusion block %d): %w", comm, inclusionBlock, err)
+			svr.WriteError(w, err)
+			return commitments.CommitmentMeta{}, MetaError{
+				Err:  err,
+				Meta: meta,
+			}
+		}
+	}
+
+	input, err := svr.router.Get(r.Context(), comm, meta)
+	if err != nil {
+		err = fmt.Errorf("get request failed with commitment %v (commitment mode %v): %w", comm, meta.Mode, err)
+		svr.WriteError(w, err)
 		return commitments.CommitmentMeta{}, MetaError{
 			Err:  err,
 			Meta: meta,
@@ -202,10 +320,11 @@ func (svr *Server) HandlePut(w http.ResponseWriter, r *http.Request) (commitment
 		svr.WriteBadRequest(w, err)
 		return commitments.CommitmentMeta{}, err
 	}
-	// ReadCommitmentMeta function invoked inside HandlePut will not return a valid certVersion
-	// Current simple fix is using the hardcoded default value of 0 (also the only supported value)
-	//TODO: smarter decode needed when there's more than one version
-	meta.CertVersion = byte(commitments.CertV0)
+	// the cert version of put requests isn't chosen by the client, certs are dispersed and encoded
+	// with the version configured on the router
+	if meta.Mode != commitments.OptimismKeccak {
+		meta.CertVersion = byte(svr.router.PutCertVersion())
+	}
 
 	input, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -232,25 +351,52 @@ func (svr *Server) HandlePut(w http.ResponseWriter, r *http.Request) (commitment
 		}
 	}
 
-	commitment, err := svr.router.Put(r.Context(), meta.Mode, comm, input)
+	async, err := ReadAsync(r)
+	if err != nil {
+		err = fmt.Errorf("invalid %s query param: %w", AsyncKey, err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
+		}
+	}
+	params, err := ReadSecurityParams(r)
+	if err == nil {
+		err = svr.securityOverrides.allow(params)
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid security params: %w", err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{
+			Err:  err,
+			Meta: meta,
+		}
+	}
+
+	if async {
+		return svr.handleAsyncPut(w, meta, comm, input, params)
+	}
+
+	commitment, err := svr.router.Put(verify.WithSecurityParams(r.Context(), params), meta, comm, input)
 	if err != nil {
 		err = fmt.Errorf("put request failed with commitment %v (commitment mode %v): %w", comm, meta.Mode, err)
 
-		if errors.Is(err, store.ErrEigenDAOversizedBlob) || errors.Is(err, store.ErrProxyOversizedBlob) {
-			// we add here any error that should be returned as a 400 instead of a 500.
-			// currently only includes oversized blob requests
+		if errors.Is(err, store.ErrEigenDAOversizedBlob) || errors.Is(err, store.ErrProxyOversizedBlob) ||
+			(comm != nil && errors.Is(err, verify.ErrCommitmentMismatch)) {
+			// we add here any error that should be returned as a 400 instead of the status of its category.
+			// currently includes oversized blob requests, and provided keccak256 keys that don't match the value
 			svr.WriteBadRequest(w, err)
 			return meta, err
 		}
 
-		svr.WriteInternalError(w, err)
+		svr.WriteError(w, err)
 		return commitments.CommitmentMeta{}, MetaError{
 			Err:  err,
 			Meta: meta,
 		}
 	}
 
-	responseCommit, err := commitments.EncodeCommitment(commitment, meta.Mode)
+	responseCommit, err := commitments.EncodeCommitment(commitment, meta.Mode, commitments.CertEncodingCommitment(meta.CertVersion))
 	if err != nil {
 		err = fmt.Errorf("failed to encode commitment %v (commitment mode %v): %w", commitment, meta.Mode, err)
 		svr.WriteInternalError(w, err)
@@ -276,17 +422,35 @@ func (svr *Server) WriteResponse(w http.ResponseWriter, data []byte) {
 
 func (svr *Server) WriteInternalError(w http.ResponseWriter, err error) {
 	svr.log.Error("internal server error", "err", err)
-	w.WriteHeader(http.StatusInternalServerError)
+	writeErrorResponse(w, http.StatusInternalServerError, ErrorCodeInternal, err)
 }
 
 func (svr *Server) WriteNotFound(w http.ResponseWriter, err error) {
 	svr.log.Info("not found", "err", err)
-	w.WriteHeader(http.StatusNotFound)
+	writeErrorResponse(w, http.StatusNotFound, ErrorCodeNotFound, err)
 }
 
 func (svr *Server) WriteBadRequest(w http.ResponseWriter, err error) {
 	svr.log.Info("bad request", "err", err)
-	w.WriteHeader(http.StatusBadRequest)
+	writeErrorResponse(w, http.StatusBadRequest, ErrorCodeBadRequest, err)
+}
+
+// WriteError writes the error with the HTTP status of its category (see errorCategories)
+func (svr *Server) WriteError(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	if status >= http.StatusInternalServerError {
+		svr.log.Error("request failed", "code", code, "err", err)
+	} else {
+		svr.log.Info("request failed", "code", code, "err", err)
+	}
+	writeErrorResponse(w, status, code, err)
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, code ErrorCode, err error) {
+	body, _ := json.Marshal(ErrorResponse{Code: code, Message: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
 }
 
 func (svr *Server) Port() int {
@@ -350,6 +514,8 @@ func ReadCommitmentMode(r *http.Request) (commitments.CommitmentMode, error) {
 	return commitments.OptimismGeneric, nil
 }
 
+// ReadCommitmentVersion ... reads the cert version byte of the commitment of the request path, which
+// keccak256 commitments and put requests without a commitment don't have
 func ReadCommitmentVersion(r *http.Request, mode commitments.CommitmentMode) (byte, error) {
 	commit := path.Base(r.URL.Path)
 	if len(commit) > 0 && commit != Put { // provided commitment in request params (op keccak256)
@@ -362,15 +528,24 @@ func ReadCommitmentVersion(r *http.Request, mode commitments.CommitmentMode) (by
 			return 0, err
 		}
 
-		if len(decodedCommit) < 3 {
-			return 0, fmt.Errorf("commitment is too short")
-		}
+		return commitmentCertVersion(decodedCommit, mode)
+	}
+	return 0, nil
+}
 
-		if mode == commitments.OptimismGeneric || mode == commitments.SimpleCommitmentMode {
-			return decodedCommit[2], nil
-		}
+// commitmentCertVersion ... reads the cert version byte of a commitment of the commitment mode
+func commitmentCertVersion(commit []byte, mode commitments.CommitmentMode) (byte, error) {
+	if len(commit) < 3 {
+		return 0, fmt.Errorf("commitment is too short")
+	}
 
-		return decodedCommit[0], nil
+	switch mode {
+	case commitments.OptimismGeneric: // [op_type, da_provider, cert_version, ...]
+		return commit[2], nil
+	case commitments.SimpleCommitmentMode: // [cert_version, ...]
+		return commit[0], nil
+	case commitments.ArbitrumNitro: // [header_flag, cert_version, ...]
+		return commit[1], nil
 	}
 	return 0, nil
 }
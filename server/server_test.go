@@ -2,14 +2,20 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/jobs"
 	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -138,8 +144,14 @@ func TestGetHandler(t *testing.T) {
 
 			require.Equal(t, tt.expectedCode, rec.Code)
 			require.Equal(t, tt.expectedCommitmentMeta, meta)
-			require.Equal(t, tt.expectedBody, rec.Body.String())
-
+			if tt.expectError {
+				var body ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				require.NotEmpty(t, body.Code)
+				require.NotEmpty(t, body.Message)
+			} else {
+				require.Equal(t, tt.expectedBody, rec.Body.String())
+			}
 		})
 	}
 	for _, tt := range tests {
@@ -157,11 +169,58 @@ func TestGetHandler(t *testing.T) {
 	}
 }
 
+func TestGetHandlerErrorStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody ErrorCode
+	}{
+		{"NotFound", fmt.Errorf("value %w in s3 bucket", store.ErrNotFound), http.StatusNotFound, ErrorCodeNotFound},
+		{"Expired", fmt.Errorf("commitment key %w", store.ErrExpired), http.StatusGone, ErrorCodeExpired},
+		{"InvalidCert", fmt.Errorf("%w: batch hash mismatch", verify.ErrInvalidCert), http.StatusUnprocessableEntity, ErrorCodeInvalidCert},
+		{"RecencyWindowExceeded", verify.ErrRecencyWindowExceeded, http.StatusTeapot, ErrorCodeRecencyWindowExceeded},
+		{"CommitmentMismatch", verify.ErrCommitmentMismatch, http.StatusBadGateway, ErrorCodeCommitmentMismatch},
+		{"InsufficientDepth", fmt.Errorf("%w: %w", verify.ErrInsufficientDepth, verify.ErrBatchMetadataHashNotFound), http.StatusTooEarly, ErrorCodeInsufficientDepth},
+		{"BackendUnavailable", fmt.Errorf("%w: disperser down", store.ErrBackendUnavailable), http.StatusServiceUnavailable, ErrorCodeBackendUnavailable},
+		{"EthRPCUnavailable", fmt.Errorf("%w: connection refused", verify.ErrEthRPCUnavailable), http.StatusServiceUnavailable, ErrorCodeBackendUnavailable},
+		// the EigenDA error wins over the not found error of the fallbacks
+		{"FallbackNotFound", fmt.Errorf("%w (fallback read failed: %w)", store.ErrBackendUnavailable, store.ErrNotFound), http.StatusServiceUnavailable, ErrorCodeBackendUnavailable},
+		{"Untyped", fmt.Errorf("internal error"), http.StatusInternalServerError, ErrorCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/get/0x010000%s", testCommitStr), nil)
+			rec := httptest.NewRecorder()
+
+			_, err := server.HandleGet(rec, req)
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.expectedCode, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var body ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.Equal(t, tt.expectedBody, body.Code)
+			require.Contains(t, body.Message, tt.err.Error())
+		})
+	}
+}
+
 func TestPutHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
 	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
 
 	tests := []struct {
@@ -299,3 +358,235 @@ func TestPutHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestCertVersionRouting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
+
+	t.Run("GetDispatchesOnCertVersion", func(t *testing.T) {
+		for _, tt := range []struct {
+			url  string
+			meta commitments.CommitmentMeta
+		}{
+			{url: "/get/0x010001" + testCommitStr, meta: commitments.CommitmentMeta{Mode: commitments.OptimismGeneric, CertVersion: 1}},
+			{url: "/get/0x01" + testCommitStr + "?commitment_mode=simple", meta: commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode, CertVersion: 1}},
+		} {
+			mockRouter.EXPECT().Get(gomock.Any(), gomock.Any(), tt.meta).Return([]byte("payload"), nil)
+
+			rec := httptest.NewRecorder()
+			meta, err := server.HandleGet(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			require.NoError(t, err)
+			require.Equal(t, tt.meta, meta)
+			require.Equal(t, []byte("payload"), rec.Body.Bytes())
+		}
+	})
+
+	t.Run("PutEmitsConfiguredCertVersion", func(t *testing.T) {
+		meta := commitments.CommitmentMeta{Mode: commitments.OptimismGeneric, CertVersion: 1}
+		mockRouter.EXPECT().PutCertVersion().Return(commitments.CertEncodingCommitment(1))
+		mockRouter.EXPECT().Put(gomock.Any(), meta, gomock.Any(), gomock.Any()).Return([]byte(testCommitStr), nil)
+
+		rec := httptest.NewRecorder()
+		putMeta, err := server.HandlePut(rec, httptest.NewRequest(http.MethodPut, "/put/", bytes.NewReader([]byte("payload"))))
+		require.NoError(t, err)
+		require.Equal(t, meta, putMeta)
+		require.Equal(t, []byte("\x01\x00\x01"+testCommitStr), rec.Body.Bytes())
+	})
+
+	t.Run("VerifyRejectsUnsupportedCertVersion", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_, err := server.HandleVerify(rec, httptest.NewRequest(http.MethodPost, "/verify/0x010001"+testCommitStr, nil))
+		require.ErrorIs(t, err, ErrUnsupportedCertVersion)
+		require.NotErrorIs(t, err, verify.ErrInvalidCert)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAsyncPutHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
+	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
+
+	// polls the status route until the job is done
+	waitForJob := func(t *testing.T, id string) *jobs.Job {
+		var job jobs.Job
+		require.Eventually(t, func() bool {
+			req := httptest.NewRequest(http.MethodGet, PutStatusRoute+id, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, server.HandlePutStatus(rec, req))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+			return job.Done()
+		}, 5*time.Second, 10*time.Millisecond)
+		return &job
+	}
+
+	putAsync := func(t *testing.T, body []byte) *jobs.Job {
+		req := httptest.NewRequest(http.MethodPost, "/put/?commitment_mode=simple&async=true", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		meta, err := server.HandlePut(rec, req)
+		require.NoError(t, err)
+		require.Equal(t, commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode, CertVersion: 0}, meta)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+
+		var job jobs.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		require.Equal(t, jobs.StatusQueued, job.Status)
+		require.NotEmpty(t, job.ID)
+		return &job
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		release := make(chan struct{})
+		mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ commitments.CommitmentMeta, _, _ []byte) ([]byte, error) {
+				store.ReportPutStatus(ctx, store.PutStatusDispersing)
+				<-release
+				store.ReportPutStatus(ctx, store.PutStatusVerified)
+				return []byte(testCommitStr), nil
+			})
+
+		job := putAsync(t, []byte("some data"))
+
+		// the dispersal is still in flight
+		require.Eventually(t, func() bool {
+			req := httptest.NewRequest(http.MethodGet, PutStatusRoute+job.ID, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, server.HandlePutStatus(rec, req))
+			var inFlight jobs.Job
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &inFlight))
+			return inFlight.Status == jobs.StatusDispersing
+		}, 5*time.Second, 10*time.Millisecond)
+		close(release)
+
+		job = waitForJob(t, job.ID)
+		require.Equal(t, jobs.StatusVerified, job.Status)
+		require.Equal(t, append([]byte(genericPrefix), []byte(testCommitStr)...), []byte(job.Commitment))
+		require.Empty(t, job.Error)
+	})
+
+	t.Run("ConfirmedButNotVerified", func(t *testing.T) {
+		mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ commitments.CommitmentMeta, _, _ []byte) ([]byte, error) {
+				store.ReportPutStatus(ctx, store.PutStatusConfirmed)
+				return []byte(testCommitStr), nil
+			})
+
+		job := waitForJob(t, putAsync(t, []byte("some data")).ID)
+		require.Equal(t, jobs.StatusConfirmed, job.Status)
+		require.NotEmpty(t, job.Commitment)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("internal error"))
+
+		job := waitForJob(t, putAsync(t, []byte("some data")).ID)
+		require.Equal(t, jobs.StatusFailed, job.Status)
+		require.Contains(t, job.Error, "internal error")
+		require.Empty(t, job.Commitment)
+	})
+
+	t.Run("StatusReportedAfterFailureIsIgnored", func(t *testing.T) {
+		reporterCtx := make(chan context.Context, 1)
+		mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ commitments.CommitmentMeta, _, _ []byte) ([]byte, error) {
+				reporterCtx <- ctx
+				return nil, fmt.Errorf("internal error")
+			})
+
+		id := putAsync(t, []byte("some data")).ID
+		require.Equal(t, jobs.StatusFailed, waitForJob(t, id).Status)
+
+		// e.g. the aggregator flushing the batch that the job was part of after it failed
+		store.ReportPutStatus(<-reporterCtx, store.PutStatusVerified)
+
+		job := waitForJob(t, id)
+		require.Equal(t, jobs.StatusFailed, job.Status)
+		require.Contains(t, job.Error, "internal error")
+	})
+
+	t.Run("InvalidAsyncParam", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/put/?commitment_mode=simple&async=maybe", bytes.NewReader([]byte("some data")))
+		rec := httptest.NewRecorder()
+
+		_, err := server.HandlePut(rec, req)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, PutStatusRoute+"deadbeef", nil)
+		rec := httptest.NewRecorder()
+
+		err := server.HandlePutStatus(rec, req)
+		require.ErrorIs(t, err, jobs.ErrJobNotFound)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAsyncPutQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	mockRouter.EXPECT().PutCertVersion().Return(commitments.CertV0).AnyTimes()
+	server := NewServerWithJobStore("localhost", 8080, mockRouter, jobs.NewMemoryStore(DefaultJobExpiration),
+		jobs.Config{Workers: 1, QueueSize: 1}, SecurityOverridesConfig{}, 0, log.New(), metrics.NoopMetrics)
+
+	getJob := func(t *testing.T, id string) *jobs.Job {
+		req := httptest.NewRequest(http.MethodGet, PutStatusRoute+id, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, server.HandlePutStatus(rec, req))
+		var job jobs.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		return &job
+	}
+
+	putAsync := func(t *testing.T) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/put/?commitment_mode=simple&async=true", bytes.NewReader([]byte("some data")))
+		rec := httptest.NewRecorder()
+		_, _ = server.HandlePut(rec, req)
+		return rec
+	}
+
+	release := make(chan struct{})
+	mockRouter.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ commitments.CommitmentMeta, _, _ []byte) ([]byte, error) {
+			<-release
+			return []byte(testCommitStr), nil
+		}).Times(2)
+
+	// the only worker picks up the first job and blocks on it
+	rec := putAsync(t)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var first jobs.Job
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+	require.Eventually(t, func() bool {
+		return getJob(t, first.ID).Status == jobs.StatusDispersing
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the second job waits in the queue
+	rec = putAsync(t)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var second jobs.Job
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &second))
+	require.Equal(t, jobs.StatusQueued, getJob(t, second.ID).Status)
+
+	// the queue is full
+	rec = putAsync(t)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	close(release)
+	for _, id := range []string{first.ID, second.ID} {
+		require.Eventually(t, func() bool {
+			return getJob(t, id).Status == jobs.StatusVerified
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/aggregator"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/chunker"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrUnsupportedCertVersion ... returned for commitments whose certs are of a version that can't be
+// decoded into V0 certs, e.g. to report on them. Their certs aren't invalid, and may still be served.
+var ErrUnsupportedCertVersion = errors.New("unsupported cert version")
+
+// CertKey ... key of a commitment of the EigenDA backend, decoded into the certs it holds. Keys are
+// either a cert, point to a frame of an aggregated blob or list the parts of a chunked payload.
+type CertKey struct {
+	Frame    *FrameInfo       `json:"aggregated_frame,omitempty"`
+	Manifest *ManifestInfo    `json:"chunk_manifest,omitempty"`
+	Cert     *verify.CertInfo `json:"cert,omitempty"`
+
+	// the cert of the key or the aggregated blob, or the certs of the parts of a chunked payload
+	Certs []*verify.Certificate `json:"-"`
+}
+
+// FrameInfo ... where the payload of a key is in an aggregated blob
+type FrameInfo struct {
+	Index     uint32      `json:"index"`
+	FrameHash common.Hash `json:"frame_hash"`
+}
+
+// ManifestInfo ... the payload of a chunked payload manifest, and the certs of its parts
+type ManifestInfo struct {
+	PayloadHash common.Hash       `json:"payload_hash"`
+	Size        uint64            `json:"size"`
+	Parts       []verify.CertInfo `json:"parts"`
+}
+
+// DecodeCertKey ... decodes a key of the EigenDA backend, stripped of its commitment prefixes
+func DecodeCertKey(key []byte) (*CertKey, error) {
+	var decoded CertKey
+
+	switch {
+	case aggregator.IsKey(key):
+		frame, err := aggregator.DecodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		decoded.Frame = &FrameInfo{Index: frame.Index, FrameHash: frame.FrameHash}
+		key = frame.BlobKey
+
+	case chunker.IsKey(key):
+		manifest, err := chunker.DecodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		decoded.Manifest = &ManifestInfo{PayloadHash: manifest.PayloadHash, Size: manifest.Size}
+		for i, part := range manifest.Parts {
+			cert, err := verify.DecodeCertificate(part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode part %d: %w", i, err)
+			}
+			decoded.Manifest.Parts = append(decoded.Manifest.Parts, cert.Info())
+			decoded.Certs = append(decoded.Certs, cert)
+		}
+		return &decoded, nil
+	}
+
+	cert, err := verify.DecodeCertificate(key)
+	if err != nil {
+		return nil, err
+	}
+	info := cert.Info()
+	decoded.Cert = &info
+	decoded.Certs = []*verify.Certificate{cert}
+	return &decoded, nil
+}
+
+// DecodeVersionedCertKey ... decodes a key of the cert version, which can only be done for V0 certs
+func DecodeVersionedCertKey(version commitments.CertEncodingCommitment, key []byte) (*CertKey, error) {
+	if version != commitments.CertV0 {
+		return nil, fmt.Errorf("%w %d, only certs of version %d can be decoded", ErrUnsupportedCertVersion, version, commitments.CertV0)
+	}
+	certKey, err := DecodeCertKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode cert: %w", verify.ErrInvalidCert, err)
+	}
+	return certKey, nil
+}
+
+// certV0Handler ... handler of V0 certs, which are served by the EigenDA backend (possibly wrapped by
+// the aggregator and chunker)
+func certV0Handler(eigenDA store.GeneratedKeyStore) store.CertHandler {
+	return store.CertHandler{
+		Decode: func(key []byte) error {
+			_, err := DecodeCertKey(key)
+			return err
+		},
+		Store: eigenDA,
+		ReferenceBlocks: func(key []byte) ([]uint64, error) {
+			certKey, err := DecodeCertKey(key)
+			if err != nil {
+				return nil, err
+			}
+			blocks := make([]uint64, len(certKey.Certs))
+			for i, cert := range certKey.Certs {
+				blocks[i] = cert.ReferenceBlock()
+			}
+			return blocks, nil
+		},
+	}
+}
+
+// certReporter returns the EigenDA backend, which may be wrapped by the aggregator and chunker
+func (svr *Server) certReporter() (store.CertReporter, error) {
+	s := svr.router.GetEigenDAStore()
+	for s != nil {
+		if reporter, ok := s.(store.CertReporter); ok {
+			return reporter, nil
+		}
+		wrapper, ok := s.(store.WrappingStore)
+		if !ok {
+			break
+		}
+		s = wrapper.Unwrap()
+	}
+	return nil, errors.New("EigenDA backend is not configured")
+}
+
+// reportCerts runs every verification step on the certs of a key, and on the payload if it isn't nil
+func (svr *Server) reportCerts(ctx context.Context, certKey *CertKey, value []byte) (*verify.CertReports, error) {
+	reporter, err := svr.certReporter()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &verify.CertReports{Valid: true, Reports: make([]*verify.Report, len(certKey.Certs))}
+	for i, cert := range certKey.Certs {
+		resp.Reports[i], err = reporter.ReportCert(ctx, cert, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify cert: %w", err)
+		}
+		resp.Valid = resp.Valid && resp.Reports[i].Valid
+	}
+	return resp, nil
+}
+
+// HandleVerify handles the POST request to verify the cert of a commitment, and the payload in the
+// request body against the cert's commitment if one is provided. The response is a report of every
+// verification step, which is returned with status 200 even if the cert is invalid.
+func (svr *Server) HandleVerify(w http.ResponseWriter, r *http.Request) (commitments.CommitmentMeta, error) {
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("method %s not allowed for verify requests", r.Method)
+		writeErrorResponse(w, http.StatusMethodNotAllowed, ErrorCodeBadRequest, err)
+		return commitments.CommitmentMeta{}, err
+	}
+
+	meta, err := ReadCommitmentMeta(r)
+	if err != nil {
+		err = fmt.Errorf("invalid commitment mode: %w", err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, err
+	}
+	if meta.Mode == commitments.OptimismKeccak {
+		err = fmt.Errorf("%s commitments are the keccak256 hash of the payload, not a cert", meta.Mode)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+
+	key := path.Base(r.URL.Path)
+	comm, err := commitments.StringToDecodedCommitment(key, meta.Mode)
+	if err != nil {
+		err = fmt.Errorf("failed to decode commitment from key %v (commitment mode %v): %w", key, meta.Mode, err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+	// only certs of V0 can be decoded into their blob header and verification proof
+	certKey, err := DecodeVersionedCertKey(commitments.CertEncodingCommitment(meta.CertVersion), comm)
+	if err != nil {
+		err = fmt.Errorf("failed to decode cert from key %v: %w", key, err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+
+	value, err := io.ReadAll(r.Body)
+	if err != nil {
+		err = fmt.Errorf("failed to read request body: %w", err)
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+	if len(value) == 0 {
+		value = nil
+	}
+	// the blob of an aggregated frame or chunked payload part isn't the payload of the key
+	if value != nil && (certKey.Frame != nil || certKey.Manifest != nil) {
+		err = fmt.Errorf("payloads can only be verified against commitments of a single cert, not of an aggregated frame or chunked payload")
+		svr.WriteBadRequest(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+
+	resp, err := svr.reportCerts(r.Context(), certKey, value)
+	if err != nil {
+		svr.WriteInternalError(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal verification report: %w", err)
+		svr.WriteInternalError(w, err)
+		return commitments.CommitmentMeta{}, MetaError{Err: err, Meta: meta}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	svr.WriteResponse(w, body)
+	return meta, nil
+}
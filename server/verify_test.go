@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/mocks"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := mocks.NewMockIRouter(ctrl)
+	server := NewServer("localhost", 8080, mockRouter, log.New(), metrics.NoopMetrics)
+
+	cert, err := rlp.EncodeToBytes(&verify.Certificate{
+		BlobHeader: &disperser.BlobHeader{Commitment: &common.G1Commitment{X: []byte{1}, Y: []byte{2}}},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:         &disperser.BatchHeader{BatchRoot: make([]byte, 32)},
+				SignatoryRecordHash: make([]byte, 32),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         []byte
+		mockBehavior func()
+		expectedCode int
+	}{
+		{
+			name:         "Failure - Method Not Allowed",
+			method:       http.MethodGet,
+			url:          fmt.Sprintf("/verify/0x%x?commitment_mode=simple", append([]byte{0}, cert...)),
+			mockBehavior: func() {},
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "Failure - Keccak Commitment",
+			method:       http.MethodPost,
+			url:          "/verify/0x00" + testCommitStr,
+			mockBehavior: func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Failure - Malformed Cert",
+			method:       http.MethodPost,
+			url:          "/verify/0x00" + testCommitStr + "?commitment_mode=simple",
+			mockBehavior: func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Failure - EigenDA Backend Not Configured",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/verify/0x%x?commitment_mode=simple", append([]byte{0}, cert...)),
+			body:   []byte("some data"),
+			mockBehavior: func() {
+				mockRouter.EXPECT().GetEigenDAStore().Return(nil)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			_, err := server.HandleVerify(rec, req)
+			require.Error(t, err)
+			require.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+)
+
+// CertHandler ... decodes, verifies and retrieves the certs of a single cert version
+type CertHandler struct {
+	// Decode checks that a key holds a well formed cert of the version before it's read, and may be nil
+	Decode func(key []byte) error
+	// Store retrieves and verifies the blobs of certs of the version, and disperses blobs when the
+	// version is the one emitted by puts
+	Store GeneratedKeyStore
+	// ReferenceBlocks returns the L1 reference blocks of the certs of a key, which are checked against
+	// the recency window. It may be nil, in which case certs of the version aren't recency checked.
+	ReferenceBlocks func(key []byte) ([]uint64, error)
+}
+
+// CertRegistry ... cert handlers keyed by the cert version byte of commitments, and the version
+// that new commitments are encoded with
+type CertRegistry struct {
+	handlers   map[commitments.CertEncodingCommitment]CertHandler
+	putVersion commitments.CertEncodingCommitment
+}
+
+func NewCertRegistry() *CertRegistry {
+	return &CertRegistry{
+		handlers:   make(map[commitments.CertEncodingCommitment]CertHandler),
+		putVersion: commitments.CertV0,
+	}
+}
+
+// Register adds the handler of a cert version, which can only be registered once
+func (c *CertRegistry) Register(version commitments.CertEncodingCommitment, handler CertHandler) error {
+	if handler.Store == nil {
+		return fmt.Errorf("cert version %d has no store", version)
+	}
+	if _, ok := c.handlers[version]; ok {
+		return fmt.Errorf("cert version %d is already registered", version)
+	}
+	c.handlers[version] = handler
+	return nil
+}
+
+// SetPutVersion sets the cert version of the commitments returned for puts, which must be registered
+func (c *CertRegistry) SetPutVersion(version commitments.CertEncodingCommitment) error {
+	if _, ok := c.handlers[version]; !ok {
+		return fmt.Errorf("cert version %d is not registered, registered versions are %v", version, c.Versions())
+	}
+	c.putVersion = version
+	return nil
+}
+
+// PutVersion ... cert version of the commitments returned for puts
+func (c *CertRegistry) PutVersion() commitments.CertEncodingCommitment {
+	return c.putVersion
+}
+
+// Handler returns the handler of a cert version. Commitments of unknown versions are invalid certs,
+// since they can't be decoded.
+func (c *CertRegistry) Handler(version commitments.CertEncodingCommitment) (CertHandler, error) {
+	handler, ok := c.handlers[version]
+	if !ok {
+		return CertHandler{}, fmt.Errorf("%w: unsupported cert version %d", verify.ErrInvalidCert, version)
+	}
+	return handler, nil
+}
+
+// Versions ... registered cert versions in ascending order
+func (c *CertRegistry) Versions() []commitments.CertEncodingCommitment {
+	versions := make([]commitments.CertEncodingCommitment, 0, len(c.handlers))
+	for v := range c.handlers {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
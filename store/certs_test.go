@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedStore ... in-memory store whose keys are prefixed with a tag, so that tests can tell which
+// store of a cert version served a request
+type versionedStore struct {
+	tag    byte
+	values map[string][]byte
+}
+
+func newVersionedStore(tag byte) *versionedStore {
+	return &versionedStore{tag: tag, values: make(map[string][]byte)}
+}
+
+func (s *versionedStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	value, ok := s.values[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *versionedStore) Put(_ context.Context, value []byte) ([]byte, error) {
+	key := []byte{s.tag, byte(len(s.values))}
+	s.values[string(key)] = value
+	return key, nil
+}
+
+func (s *versionedStore) Verify(key []byte, _ []byte) error {
+	if key[0] != s.tag {
+		return errors.New("key of another store")
+	}
+	return nil
+}
+
+func (s *versionedStore) Stats() *Stats            { return &Stats{} }
+func (s *versionedStore) BackendType() BackendType { return EigenDABackendType }
+
+func TestCertRegistry(t *testing.T) {
+	certs := NewCertRegistry()
+	require.Equal(t, commitments.CertV0, certs.PutVersion())
+
+	require.NoError(t, certs.Register(commitments.CertV0, CertHandler{Store: newVersionedStore(0)}))
+	require.NoError(t, certs.Register(2, CertHandler{Store: newVersionedStore(2)}))
+	require.Error(t, certs.Register(commitments.CertV0, CertHandler{Store: newVersionedStore(0)}))
+	require.Error(t, certs.Register(3, CertHandler{}))
+	require.Equal(t, []commitments.CertEncodingCommitment{0, 2}, certs.Versions())
+
+	require.Error(t, certs.SetPutVersion(1))
+	require.NoError(t, certs.SetPutVersion(2))
+	require.Equal(t, commitments.CertEncodingCommitment(2), certs.PutVersion())
+
+	_, err := certs.Handler(1)
+	require.ErrorIs(t, err, verify.ErrInvalidCert)
+}
+
+func TestRouterCertVersionDispatch(t *testing.T) {
+	ctx := context.Background()
+	v0, v1 := newVersionedStore(0), newVersionedStore(1)
+
+	certs := NewCertRegistry()
+	require.NoError(t, certs.Register(commitments.CertV0, CertHandler{Store: v0}))
+	require.NoError(t, certs.Register(1, CertHandler{
+		Decode: func(key []byte) error {
+			if len(key) != 2 {
+				return errors.New("malformed key")
+			}
+			return nil
+		},
+		Store: v1,
+	}))
+	require.NoError(t, certs.SetPutVersion(1))
+
+	router, err := NewRouterWithCerts(certs, nil, log.New(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, commitments.CertEncodingCommitment(1), router.PutCertVersion())
+	require.Equal(t, v1, router.GetEigenDAStore())
+
+	v1Meta := commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode, CertVersion: 1}
+	key, err := router.Put(ctx, v1Meta, nil, []byte("v1 payload"))
+	require.NoError(t, err)
+	require.Len(t, v1.values, 1)
+	require.Empty(t, v0.values)
+
+	value, err := router.Get(ctx, key, v1Meta)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1 payload"), value)
+
+	// certs of older versions are still served by their own store
+	v0Key, err := v0.Put(ctx, []byte("v0 payload"))
+	require.NoError(t, err)
+	value, err = router.Get(ctx, v0Key, commitments.CommitmentMeta{Mode: commitments.OptimismGeneric, CertVersion: 0})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v0 payload"), value)
+	value, err = router.Get(ctx, v0Key, commitments.CommitmentMeta{Mode: commitments.ArbitrumNitro, CertVersion: 0})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v0 payload"), value)
+
+	_, err = router.Get(ctx, []byte{1}, v1Meta)
+	require.ErrorIs(t, err, verify.ErrInvalidCert)
+
+	_, err = router.Get(ctx, key, commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode, CertVersion: 7})
+	require.ErrorIs(t, err, verify.ErrInvalidCert)
+}
@@ -0,0 +1,275 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// KeyPrefix ... first byte of keys that point to a frame of an aggregated blob. Keys returned by
+// the EigenDA backends are RLP encoded certs, which always start with a byte >= 0xc0.
+const KeyPrefix byte = 0x01
+
+// keyHeaderSize ... prefix, frame index and frame hash preceding the blob key
+const keyHeaderSize = 1 + 4 + common.HashLength
+
+// Key ... points to a single frame of an aggregated blob
+type Key struct {
+	// index of the frame in the aggregated blob
+	Index uint32
+	// keccak256 hash of the frame, binds the key to the caller's payload
+	FrameHash common.Hash
+	// key of the aggregated blob in the wrapped store (i.e, its RLP encoded cert)
+	BlobKey []byte
+}
+
+// Encode ... [KeyPrefix, index (4 bytes), frame hash (32 bytes), blob key...]
+func (k Key) Encode() []byte {
+	b := make([]byte, 0, keyHeaderSize+len(k.BlobKey))
+	b = append(b, KeyPrefix)
+	b = binary.BigEndian.AppendUint32(b, k.Index)
+	b = append(b, k.FrameHash[:]...)
+	return append(b, k.BlobKey...)
+}
+
+// IsKey ... returns true if the key points to a frame of an aggregated blob
+func IsKey(key []byte) bool {
+	return len(key) > 0 && key[0] == KeyPrefix
+}
+
+// DecodeKey ... decodes a key produced by Key.Encode
+func DecodeKey(key []byte) (Key, error) {
+	if !IsKey(key) {
+		return Key{}, fmt.Errorf("not an aggregated blob key")
+	}
+	if len(key) <= keyHeaderSize {
+		return Key{}, fmt.Errorf("aggregated blob key is too short")
+	}
+
+	return Key{
+		Index:     binary.BigEndian.Uint32(key[1:5]),
+		FrameHash: common.BytesToHash(key[5:keyHeaderSize]),
+		BlobKey:   key[keyHeaderSize:],
+	}, nil
+}
+
+type Config struct {
+	Enabled bool
+	// how long the first payload of a batch waits for others before the batch is dispersed
+	Window time.Duration
+	// size of the framed blob that triggers an early dispersal, larger payloads bypass aggregation
+	MaxBytes uint64
+}
+
+// batch ... payloads that are dispersed together in one blob
+type batch struct {
+	frames [][]byte
+	// contexts of the callers, used to forward the put statuses of the shared dispersal
+	ctxs  []context.Context
+	size  uint64
+	timer *time.Timer
+
+	// closed once the blob is dispersed, key and err are set before
+	done chan struct{}
+	key  []byte
+	err  error
+}
+
+// Store packs payloads that are written within the aggregation window (or byte budget) into a
+// single blob of the wrapped store. Every caller gets a key made of the blob key and the index
+// of its frame, and reads only return the caller's frame.
+type Store struct {
+	inner store.GeneratedKeyStore
+	cfg   Config
+	log   log.Logger
+
+	mu      sync.Mutex
+	pending *batch
+}
+
+var _ store.GeneratedKeyStore = (*Store)(nil)
+var _ store.CertVerifier = (*Store)(nil)
+var _ store.WrappingStore = (*Store)(nil)
+
+// New ... constructor
+func New(inner store.GeneratedKeyStore, cfg Config, l log.Logger) *Store {
+	return &Store{
+		inner: inner,
+		cfg:   cfg,
+		log:   l,
+	}
+}
+
+// Put adds the value to the pending batch and waits for the batch to be dispersed. Values that
+// don't fit in an aggregated blob, or whose put requires its own security params, are written to
+// the wrapped store as is.
+func (s *Store) Put(ctx context.Context, value []byte) ([]byte, error) {
+	if encodedFramesHeaderSize+frameSize(value) > s.cfg.MaxBytes || !verify.SecurityParamsFromContext(ctx).IsZero() {
+		return s.inner.Put(ctx, value)
+	}
+
+	s.mu.Lock()
+	if s.pending != nil && s.pending.size+frameSize(value) > s.cfg.MaxBytes {
+		s.seal(s.pending)
+	}
+	if s.pending == nil {
+		b := &batch{
+			size: encodedFramesHeaderSize,
+			done: make(chan struct{}),
+		}
+		b.timer = time.AfterFunc(s.cfg.Window, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.seal(b)
+		})
+		s.pending = b
+	}
+
+	b := s.pending
+	index := uint32(len(b.frames)) // #nosec G115
+	b.frames = append(b.frames, value)
+	b.ctxs = append(b.ctxs, ctx)
+	b.size += frameSize(value)
+	if b.size >= s.cfg.MaxBytes {
+		s.seal(b)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("aggregated blob wasn't dispersed before the request ended: %w", ctx.Err())
+	case <-b.done:
+	}
+
+	if b.err != nil {
+		return nil, fmt.Errorf("failed to disperse aggregated blob: %w", b.err)
+	}
+
+	return Key{
+		Index:     index,
+		FrameHash: crypto.Keccak256Hash(value),
+		BlobKey:   b.key,
+	}.Encode(), nil
+}
+
+// seal closes the batch to new payloads and disperses it. Must be called with the lock held.
+func (s *Store) seal(b *batch) {
+	if s.pending != b {
+		// already sealed
+		return
+	}
+	s.pending = nil
+	b.timer.Stop()
+	go s.flush(b)
+}
+
+func (s *Store) flush(b *batch) {
+	defer close(b.done)
+
+	// the dispersal is shared and outlives any single caller, progress is reported to all of them
+	ctx := store.WithPutStatusReporter(context.Background(), func(status store.PutStatus) {
+		for _, c := range b.ctxs {
+			store.ReportPutStatus(c, status)
+		}
+	})
+
+	s.log.Debug("Dispersing aggregated blob", "frames", len(b.frames), "size", b.size)
+	b.key, b.err = s.inner.Put(ctx, encodeFrames(b.frames))
+	if b.err != nil {
+		s.log.Error("Failed to disperse aggregated blob", "frames", len(b.frames), "err", b.err)
+		return
+	}
+	s.log.Info("Dispersed aggregated blob", "frames", len(b.frames), "size", b.size)
+}
+
+// Get fetches and verifies the aggregated blob and returns the frame the key points to.
+func (s *Store) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if !IsKey(key) {
+		return s.inner.Get(ctx, key)
+	}
+
+	k, err := DecodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := s.inner.Get(ctx, k.BlobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// the frame can only be checked against the cert as part of the whole blob
+	if err := s.inner.Verify(k.BlobKey, blob); err != nil {
+		return nil, fmt.Errorf("failed to verify aggregated blob: %w", err)
+	}
+
+	frame, err := decodeFrame(blob, k.Index)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyFrame(k, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// Verify checks a frame against the hash in its key, and the cert of the aggregated blob. The blob
+// itself is verified by Get, but frames served from caches and fallbacks are read without it.
+func (s *Store) Verify(key []byte, value []byte) error {
+	if !IsKey(key) {
+		return s.inner.Verify(key, value)
+	}
+
+	k, err := DecodeKey(key)
+	if err != nil {
+		return err
+	}
+	if err := verifyFrame(k, value); err != nil {
+		return err
+	}
+	if err := store.VerifyCert(s.inner, k.BlobKey); err != nil {
+		return fmt.Errorf("failed to verify aggregated blob cert: %w", err)
+	}
+	return nil
+}
+
+// VerifyCert verifies the cert of the aggregated blob the key points to
+func (s *Store) VerifyCert(key []byte) error {
+	if !IsKey(key) {
+		return store.VerifyCert(s.inner, key)
+	}
+
+	k, err := DecodeKey(key)
+	if err != nil {
+		return err
+	}
+	return store.VerifyCert(s.inner, k.BlobKey)
+}
+
+func verifyFrame(k Key, frame []byte) error {
+	if hash := crypto.Keccak256Hash(frame); hash != k.FrameHash {
+		return fmt.Errorf("frame %d hash mismatch: expected %s, got %s", k.Index, k.FrameHash, hash)
+	}
+	return nil
+}
+
+// Unwrap ... returns the wrapped store
+func (s *Store) Unwrap() store.GeneratedKeyStore {
+	return s.inner
+}
+
+func (s *Store) Stats() *store.Stats {
+	return s.inner.Stats()
+}
+
+func (s *Store) BackendType() store.BackendType {
+	return s.inner.BackendType()
+}
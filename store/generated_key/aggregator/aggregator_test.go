@@ -0,0 +1,280 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore ... generated key store keyed by the keccak256 hash of the value, prefixed like an RLP list
+type fakeStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	puts  int
+	err   error
+}
+
+var _ store.GeneratedKeyStore = (*fakeStore)(nil)
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{blobs: make(map[string][]byte)}
+}
+
+func (f *fakeStore) Put(_ context.Context, value []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.puts++
+	key := append([]byte{0xc0}, crypto.Keccak256(value)...)
+	f.blobs[string(key)] = value
+	return key, nil
+}
+
+func (f *fakeStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.blobs[string(key)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (f *fakeStore) Verify(key []byte, value []byte) error {
+	if !bytes.Equal(key[1:], crypto.Keccak256(value)) {
+		return errors.New("value doesn't match key")
+	}
+	return nil
+}
+
+// VerifyCert ... only the keys returned by Put are valid certs
+func (f *fakeStore) VerifyCert(key []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.blobs[string(key)]; !ok {
+		return errors.New("unknown cert")
+	}
+	return nil
+}
+
+func (f *fakeStore) Stats() *store.Stats {
+	return nil
+}
+
+func (f *fakeStore) BackendType() store.BackendType {
+	return store.MemoryBackendType
+}
+
+func putConcurrently(t *testing.T, s *Store, values [][]byte) [][]byte {
+	keys := make([][]byte, len(values))
+	errs := make([]error, len(values))
+
+	var wg sync.WaitGroup
+	for i, v := range values {
+		wg.Add(1)
+		go func(i int, v []byte) {
+			defer wg.Done()
+			keys[i], errs[i] = s.Put(context.Background(), v)
+		}(i, v)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	return keys
+}
+
+func TestAggregatesPayloadsWithinWindow(t *testing.T) {
+	inner := newFakeStore()
+	s := New(inner, Config{Window: 100 * time.Millisecond, MaxBytes: 1024}, log.New())
+
+	values := make([][]byte, 5)
+	for i := range values {
+		values[i] = []byte(fmt.Sprintf("payload %d", i))
+	}
+	keys := putConcurrently(t, s, values)
+	require.Equal(t, 1, inner.puts)
+
+	ctx := context.Background()
+	for i, key := range keys {
+		require.True(t, IsKey(key))
+
+		value, err := s.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, values[i], value)
+		require.NoError(t, s.Verify(key, value))
+		require.Error(t, s.Verify(key, []byte("tampered")))
+	}
+}
+
+// cacheStore ... precomputed key store standing in for a cache or fallback target
+type cacheStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (c *cacheStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[string(key)], nil
+}
+
+func (c *cacheStore) Put(_ context.Context, key []byte, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[string(key)] = value
+	return nil
+}
+
+func (c *cacheStore) Verify(_ []byte, _ []byte) error { return nil }
+func (c *cacheStore) Stats() *store.Stats             { return nil }
+func (c *cacheStore) BackendType() store.BackendType  { return store.RedisBackendType }
+
+func TestCachedFramesAreServedWithVerifiedCerts(t *testing.T) {
+	inner := newFakeStore()
+	cache := &cacheStore{values: make(map[string][]byte)}
+	router, err := store.NewRouter(New(inner, Config{Window: 10 * time.Millisecond, MaxBytes: 1024}, log.New()),
+		nil, log.New(), []store.PrecomputedKeyStore{cache}, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	meta := commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode}
+	payload := []byte("cached payload")
+	key, err := router.Put(ctx, meta, nil, payload)
+	require.NoError(t, err)
+
+	value, err := router.Get(ctx, key, meta)
+	require.NoError(t, err)
+	require.Equal(t, payload, value)
+
+	// a key of the same frame whose cert was tampered with isn't served from the cache, even though
+	// the cache holds a frame matching the key's frame hash
+	k, err := DecodeKey(key)
+	require.NoError(t, err)
+	k.BlobKey = append([]byte{}, k.BlobKey...)
+	k.BlobKey[len(k.BlobKey)-1] ^= 0xff
+	tampered := k.Encode()
+	require.NoError(t, cache.Put(ctx, crypto.Keccak256(tampered), payload))
+
+	_, err = router.Get(ctx, tampered, meta)
+	require.Error(t, err)
+}
+
+func TestFlushesWhenByteBudgetIsReached(t *testing.T) {
+	inner := newFakeStore()
+	// every payload takes 4+16 bytes, so two of them fill a blob
+	s := New(inner, Config{Window: time.Hour, MaxBytes: encodedFramesHeaderSize + 2*20}, log.New())
+
+	values := make([][]byte, 4)
+	for i := range values {
+		values[i] = bytes.Repeat([]byte{byte(i)}, 16)
+	}
+	keys := putConcurrently(t, s, values)
+	require.Equal(t, 2, inner.puts)
+
+	for i, key := range keys {
+		value, err := s.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Equal(t, values[i], value)
+	}
+}
+
+func TestLargePayloadBypassesAggregation(t *testing.T) {
+	inner := newFakeStore()
+	s := New(inner, Config{Window: time.Hour, MaxBytes: 64}, log.New())
+
+	value := bytes.Repeat([]byte{1}, 64)
+	key, err := s.Put(context.Background(), value)
+	require.NoError(t, err)
+	require.False(t, IsKey(key))
+
+	actual, err := s.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, value, actual)
+	require.NoError(t, s.Verify(key, actual))
+}
+
+func TestAggregatedDispersalFailure(t *testing.T) {
+	inner := newFakeStore()
+	inner.err = errors.New("disperser down")
+	s := New(inner, Config{Window: 10 * time.Millisecond, MaxBytes: 1024}, log.New())
+
+	_, err := s.Put(context.Background(), []byte("payload"))
+	require.ErrorContains(t, err, "disperser down")
+}
+
+func TestPutStatusesAreForwardedToEveryCaller(t *testing.T) {
+	reportingStore := &reportingStore{fakeStore: newFakeStore()}
+	s := New(reportingStore, Config{Window: 50 * time.Millisecond, MaxBytes: 1024}, log.New())
+
+	var mu sync.Mutex
+	reported := make(map[int][]store.PutStatus)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := store.WithPutStatusReporter(context.Background(), func(status store.PutStatus) {
+				mu.Lock()
+				defer mu.Unlock()
+				reported[i] = append(reported[i], status)
+			})
+			_, err := s.Put(ctx, []byte{byte(i)})
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 2; i++ {
+		require.Equal(t, []store.PutStatus{store.PutStatusDispersing, store.PutStatusVerified}, reported[i])
+	}
+}
+
+// reportingStore ... reports put statuses like the EigenDA backends do
+type reportingStore struct {
+	*fakeStore
+}
+
+func (r *reportingStore) Put(ctx context.Context, value []byte) ([]byte, error) {
+	store.ReportPutStatus(ctx, store.PutStatusDispersing)
+	key, err := r.fakeStore.Put(ctx, value)
+	if err == nil {
+		store.ReportPutStatus(ctx, store.PutStatusVerified)
+	}
+	return key, err
+}
+
+func TestDecodeFrame(t *testing.T) {
+	frames := [][]byte{{}, []byte("a"), []byte("bcd")}
+	blob := encodeFrames(frames)
+
+	for i, f := range frames {
+		actual, err := decodeFrame(blob, uint32(i))
+		require.NoError(t, err)
+		require.Equal(t, f, actual)
+	}
+
+	_, err := decodeFrame(blob, 3)
+	require.ErrorIs(t, err, errMalformedFrames)
+	_, err = decodeFrame(blob[:len(blob)-1], 2)
+	require.ErrorIs(t, err, errMalformedFrames)
+	_, err = decodeFrame([]byte{1, 0, 0, 0, 1}, 0)
+	require.ErrorIs(t, err, errMalformedFrames)
+}
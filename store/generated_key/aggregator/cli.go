@@ -0,0 +1,76 @@
+package aggregator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	EnabledFlagName  = withFlagPrefix("enabled")
+	WindowFlagName   = withFlagPrefix("window")
+	MaxBytesFlagName = withFlagPrefix("max-bytes")
+)
+
+func withFlagPrefix(s string) string {
+	return "aggregation." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_AGGREGATION_" + s}
+}
+
+// CLIFlags ... used for small payload aggregation configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:     EnabledFlagName,
+			Usage:    "Pack payloads written within the aggregation window into shared EigenDA blobs.",
+			Value:    false,
+			EnvVars:  withEnvPrefix(envPrefix, "ENABLED"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     WindowFlagName,
+			Usage:    "Duration that the first payload of an aggregated blob waits for others before the blob is dispersed.",
+			Value:    2 * time.Second,
+			EnvVars:  withEnvPrefix(envPrefix, "WINDOW"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     MaxBytesFlagName,
+			Usage:    "Size of an aggregated blob that triggers its dispersal before the window ends. Larger payloads are dispersed on their own. Must leave room for the blob encoding overhead below the max blob length. Example units: '128KiB', '1MB'.",
+			Value:    "128KiB",
+			EnvVars:  withEnvPrefix(envPrefix, "MAX_BYTES"),
+			Category: category,
+			Action: func(_ *cli.Context, s string) error {
+				_, err := parseMaxBytes(s)
+				return err
+			},
+		},
+	}
+}
+
+func parseMaxBytes(s string) (uint64, error) {
+	numBytes, err := utils.ParseBytesAmount(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse aggregation max bytes flag: %w", err)
+	}
+	if numBytes == 0 {
+		return 0, fmt.Errorf("aggregation max bytes is 0")
+	}
+	return numBytes, nil
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	// invalid amounts are rejected by the flag action
+	maxBytes, _ := parseMaxBytes(ctx.String(MaxBytesFlagName))
+	return Config{
+		Enabled:  ctx.Bool(EnabledFlagName),
+		Window:   ctx.Duration(WindowFlagName),
+		MaxBytes: maxBytes,
+	}
+}
@@ -0,0 +1,75 @@
+package aggregator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// framesVersion ... first byte of an aggregated blob, identifies the framed layout
+const framesVersion byte = 0
+
+const (
+	// encodedFramesHeaderSize ... size of the version and frame count preceding the frames
+	encodedFramesHeaderSize = 1 + 4
+	// frameHeaderSize ... size of the length prefix of every frame
+	frameHeaderSize = 4
+)
+
+var errMalformedFrames = errors.New("malformed aggregated blob")
+
+// encodeFrames packs payloads into a single blob laid out as
+// [version (1 byte), frame count (4 bytes), {frame length (4 bytes), frame}...]
+// with all integers big-endian.
+func encodeFrames(frames [][]byte) []byte {
+	size := encodedFramesHeaderSize
+	for _, f := range frames {
+		size += frameHeaderSize + len(f)
+	}
+
+	blob := make([]byte, 0, size)
+	blob = append(blob, framesVersion)
+	blob = binary.BigEndian.AppendUint32(blob, uint32(len(frames))) // #nosec G115
+	for _, f := range frames {
+		blob = binary.BigEndian.AppendUint32(blob, uint32(len(f))) // #nosec G115
+		blob = append(blob, f...)
+	}
+	return blob
+}
+
+// decodeFrame returns the frame at the given index of an aggregated blob
+func decodeFrame(blob []byte, index uint32) ([]byte, error) {
+	if len(blob) < encodedFramesHeaderSize {
+		return nil, fmt.Errorf("%w: blob is too short", errMalformedFrames)
+	}
+	if blob[0] != framesVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", errMalformedFrames, blob[0])
+	}
+
+	count := binary.BigEndian.Uint32(blob[1:5])
+	if index >= count {
+		return nil, fmt.Errorf("%w: frame index %d out of range, blob has %d frames", errMalformedFrames, index, count)
+	}
+
+	rest := blob[encodedFramesHeaderSize:]
+	for i := uint32(0); ; i++ {
+		if len(rest) < frameHeaderSize {
+			return nil, fmt.Errorf("%w: frame %d header is truncated", errMalformedFrames, i)
+		}
+		length := uint64(binary.BigEndian.Uint32(rest[:frameHeaderSize]))
+		rest = rest[frameHeaderSize:]
+		if uint64(len(rest)) < length {
+			return nil, fmt.Errorf("%w: frame %d is truncated", errMalformedFrames, i)
+		}
+
+		if i == index {
+			return rest[:length], nil
+		}
+		rest = rest[length:]
+	}
+}
+
+// frameSize ... number of bytes a payload takes up in an aggregated blob
+func frameSize(payload []byte) uint64 {
+	return uint64(frameHeaderSize + len(payload))
+}
@@ -0,0 +1,290 @@
+package chunker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// KeyPrefix ... first byte of manifest keys. Keys returned by the EigenDA backends are RLP encoded
+// certs, which always start with a byte >= 0xc0.
+const KeyPrefix byte = 0x02
+
+// bytesPerSymbol ... size of a bn254 field element in an encoded blob
+const bytesPerSymbol = 32
+
+// Manifest ... lists the blobs a payload was split across
+type Manifest struct {
+	// keccak256 hash and size of the reassembled payload
+	PayloadHash common.Hash
+	Size        uint64
+	// keys of the parts in the wrapped store (i.e, their RLP encoded certs), in payload order
+	Parts [][]byte
+}
+
+// Encode ... [KeyPrefix, RLP encoded manifest...]
+func (m *Manifest) Encode() ([]byte, error) {
+	b, err := rlp.EncodeToBytes(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append([]byte{KeyPrefix}, b...), nil
+}
+
+// IsKey ... returns true if the key is a manifest of a chunked payload
+func IsKey(key []byte) bool {
+	return len(key) > 0 && key[0] == KeyPrefix
+}
+
+// DecodeKey ... decodes a manifest key produced by Manifest.Encode
+func DecodeKey(key []byte) (*Manifest, error) {
+	if !IsKey(key) {
+		return nil, fmt.Errorf("not a manifest key")
+	}
+
+	var m Manifest
+	if err := rlp.DecodeBytes(key[1:], &m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if len(m.Parts) == 0 {
+		return nil, fmt.Errorf("manifest has no parts")
+	}
+	return &m, nil
+}
+
+// MaxPayloadSize returns the largest payload whose encoded blob fits in maxBlobSizeBytes. Blobs
+// are prefixed with a 32 byte codec header, every 31 payload bytes take up a 32 byte field
+// element and the IFFT codec pads the field elements to a power of two.
+func MaxPayloadSize(maxBlobSizeBytes uint64) uint64 {
+	symbols := uint64(1)
+	for symbols*2*bytesPerSymbol <= maxBlobSizeBytes {
+		symbols *= 2
+	}
+	if symbols <= 1 {
+		return 0
+	}
+	return (symbols - 1) * (bytesPerSymbol - 1)
+}
+
+type Config struct {
+	Enabled bool
+	// maximum number of blobs a payload can be split across
+	MaxChunks uint
+}
+
+// Store splits payloads that don't fit in a single blob across several blobs of the wrapped
+// store, which are dispersed in parallel. Callers get a manifest key listing the parts, reads
+// reassemble and verify every part.
+type Store struct {
+	inner     store.GeneratedKeyStore
+	cfg       Config
+	chunkSize uint64
+	log       log.Logger
+}
+
+var _ store.GeneratedKeyStore = (*Store)(nil)
+var _ store.CertVerifier = (*Store)(nil)
+var _ store.WrappingStore = (*Store)(nil)
+
+// New ... constructor, maxBlobSizeBytes is the limit enforced by the wrapped store
+func New(inner store.GeneratedKeyStore, cfg Config, maxBlobSizeBytes uint64, l log.Logger) (*Store, error) {
+	chunkSize := MaxPayloadSize(maxBlobSizeBytes)
+	if chunkSize == 0 {
+		return nil, fmt.Errorf("max blob size %d is too small to split payloads", maxBlobSizeBytes)
+	}
+
+	return &Store{
+		inner:     inner,
+		cfg:       cfg,
+		chunkSize: chunkSize,
+		log:       l,
+	}, nil
+}
+
+// Put writes payloads that fit in a single blob as is, and splits larger ones.
+func (s *Store) Put(ctx context.Context, value []byte) ([]byte, error) {
+	if uint64(len(value)) <= s.chunkSize {
+		return s.inner.Put(ctx, value)
+	}
+
+	count := (uint64(len(value)) + s.chunkSize - 1) / s.chunkSize
+	if count > uint64(s.cfg.MaxChunks) {
+		return nil, fmt.Errorf("%w: payload of %d bytes would take %d blobs, max is %d",
+			store.ErrProxyOversizedBlob, len(value), count, s.cfg.MaxChunks)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// every part reports its own progress, callers only see the progress of the slowest part
+	store.ReportPutStatus(ctx, store.PutStatusDispersing)
+	statuses := make([]store.PutStatus, count)
+	parts := make([][]byte, count)
+	errs := make([]error, count)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := uint64(0); i < count; i++ {
+		end := (i + 1) * s.chunkSize
+		if end > uint64(len(value)) {
+			end = uint64(len(value))
+		}
+		chunk := value[i*s.chunkSize : end]
+
+		wg.Add(1)
+		go func(i uint64) {
+			defer wg.Done()
+
+			partCtx := store.WithPutStatusReporter(ctx, func(status store.PutStatus) {
+				mu.Lock()
+				defer mu.Unlock()
+				statuses[i] = status
+			})
+
+			parts[i], errs[i] = s.inner.Put(partCtx, chunk)
+			if errs[i] != nil {
+				// the payload can't be reassembled without every part
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to disperse part %d of %d: %w", i, count, err)
+		}
+	}
+
+	status := store.PutStatusVerified
+	for _, st := range statuses {
+		if st != store.PutStatusVerified {
+			status = store.PutStatusConfirmed
+		}
+	}
+	store.ReportPutStatus(ctx, status)
+
+	s.log.Info("Dispersed chunked payload", "size", len(value), "parts", count)
+	m := &Manifest{
+		PayloadHash: crypto.Keccak256Hash(value),
+		Size:        uint64(len(value)),
+		Parts:       parts,
+	}
+	return m.Encode()
+}
+
+// Get fetches and verifies every part of a chunked payload in parallel and reassembles it.
+func (s *Store) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if !IsKey(key) {
+		return s.inner.Get(ctx, key)
+	}
+
+	m, err := DecodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parts := make([][]byte, len(m.Parts))
+	errs := make([]error, len(m.Parts))
+
+	var wg sync.WaitGroup
+	for i, partKey := range m.Parts {
+		wg.Add(1)
+		go func(i int, partKey []byte) {
+			defer wg.Done()
+
+			parts[i], errs[i] = s.inner.Get(ctx, partKey)
+			if errs[i] == nil {
+				errs[i] = s.inner.Verify(partKey, parts[i])
+			}
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, partKey)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get part %d of %d: %w", i, len(m.Parts), err)
+		}
+	}
+
+	value := bytes.Join(parts, nil)
+	if err := verifyPayload(m, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Verify checks a reassembled payload against its manifest, and the cert of every part. The parts
+// themselves are verified by Get, but payloads served from caches and fallbacks are read without them.
+func (s *Store) Verify(key []byte, value []byte) error {
+	if !IsKey(key) {
+		return s.inner.Verify(key, value)
+	}
+
+	m, err := DecodeKey(key)
+	if err != nil {
+		return err
+	}
+	if err := verifyPayload(m, value); err != nil {
+		return err
+	}
+	return s.verifyPartCerts(m)
+}
+
+// VerifyCert verifies the cert of every part of a chunked payload
+func (s *Store) VerifyCert(key []byte) error {
+	if !IsKey(key) {
+		return store.VerifyCert(s.inner, key)
+	}
+
+	m, err := DecodeKey(key)
+	if err != nil {
+		return err
+	}
+	return s.verifyPartCerts(m)
+}
+
+func (s *Store) verifyPartCerts(m *Manifest) error {
+	for i, partKey := range m.Parts {
+		if err := store.VerifyCert(s.inner, partKey); err != nil {
+			return fmt.Errorf("failed to verify cert of part %d of %d: %w", i, len(m.Parts), err)
+		}
+	}
+	return nil
+}
+
+func verifyPayload(m *Manifest, value []byte) error {
+	if uint64(len(value)) != m.Size {
+		return fmt.Errorf("reassembled payload size mismatch: expected %d, got %d", m.Size, len(value))
+	}
+	if hash := crypto.Keccak256Hash(value); hash != m.PayloadHash {
+		return fmt.Errorf("reassembled payload hash mismatch: expected %s, got %s", m.PayloadHash, hash)
+	}
+	return nil
+}
+
+// Unwrap ... returns the wrapped store
+func (s *Store) Unwrap() store.GeneratedKeyStore {
+	return s.inner
+}
+
+func (s *Store) Stats() *store.Stats {
+	return s.inner.Stats()
+}
+
+func (s *Store) BackendType() store.BackendType {
+	return s.inner.BackendType()
+}
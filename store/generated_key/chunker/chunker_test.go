@@ -0,0 +1,280 @@
+package chunker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda/api/clients/codecs"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore ... generated key store that enforces the max blob size on the encoded blob like the
+// EigenDA backends do
+type fakeStore struct {
+	maxBlobSizeBytes uint64
+	codec            codecs.BlobCodec
+
+	mu    sync.Mutex
+	blobs map[string][]byte
+	puts  int
+	fail  int
+}
+
+var _ store.GeneratedKeyStore = (*fakeStore)(nil)
+
+func newFakeStore(maxBlobSizeBytes uint64) *fakeStore {
+	return &fakeStore{
+		maxBlobSizeBytes: maxBlobSizeBytes,
+		codec:            codecs.NewIFFTCodec(codecs.NewDefaultBlobCodec()),
+		blobs:            make(map[string][]byte),
+	}
+}
+
+func (f *fakeStore) Put(_ context.Context, value []byte) ([]byte, error) {
+	encoded, err := f.codec.EncodeBlob(value)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(encoded)) > f.maxBlobSizeBytes {
+		return nil, store.ErrProxyOversizedBlob
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail > 0 {
+		f.fail--
+		return nil, errors.New("disperser down")
+	}
+	f.puts++
+	key := append([]byte{0xc0}, crypto.Keccak256(value)...)
+	f.blobs[string(key)] = value
+	return key, nil
+}
+
+func (f *fakeStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.blobs[string(key)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (f *fakeStore) Verify(key []byte, value []byte) error {
+	if !bytes.Equal(key[1:], crypto.Keccak256(value)) {
+		return errors.New("value doesn't match key")
+	}
+	return nil
+}
+
+// VerifyCert ... only the keys returned by Put are valid certs
+func (f *fakeStore) VerifyCert(key []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.blobs[string(key)]; !ok {
+		return errors.New("unknown cert")
+	}
+	return nil
+}
+
+func (f *fakeStore) Stats() *store.Stats {
+	return nil
+}
+
+func (f *fakeStore) BackendType() store.BackendType {
+	return store.MemoryBackendType
+}
+
+func randBytes(t *testing.T, n uint64) []byte {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return b
+}
+
+func TestMaxPayloadSizeFitsEncodedBlob(t *testing.T) {
+	codec := codecs.NewIFFTCodec(codecs.NewDefaultBlobCodec())
+	for _, maxBlobSize := range []uint64{64, 1000, 4096, 100_000, 128 * 1024} {
+		t.Run(fmt.Sprint(maxBlobSize), func(t *testing.T) {
+			size := MaxPayloadSize(maxBlobSize)
+
+			encoded, err := codec.EncodeBlob(make([]byte, size))
+			require.NoError(t, err)
+			require.LessOrEqual(t, uint64(len(encoded)), maxBlobSize)
+
+			encoded, err = codec.EncodeBlob(make([]byte, size+1))
+			require.NoError(t, err)
+			// one more byte takes another field element, which doubles the padded blob
+			require.Greater(t, uint64(len(encoded)), maxBlobSize)
+		})
+	}
+
+	require.Zero(t, MaxPayloadSize(32))
+}
+
+func TestChunkedPutGet(t *testing.T) {
+	const maxBlobSize = 4096
+	inner := newFakeStore(maxBlobSize)
+	s, err := New(inner, Config{MaxChunks: 4}, maxBlobSize, log.New())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// payloads that fit in a single blob are written as is
+	small := randBytes(t, MaxPayloadSize(maxBlobSize))
+	key, err := s.Put(ctx, small)
+	require.NoError(t, err)
+	require.False(t, IsKey(key))
+	require.Equal(t, 1, inner.puts)
+
+	large := randBytes(t, 3*MaxPayloadSize(maxBlobSize)+1)
+	key, err = s.Put(ctx, large)
+	require.NoError(t, err)
+	require.True(t, IsKey(key))
+	require.Equal(t, 5, inner.puts)
+
+	m, err := DecodeKey(key)
+	require.NoError(t, err)
+	require.Len(t, m.Parts, 4)
+	require.Equal(t, uint64(len(large)), m.Size)
+
+	actual, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, large, actual)
+	require.NoError(t, s.Verify(key, actual))
+	require.Error(t, s.Verify(key, actual[1:]))
+}
+
+func TestTooManyChunks(t *testing.T) {
+	const maxBlobSize = 4096
+	s, err := New(newFakeStore(maxBlobSize), Config{MaxChunks: 2}, maxBlobSize, log.New())
+	require.NoError(t, err)
+
+	_, err = s.Put(context.Background(), randBytes(t, 2*MaxPayloadSize(maxBlobSize)+1))
+	require.ErrorIs(t, err, store.ErrProxyOversizedBlob)
+}
+
+func TestFailedPartFailsPut(t *testing.T) {
+	const maxBlobSize = 4096
+	inner := newFakeStore(maxBlobSize)
+	inner.fail = 1
+	s, err := New(inner, Config{MaxChunks: 4}, maxBlobSize, log.New())
+	require.NoError(t, err)
+
+	_, err = s.Put(context.Background(), randBytes(t, 2*MaxPayloadSize(maxBlobSize)))
+	require.ErrorContains(t, err, "disperser down")
+}
+
+func TestTamperedPartFailsGet(t *testing.T) {
+	const maxBlobSize = 4096
+	inner := newFakeStore(maxBlobSize)
+	s, err := New(inner, Config{MaxChunks: 4}, maxBlobSize, log.New())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	key, err := s.Put(ctx, randBytes(t, 2*MaxPayloadSize(maxBlobSize)))
+	require.NoError(t, err)
+	m, err := DecodeKey(key)
+	require.NoError(t, err)
+
+	inner.blobs[string(m.Parts[1])] = []byte("tampered")
+	_, err = s.Get(ctx, key)
+	require.ErrorContains(t, err, "failed to get part 1 of 2")
+}
+
+// cacheStore ... precomputed key store standing in for a cache or fallback target
+type cacheStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (c *cacheStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[string(key)], nil
+}
+
+func (c *cacheStore) Put(_ context.Context, key []byte, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[string(key)] = value
+	return nil
+}
+
+func (c *cacheStore) Verify(_ []byte, _ []byte) error { return nil }
+func (c *cacheStore) Stats() *store.Stats             { return nil }
+func (c *cacheStore) BackendType() store.BackendType  { return store.RedisBackendType }
+
+func TestCachedPayloadsAreServedWithVerifiedPartCerts(t *testing.T) {
+	const maxBlobSize = 4096
+	inner := newFakeStore(maxBlobSize)
+	s, err := New(inner, Config{MaxChunks: 4}, maxBlobSize, log.New())
+	require.NoError(t, err)
+	cache := &cacheStore{values: make(map[string][]byte)}
+	router, err := store.NewRouter(s, nil, log.New(), []store.PrecomputedKeyStore{cache}, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	meta := commitments.CommitmentMeta{Mode: commitments.SimpleCommitmentMode}
+	payload := randBytes(t, 2*MaxPayloadSize(maxBlobSize))
+	key, err := router.Put(ctx, meta, nil, payload)
+	require.NoError(t, err)
+	require.True(t, IsKey(key))
+
+	value, err := router.Get(ctx, key, meta)
+	require.NoError(t, err)
+	require.Equal(t, payload, value)
+
+	// a manifest whose part cert was tampered with isn't served from the cache, even though the
+	// cache holds a payload matching the manifest's payload hash
+	m, err := DecodeKey(key)
+	require.NoError(t, err)
+	m.Parts[1] = append([]byte{}, m.Parts[1]...)
+	m.Parts[1][len(m.Parts[1])-1] ^= 0xff
+	tampered, err := m.Encode()
+	require.NoError(t, err)
+	require.NoError(t, cache.Put(ctx, crypto.Keccak256(tampered), payload))
+
+	_, err = router.Get(ctx, tampered, meta)
+	require.Error(t, err)
+}
+
+func TestChunkedPutReportsSlowestPartStatus(t *testing.T) {
+	const maxBlobSize = 4096
+	s, err := New(&confirmingStore{newFakeStore(maxBlobSize)}, Config{MaxChunks: 4}, maxBlobSize, log.New())
+	require.NoError(t, err)
+
+	var reported []store.PutStatus
+	ctx := store.WithPutStatusReporter(context.Background(), func(status store.PutStatus) {
+		reported = append(reported, status)
+	})
+	_, err = s.Put(ctx, randBytes(t, 2*MaxPayloadSize(maxBlobSize)))
+	require.NoError(t, err)
+	require.Equal(t, []store.PutStatus{store.PutStatusDispersing, store.PutStatusConfirmed}, reported)
+}
+
+// confirmingStore ... reports parts as confirmed but not verified, like the EigenDA store does for
+// certs that didn't reach the confirmation depth yet
+type confirmingStore struct {
+	*fakeStore
+}
+
+func (c *confirmingStore) Put(ctx context.Context, value []byte) ([]byte, error) {
+	store.ReportPutStatus(ctx, store.PutStatusDispersing)
+	key, err := c.fakeStore.Put(ctx, value)
+	if err == nil {
+		store.ReportPutStatus(ctx, store.PutStatusConfirmed)
+	}
+	return key, err
+}
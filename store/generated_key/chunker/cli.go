@@ -0,0 +1,46 @@
+package chunker
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	EnabledFlagName   = withFlagPrefix("enabled")
+	MaxChunksFlagName = withFlagPrefix("max-chunks")
+)
+
+func withFlagPrefix(s string) string {
+	return "chunking." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_CHUNKING_" + s}
+}
+
+// CLIFlags ... used for large payload chunking configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:     EnabledFlagName,
+			Usage:    "Split payloads that exceed the max blob length across several EigenDA blobs, which are dispersed in parallel.",
+			Value:    false,
+			EnvVars:  withEnvPrefix(envPrefix, "ENABLED"),
+			Category: category,
+		},
+		&cli.UintFlag{
+			Name:     MaxChunksFlagName,
+			Usage:    "Maximum number of blobs a payload can be split across, larger payloads are rejected.",
+			Value:    8,
+			EnvVars:  withEnvPrefix(envPrefix, "MAX_CHUNKS"),
+			Category: category,
+		},
+	}
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	return Config{
+		Enabled:   ctx.Bool(EnabledFlagName),
+		MaxChunks: ctx.Uint(MaxChunksFlagName),
+	}
+}
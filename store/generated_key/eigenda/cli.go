@@ -0,0 +1,132 @@
+package eigenda
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	RetryMaxAttemptsFlagName = withFlagPrefix("max-attempts")
+	RetryBackoffFlagName     = withFlagPrefix("backoff")
+	RetryMaxBackoffFlagName  = withFlagPrefix("max-backoff")
+	RetryOnFlagName          = withFlagPrefix("errors")
+
+	DisconnectPolicyFlagName    = withDisconnectFlagPrefix("policy")
+	DisconnectResultTTLFlagName = withDisconnectFlagPrefix("result-ttl")
+)
+
+func withFlagPrefix(s string) string {
+	return "eigenda.retry." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_EIGENDA_RETRY_" + s}
+}
+
+func withDisconnectFlagPrefix(s string) string {
+	return "eigenda.disconnect." + s
+}
+
+func withDisconnectEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_EIGENDA_DISCONNECT_" + s}
+}
+
+// CLIFlags ... used for the dispersal retry policy of the EigenDA store
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	retryOn := make([]string, len(RetryableErrorClasses))
+	for i, class := range RetryableErrorClasses {
+		retryOn[i] = string(class)
+	}
+
+	return []cli.Flag{
+		&cli.UintFlag{
+			Name:     RetryMaxAttemptsFlagName,
+			Usage:    "Maximum number of attempts to disperse a blob to EigenDA, including the first one. 1 disables retries.",
+			Value:    1,
+			EnvVars:  withEnvPrefix(envPrefix, "MAX_ATTEMPTS"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     RetryBackoffFlagName,
+			Usage:    "Delay before retrying a failed dispersal, doubled after every attempt.",
+			Value:    5 * time.Second,
+			EnvVars:  withEnvPrefix(envPrefix, "BACKOFF"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     RetryMaxBackoffFlagName,
+			Usage:    "Maximum delay between dispersal attempts.",
+			Value:    1 * time.Minute,
+			EnvVars:  withEnvPrefix(envPrefix, "MAX_BACKOFF"),
+			Category: category,
+		},
+		&cli.StringSliceFlag{
+			Name:     RetryOnFlagName,
+			Usage:    "Classes of dispersal errors that are retried, options are [disperser, failed, insufficient-signatures, timeout]. failed re-disperses blobs the disperser reported as FAILED.",
+			Value:    cli.NewStringSlice(retryOn...),
+			EnvVars:  withEnvPrefix(envPrefix, "ERRORS"),
+			Category: category,
+			Action: func(_ *cli.Context, classes []string) error {
+				for _, class := range classes {
+					if _, err := StringToErrorClass(class); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func ReadRetryConfig(ctx *cli.Context) RetryConfig {
+	classes := ctx.StringSlice(RetryOnFlagName)
+	retryOn := make([]ErrorClass, 0, len(classes))
+	for _, class := range classes {
+		// invalid classes are rejected by the flag action
+		if c, err := StringToErrorClass(class); err == nil {
+			retryOn = append(retryOn, c)
+		}
+	}
+
+	return RetryConfig{
+		MaxAttempts: ctx.Uint(RetryMaxAttemptsFlagName),
+		Backoff:     ctx.Duration(RetryBackoffFlagName),
+		MaxBackoff:  ctx.Duration(RetryMaxBackoffFlagName),
+		RetryOn:     retryOn,
+	}
+}
+
+// DisconnectCLIFlags ... used for the handling of puts whose client goes away during dispersal
+func DisconnectCLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     DisconnectPolicyFlagName,
+			Usage:    "What happens to a dispersal when the client goes away, options are [cancel, detach]. detach finishes the dispersal in the background and returns its cert to a retried put of the same payload.",
+			Value:    string(DisconnectCancel),
+			EnvVars:  withDisconnectEnvPrefix(envPrefix, "POLICY"),
+			Category: category,
+			Action: func(_ *cli.Context, policy string) error {
+				_, err := StringToDisconnectPolicy(policy)
+				return err
+			},
+		},
+		&cli.DurationFlag{
+			Name:     DisconnectResultTTLFlagName,
+			Usage:    "How long the cert of a detached dispersal is returned to puts of the same payload.",
+			Value:    1 * time.Hour,
+			EnvVars:  withDisconnectEnvPrefix(envPrefix, "RESULT_TTL"),
+			Category: category,
+		},
+	}
+}
+
+func ReadDisconnectConfig(ctx *cli.Context) DisconnectConfig {
+	// invalid policies are rejected by the flag action
+	policy, _ := StringToDisconnectPolicy(ctx.String(DisconnectPolicyFlagName))
+	return DisconnectConfig{
+		Policy:    policy,
+		ResultTTL: ctx.Duration(DisconnectResultTTLFlagName),
+	}
+}
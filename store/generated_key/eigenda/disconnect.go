@@ -0,0 +1,145 @@
+package eigenda
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DisconnectPolicy ... what happens to a dispersal when the client that requested it goes away
+type DisconnectPolicy string
+
+const (
+	// DisconnectCancel ... stop submitting, polling and waiting for the confirmation depth
+	DisconnectCancel DisconnectPolicy = "cancel"
+	// DisconnectDetach ... finish the dispersal in the background and keep its cert for a retried put
+	DisconnectDetach DisconnectPolicy = "detach"
+)
+
+func StringToDisconnectPolicy(s string) (DisconnectPolicy, error) {
+	switch DisconnectPolicy(strings.ToLower(s)) {
+	case DisconnectCancel:
+		return DisconnectCancel, nil
+	case DisconnectDetach:
+		return DisconnectDetach, nil
+	}
+	return "", fmt.Errorf("unknown client disconnect policy: %s", s)
+}
+
+// DisconnectConfig ... handling of puts whose client went away before the dispersal finished
+type DisconnectConfig struct {
+	Policy DisconnectPolicy
+	// how long the cert of a detached dispersal is returned to puts of the same payload
+	ResultTTL time.Duration
+}
+
+// Check ... verifies that detached results are kept long enough for a client to retry
+func (c DisconnectConfig) Check() error {
+	if c.Policy == DisconnectDetach && c.ResultTTL <= 0 {
+		return fmt.Errorf("detached dispersal result TTL must be positive")
+	}
+	return nil
+}
+
+// detachedPut ... dispersal running independently of the puts waiting for it
+type detachedPut struct {
+	done     chan struct{}
+	cert     []byte
+	err      error
+	finished time.Time
+}
+
+// detachedPuts tracks detached dispersals by payload hash, so that puts of a payload that is
+// being dispersed (or was recently) wait for that dispersal instead of paying for another one.
+type detachedPuts struct {
+	mu   sync.Mutex
+	puts map[common.Hash]*detachedPut
+	ttl  time.Duration
+}
+
+func newDetachedPuts(ttl time.Duration) *detachedPuts {
+	return &detachedPuts{puts: make(map[common.Hash]*detachedPut), ttl: ttl}
+}
+
+// start returns the dispersal of the payload hash, and true when the caller must run it
+func (d *detachedPuts) start(hash common.Hash) (*detachedPut, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for h, p := range d.puts {
+		if !p.finished.IsZero() && now.Sub(p.finished) > d.ttl {
+			delete(d.puts, h)
+		}
+	}
+
+	if p, ok := d.puts[hash]; ok {
+		return p, false
+	}
+	p := &detachedPut{done: make(chan struct{})}
+	d.puts[hash] = p
+	return p, true
+}
+
+// finish wakes up the puts waiting for the dispersal. Certs are kept for the TTL while failed
+// dispersals are forgotten so that a retried put disperses the payload again.
+func (d *detachedPuts) finish(hash common.Hash, p *detachedPut, cert []byte, err error) {
+	d.mu.Lock()
+	p.cert, p.err = cert, err
+	if err != nil {
+		delete(d.puts, hash)
+	} else {
+		p.finished = time.Now()
+	}
+	d.mu.Unlock()
+	close(p.done)
+}
+
+// detachedPutKey ... puts are only deduplicated when they require the same security params. The
+// payload is hashed on its own and the quorum IDs are length prefixed, so that the bytes of one
+// field can't be read as part of another (e.g, payload P||0x01 and payload P with quorum 1).
+func detachedPutKey(value []byte, params verify.SecurityParams) common.Hash {
+	var quorumsLen [4]byte
+	binary.BigEndian.PutUint32(quorumsLen[:], uint32(len(params.QuorumIDs)))
+	return crypto.Keccak256Hash(
+		crypto.Keccak256(value),
+		quorumsLen[:],
+		params.QuorumIDs,
+		[]byte{params.AdversaryThreshold, params.ConfirmationThreshold},
+	)
+}
+
+// putDetached runs the dispersal with a context that isn't canceled when the client goes away,
+// and waits for it (or for a dispersal of the same payload that's already running) until it
+// finishes or the client goes away.
+func (e Store) putDetached(ctx context.Context, value []byte) ([]byte, error) {
+	hash := detachedPutKey(value, verify.SecurityParamsFromContext(ctx))
+	p, started := e.detached.start(hash)
+	if started {
+		go func() {
+			cert, err := e.put(context.WithoutCancel(ctx), value)
+			if err == nil && ctx.Err() != nil {
+				e.log.Info("Detached dispersal finished after the client went away, keeping its cert for a retried put",
+					"payloadHash", hash, "ttl", e.cfg.Disconnect.ResultTTL)
+			}
+			e.detached.finish(hash, p, cert, err)
+		}()
+	} else {
+		e.log.Info("Payload is already being dispersed or was recently, reusing that dispersal", "payloadHash", hash)
+	}
+
+	select {
+	case <-p.done:
+		return p.cert, p.err
+	case <-ctx.Done():
+		e.log.Warn("Client went away during dispersal, finishing it in the background", "payloadHash", hash, "err", ctx.Err())
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,124 @@
+package eigenda
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func putWithContext(ctx context.Context, s *Store, payload []byte) <-chan putResult {
+	done := make(chan putResult, 1)
+	go func() {
+		key, err := s.Put(ctx, payload)
+		done <- putResult{key, err}
+	}()
+	return done
+}
+
+func TestPutCanceledWhenClientGoesAway(t *testing.T) {
+	s, _, backend := newSimulatedTestStore(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := putWithContext(ctx, s, []byte("abandoned"))
+	waitForBatchConfirmation(t, backend)
+
+	// the client hangs up while the put waits for the confirmation depth
+	cancel()
+	var res putResult
+	require.Eventually(t, func() bool {
+		select {
+		case res = <-done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, res.err, context.Canceled)
+}
+
+func TestDetachedPutIsReturnedToRetriedPut(t *testing.T) {
+	s, _, backend := newSimulatedTestStore(t, nil)
+	s.cfg.Disconnect = DisconnectConfig{Policy: DisconnectDetach, ResultTTL: time.Hour}
+	s.detached = newDetachedPuts(s.cfg.Disconnect.ResultTTL)
+
+	payload := []byte("retried after a disconnect")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := putWithContext(ctx, s, payload)
+	confirmationBlock := waitForBatchConfirmation(t, backend)
+
+	cancel()
+	res := <-done
+	require.ErrorIs(t, res.err, context.Canceled)
+
+	// the dispersal keeps waiting for the confirmation depth in the background
+	for i := 0; i < testConfirmationDepth; i++ {
+		backend.Commit()
+	}
+	hash := detachedPutKey(payload, verify.SecurityParams{})
+	require.Eventually(t, func() bool {
+		s.detached.mu.Lock()
+		defer s.detached.mu.Unlock()
+		return !s.detached.puts[hash].finished.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a new dispersal would wait for the confirmation depth again, which isn't reached without
+	// new blocks
+	retryCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	key, err := s.Put(retryCtx, payload)
+	require.NoError(t, err)
+
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(key, &cert))
+	require.Equal(t, uint32(confirmationBlock), cert.Proof().GetBatchMetadata().GetConfirmationBlockNumber())
+	require.NoError(t, s.Verify(key, payload))
+}
+
+func TestDetachedPutsForgetFailures(t *testing.T) {
+	d := newDetachedPuts(time.Minute)
+	hash := crypto.Keccak256Hash([]byte("payload"))
+
+	p, started := d.start(hash)
+	require.True(t, started)
+	joined, started := d.start(hash)
+	require.False(t, started)
+	require.Equal(t, p, joined)
+
+	d.finish(hash, p, nil, context.DeadlineExceeded)
+	<-joined.done
+	require.ErrorIs(t, joined.err, context.DeadlineExceeded)
+
+	// failed dispersals are retried by the next put
+	p, started = d.start(hash)
+	require.True(t, started)
+	d.finish(hash, p, []byte("cert"), nil)
+
+	joined, started = d.start(hash)
+	require.False(t, started)
+	require.Equal(t, []byte("cert"), joined.cert)
+
+	// and certs expire after the TTL
+	d.ttl = 0
+	time.Sleep(time.Millisecond)
+	_, started = d.start(hash)
+	require.True(t, started)
+}
+
+func TestDetachedPutKey(t *testing.T) {
+	payload := []byte("payload")
+	key := detachedPutKey(payload, verify.SecurityParams{})
+	require.Equal(t, key, detachedPutKey(payload, verify.SecurityParams{}))
+
+	// the fields are hashed apart, so bytes moved from one field to another change the key
+	require.NotEqual(t, detachedPutKey(append(payload, 1), verify.SecurityParams{}),
+		detachedPutKey(payload, verify.SecurityParams{QuorumIDs: []uint8{1}}))
+	require.NotEqual(t, detachedPutKey(payload, verify.SecurityParams{QuorumIDs: []uint8{1}}),
+		detachedPutKey(payload, verify.SecurityParams{QuorumIDs: []uint8{1, 0}}))
+	require.NotEqual(t, key, detachedPutKey(payload, verify.SecurityParams{AdversaryThreshold: 1}))
+	require.NotEqual(t, key, detachedPutKey(payload, verify.SecurityParams{ConfirmationThreshold: 1}))
+}
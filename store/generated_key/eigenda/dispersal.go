@@ -0,0 +1,281 @@
+package eigenda
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	grpcdisperser "github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/Layr-Labs/eigenda/disperser"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// errDispersalFailed is returned when the disperser reports that a blob won't be confirmed, as
+// opposed to errors where the blob may still be confirmed later (e.g, polling timeouts)
+var errDispersalFailed = errors.New("EigenDA blob dispersal failed")
+
+// dispersal ... a blob that the disperser reported as confirmed
+type dispersal struct {
+	blobInfo *grpcdisperser.BlobInfo
+	// used to poll the blob status again, e.g. when its confirmation block is reorged out
+	endpoint  *endpoints.Endpoint
+	requestID []byte
+	// nil when the journal is disabled
+	entry *journal.Entry
+}
+
+// disperseBlob sends the encoded blob to the disperser and polls its status until the batch it's
+// part of is confirmed. This mirrors EigenDAClient.PutBlob, but records the request ID in the
+// journal entry (if any) so that polling can be resumed after a restart.
+func (e Store) disperseBlob(ctx context.Context, encodedBlob []byte, entry *journal.Entry) (*dispersal, error) {
+	endpoint, blobStatus, requestID, err := e.submitBlob(ctx, encodedBlob)
+	if err != nil {
+		e.journalRemove(entry)
+		return nil, fmt.Errorf("error initializing DisperseBlobAuthenticated() client: %w", err)
+	}
+	if *blobStatus == disperser.Failed {
+		e.journalRemove(entry)
+		return nil, fmt.Errorf("%w: reply status is %d", errDispersalFailed, *blobStatus)
+	}
+
+	if entry != nil {
+		entry.Endpoint = endpoint.RPC
+		entry.RequestID = requestID
+		entry.State = journal.StateDispersed
+		e.journalPut(entry)
+	}
+
+	blobInfo, err := e.pollBlobStatus(ctx, endpoint, requestID)
+	if err != nil {
+		if errors.Is(err, errDispersalFailed) {
+			e.journalRemove(entry)
+		}
+		return nil, err
+	}
+	return &dispersal{blobInfo: blobInfo, endpoint: endpoint, requestID: requestID, entry: entry}, nil
+}
+
+// submitBlob sends the encoded blob to the first disperser endpoint that's available, healthy
+// endpoints first. Other errors aren't failed over since every disperser would reject the blob.
+// The blob is dispersed to the custom quorums and to any quorum requested for the put.
+func (e Store) submitBlob(ctx context.Context, encodedBlob []byte) (*endpoints.Endpoint, *disperser.BlobStatus, []byte, error) {
+	customQuorumNumbers := make([]uint8, len(e.client.Config.CustomQuorumIDs))
+	for i, q := range e.client.Config.CustomQuorumIDs {
+		customQuorumNumbers[i] = uint8(q)
+	}
+	for _, q := range verify.SecurityParamsFromContext(ctx).QuorumIDs {
+		if !slices.Contains(customQuorumNumbers, q) {
+			customQuorumNumbers = append(customQuorumNumbers, q)
+		}
+	}
+
+	var err error
+	for _, endpoint := range e.endpoints.Ordered() {
+		var blobStatus *disperser.BlobStatus
+		var requestID []byte
+		blobStatus, requestID, err = endpoint.Client.Client.DisperseBlobAuthenticated(ctx, encodedBlob, customQuorumNumbers)
+		e.endpoints.Report(endpoint, endpoints.MethodDisperse, err)
+		if err == nil {
+			return endpoint, blobStatus, requestID, nil
+		}
+		if !endpoints.IsUnavailable(err) || ctx.Err() != nil {
+			return nil, nil, nil, err
+		}
+		e.log.Warn("Disperser endpoint unavailable, trying the next one", "endpoint", endpoint.RPC, "err", err)
+	}
+	return nil, nil, nil, err
+}
+
+// pollBlobStatus waits for the blob with the given request ID to be confirmed (or finalized when
+// the client is configured to wait for finalization) and returns its blob info. Request IDs are
+// specific to the disperser the blob was sent to, so there's no failover while polling.
+func (e Store) pollBlobStatus(ctx context.Context, endpoint *endpoints.Endpoint, requestID []byte) (*grpcdisperser.BlobInfo, error) {
+	base64RequestID := base64.StdEncoding.EncodeToString(requestID)
+	e.log.Info("Blob dispersed to EigenDA, now waiting for confirmation", "requestID", base64RequestID, "endpoint", endpoint.RPC)
+
+	ticker := time.NewTicker(e.client.Config.StatusQueryRetryInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, e.client.Config.StatusQueryTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w with request id=%s: %w", errStatusQueryTimeout, base64RequestID, ctx.Err())
+		case <-ticker.C:
+			statusRes, err := endpoint.Client.Client.GetBlobStatus(ctx, requestID)
+			e.endpoints.Report(endpoint, endpoints.MethodBlobStatus, err)
+			if err != nil {
+				e.log.Error("Unable to retrieve blob dispersal status, will retry", "requestID", base64RequestID, "err", err)
+				continue
+			}
+
+			switch statusRes.Status {
+			case grpcdisperser.BlobStatus_PROCESSING, grpcdisperser.BlobStatus_DISPERSING:
+				e.log.Debug("Blob submitted, waiting for dispersal from EigenDA", "requestID", base64RequestID)
+			case grpcdisperser.BlobStatus_FAILED:
+				return nil, fmt.Errorf("%w in processing, requestID=%s", errDispersalFailed, base64RequestID)
+			case grpcdisperser.BlobStatus_INSUFFICIENT_SIGNATURES:
+				return nil, fmt.Errorf("%w in processing, requestID=%s", errInsufficientSignatures, base64RequestID)
+			case grpcdisperser.BlobStatus_CONFIRMED:
+				if !e.client.Config.WaitForFinalization {
+					e.log.Info("EigenDA blob confirmed", "requestID", base64RequestID)
+					return statusRes.Info, nil
+				}
+				e.log.Debug("EigenDA blob confirmed, waiting for finalization", "requestID", base64RequestID)
+			case grpcdisperser.BlobStatus_FINALIZED:
+				e.log.Info("Successfully dispersed blob to EigenDA", "requestID", base64RequestID,
+					"batchHeaderHash", fmt.Sprintf("%#x", statusRes.Info.BlobVerificationProof.BatchMetadata.BatchHeaderHash))
+				return statusRes.Info, nil
+			default:
+				return nil, fmt.Errorf("%w in processing with reply status %d", errDispersalFailed, statusRes.Status)
+			}
+		}
+	}
+}
+
+// Resume finishes the dispersals that the journal recorded as in-flight when the proxy last
+// stopped. Dispersals with a known request ID are polled until their cert is available, while
+// those that never got a request ID are reported as unknown since the disperser may or may not
+// have accepted them. Outcomes are logged and kept in the journal for the retention period.
+func (e Store) Resume(ctx context.Context) error {
+	if e.journal == nil {
+		return nil
+	}
+
+	if err := e.journal.Prune(e.cfg.JournalRetention); err != nil {
+		return fmt.Errorf("failed to prune dispersal journal: %w", err)
+	}
+
+	entries, err := e.journal.Entries()
+	if err != nil {
+		return fmt.Errorf("failed to read dispersal journal: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		if !entry.State.Outstanding() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(entry *journal.Entry) {
+			defer wg.Done()
+			e.resumeEntry(ctx, entry)
+		}(entry)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (e Store) resumeEntry(ctx context.Context, entry *journal.Entry) {
+	l := e.log.With("journalID", entry.ID, "payloadHash", entry.PayloadHash,
+		"requestID", base64.StdEncoding.EncodeToString(entry.RequestID))
+
+	switch entry.State {
+	case journal.StateSubmitting:
+		entry.State = journal.StateUnknown
+		entry.Error = "proxy stopped before the disperser acknowledged the blob, it may or may not have been dispersed"
+		l.Warn("Interrupted dispersal has an unknown outcome")
+
+	case journal.StateDispersed:
+		endpoint := e.endpoints.Get(entry.Endpoint)
+		if endpoint == nil {
+			// entries written before endpoints were journaled, or whose endpoint was since removed
+			l.Warn("Disperser endpoint of interrupted dispersal is unknown, polling the primary one", "endpoint", entry.Endpoint)
+			endpoint = e.endpoints.Primary()
+		}
+
+		l.Info("Resuming interrupted dispersal")
+		blobInfo, err := e.pollBlobStatus(ctx, endpoint, entry.RequestID)
+		if err == nil {
+			entry.Cert, err = rlp.EncodeToBytes(blobInfo)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// keep the entry outstanding so that the next run resumes it again
+				l.Warn("Stopped resuming interrupted dispersal", "err", err)
+				return
+			}
+			entry.State = journal.StateFailed
+			entry.Error = err.Error()
+			l.Error("Interrupted dispersal failed", "err", err)
+			break
+		}
+		entry.State = journal.StateRecovered
+		l.Info("Recovered cert of interrupted dispersal", "cert", entry.Cert.String())
+
+	case journal.StateConfirmed:
+		entry.State = journal.StateRecovered
+		l.Info("Recovered cert of interrupted dispersal", "cert", entry.Cert.String())
+
+	default:
+		return
+	}
+
+	e.journalPut(entry)
+}
+
+// journalSubmit records the write-ahead entry of a dispersal that's about to be submitted. A nil
+// entry is returned when the journal is disabled, every journal helper treats it as a no-op.
+func (e Store) journalSubmit(payload []byte) *journal.Entry {
+	if e.journal == nil {
+		return nil
+	}
+
+	entry, err := journal.NewEntry(payload)
+	if err != nil {
+		e.log.Error("Failed to create dispersal journal entry", "err", err)
+		return nil
+	}
+	e.journalPut(entry)
+	return entry
+}
+
+// journalPut writes the entry, failing to do so only loses the ability to resume the dispersal
+// after a restart so it doesn't fail the dispersal itself.
+func (e Store) journalPut(entry *journal.Entry) {
+	if e.journal == nil || entry == nil {
+		return
+	}
+
+	if err := e.journal.Put(entry); err != nil {
+		e.log.Error("Failed to write dispersal journal entry", "journalID", entry.ID, "state", entry.State, "err", err)
+	}
+}
+
+// journalConfirmed records the cert of a confirmed dispersal so that it can be recovered if the
+// proxy stops while waiting for the confirmation depth
+func (e Store) journalConfirmed(entry *journal.Entry, cert *verify.Certificate) {
+	if entry == nil {
+		return
+	}
+
+	b, err := rlp.EncodeToBytes(cert)
+	if err != nil {
+		e.log.Error("Failed to encode cert for the dispersal journal", "journalID", entry.ID, "err", err)
+		return
+	}
+	entry.State = journal.StateConfirmed
+	entry.Cert = b
+	e.journalPut(entry)
+}
+
+func (e Store) journalRemove(entry *journal.Entry) {
+	if e.journal == nil || entry == nil {
+		return
+	}
+
+	if err := e.journal.Remove(entry.ID); err != nil {
+		e.log.Error("Failed to remove dispersal journal entry", "journalID", entry.ID, "err", err)
+	}
+}
@@ -0,0 +1,260 @@
+package eigenda
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/mockdisperser"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/signer"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSignerHex  = "0000000000000000000100000000000000000000000000000000000000000000"
+	testMaxBlobLen = 3000 * 32
+)
+
+func getDefaultVerifierTestConfig() *verify.Config {
+	return &verify.Config{
+		VerifyCerts: false,
+		KzgConfig: &kzg.KzgConfig{
+			G1Path:          "../../../resources/g1.point",
+			G2PowerOf2Path:  "../../../resources/g2.point.powerOf2",
+			CacheDir:        "../../../resources/SRSTables",
+			SRSOrder:        3000,
+			SRSNumberToLoad: 3000,
+			NumWorker:       uint64(runtime.GOMAXPROCS(0)),
+		},
+	}
+}
+
+func newTestStore(t *testing.T, j *journal.Journal) *Store {
+	s, _ := newTestStoreWithRetries(t, j, RetryConfig{MaxAttempts: 1})
+	return s
+}
+
+func newTestStoreWithRetries(t *testing.T, j *journal.Journal, retry RetryConfig) (*Store, *mockdisperser.Server) {
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	cfg := mockdisperser.DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	server := mockdisperser.New(cfg, verifier, log.New())
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	client, err := clients.NewEigenDAClient(log.New(), clients.EigenDAClientConfig{
+		RPC:                      server.Endpoint(),
+		StatusQueryTimeout:       10 * time.Second,
+		StatusQueryRetryInterval: 50 * time.Millisecond,
+		ResponseTimeout:          5 * time.Second,
+		DisableTLS:               true,
+		SignerPrivateKeyHex:      testSignerHex,
+	})
+	require.NoError(t, err)
+
+	s, err := NewStoreWithJournal(client, verifier, j, log.New(), metrics.NoopMetrics, &StoreConfig{
+		MaxBlobSizeBytes:   testMaxBlobLen,
+		StatusQueryTimeout: 10 * time.Second,
+		JournalRetention:   time.Hour,
+		Retry:              retry,
+	})
+	require.NoError(t, err)
+	return s, server
+}
+
+func TestResumeInterruptedDispersals(t *testing.T) {
+	j, err := journal.New(t.TempDir())
+	require.NoError(t, err)
+	s := newTestStore(t, j)
+	ctx := context.Background()
+
+	// simulate a proxy that stopped while polling the status of a dispersed blob
+	payload := []byte("interrupted while polling")
+	encoded, err := s.client.GetCodec().EncodeBlob(payload)
+	require.NoError(t, err)
+	_, requestID, err := s.client.Client.DisperseBlobAuthenticated(ctx, encoded, nil)
+	require.NoError(t, err)
+
+	dispersed, err := journal.NewEntry(payload)
+	require.NoError(t, err)
+	dispersed.State = journal.StateDispersed
+	dispersed.RequestID = requestID
+	require.NoError(t, j.Put(dispersed))
+
+	// and one that stopped before the disperser acknowledged the blob
+	submitting, err := journal.NewEntry([]byte("interrupted while submitting"))
+	require.NoError(t, err)
+	require.NoError(t, j.Put(submitting))
+
+	require.NoError(t, s.Resume(ctx))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		switch entry.ID {
+		case dispersed.ID:
+			require.Equal(t, journal.StateRecovered, entry.State)
+
+			var cert verify.Certificate
+			require.NoError(t, rlp.DecodeBytes(entry.Cert, &cert))
+			require.NoError(t, s.verifier.VerifyCommitment(cert.BlobHeader.Commitment, encoded))
+
+			actual, err := s.Get(ctx, entry.Cert)
+			require.NoError(t, err)
+			require.Equal(t, payload, actual)
+		case submitting.ID:
+			require.Equal(t, journal.StateUnknown, entry.State)
+			require.NotEmpty(t, entry.Error)
+		default:
+			t.Fatalf("unexpected journal entry %s", entry.ID)
+		}
+	}
+}
+
+func TestDisperseBlobRecordsRequestID(t *testing.T) {
+	j, err := journal.New(t.TempDir())
+	require.NoError(t, err)
+	s := newTestStore(t, j)
+
+	payload := []byte("dispersed with a journal")
+	encoded, err := s.client.GetCodec().EncodeBlob(payload)
+	require.NoError(t, err)
+
+	entry := s.journalSubmit(payload)
+	require.NotNil(t, entry)
+
+	d, err := s.disperseBlob(context.Background(), encoded, entry)
+	require.NoError(t, err)
+	require.NotNil(t, d.blobInfo)
+	require.Equal(t, entry.RequestID, hexutil.Bytes(d.requestID))
+
+	// the entry stays in the journal until Put is done with the cert
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, journal.StateDispersed, entries[0].State)
+	require.NotEmpty(t, entries[0].RequestID)
+}
+
+func TestFailoverToBackupDisperser(t *testing.T) {
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	cfg := mockdisperser.DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	servers := make([]*mockdisperser.Server, 2)
+	cs := make([]*clients.EigenDAClient, 2)
+	for i := range servers {
+		servers[i] = mockdisperser.New(cfg, verifier, log.New())
+		require.NoError(t, servers[i].Start("127.0.0.1:0"))
+		t.Cleanup(servers[i].Stop)
+
+		cs[i], err = clients.NewEigenDAClient(log.New(), clients.EigenDAClientConfig{
+			RPC:                      servers[i].Endpoint(),
+			StatusQueryTimeout:       10 * time.Second,
+			StatusQueryRetryInterval: 50 * time.Millisecond,
+			ResponseTimeout:          5 * time.Second,
+			DisableTLS:               true,
+			SignerPrivateKeyHex:      testSignerHex,
+		})
+		require.NoError(t, err)
+	}
+	pool := endpoints.NewWithClients(cs, endpoints.Config{FailureThreshold: 1}, log.New(), metrics.NoopMetrics)
+
+	j, err := journal.New(t.TempDir())
+	require.NoError(t, err)
+	s, err := NewStoreWithEndpoints(pool, verifier, j, log.New(), metrics.NoopMetrics, &StoreConfig{
+		MaxBlobSizeBytes:   testMaxBlobLen,
+		StatusQueryTimeout: 10 * time.Second,
+		JournalRetention:   time.Hour,
+		Retry:              RetryConfig{MaxAttempts: 1},
+	})
+	require.NoError(t, err)
+
+	primary := servers[0].Endpoint()
+	servers[0].Stop()
+
+	ctx := context.Background()
+	payload := []byte("dispersed by the backup")
+	encoded, err := s.client.GetCodec().EncodeBlob(payload)
+	require.NoError(t, err)
+
+	// the journal records which disperser the blob was sent to, for resuming it after a restart
+	entry := s.journalSubmit(payload)
+	d, err := s.disperseBlob(ctx, encoded, entry)
+	require.NoError(t, err)
+	require.Equal(t, servers[1].Endpoint(), d.endpoint.RPC)
+	require.Equal(t, servers[1].Endpoint(), entry.Endpoint)
+	require.False(t, pool.Get(primary).Healthy())
+
+	key, err := s.Put(ctx, payload)
+	require.NoError(t, err)
+	actual, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, payload, actual)
+}
+
+func TestDispersalWithRotatingSigners(t *testing.T) {
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	cfg := mockdisperser.DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	server := mockdisperser.New(cfg, verifier, log.New())
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	signers := make([]core.BlobRequestSigner, 2)
+	accounts := make([]string, len(signers))
+	for i := range signers {
+		signers[i], err = signer.NewLocal(fmt.Sprintf("%064x", i+1))
+		require.NoError(t, err)
+		accounts[i], err = signers[i].GetAccountID()
+		require.NoError(t, err)
+	}
+	pool, err := endpoints.New(clients.EigenDAClientConfig{
+		RPC:                      server.Endpoint(),
+		StatusQueryTimeout:       10 * time.Second,
+		StatusQueryRetryInterval: 50 * time.Millisecond,
+		ResponseTimeout:          5 * time.Second,
+		DisableTLS:               true,
+	}, endpoints.Config{FailureThreshold: 1}, signers, log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	s, err := NewStoreWithEndpoints(pool, verifier, nil, log.New(), metrics.NoopMetrics, &StoreConfig{
+		MaxBlobSizeBytes:   testMaxBlobLen,
+		StatusQueryTimeout: 10 * time.Second,
+		Retry:              RetryConfig{MaxAttempts: 1},
+	})
+	require.NoError(t, err)
+
+	// the keys authenticate the dispersals in round-robin order
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		payload := []byte(fmt.Sprintf("signed by key %d", i%2))
+		key, err := s.Put(ctx, payload)
+		require.NoError(t, err)
+
+		actual, err := s.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, payload, actual)
+	}
+	require.NotEqual(t, accounts[0], accounts[1])
+	require.Equal(t, []string{accounts[0], accounts[1], accounts[0], accounts[1]}, server.Accounts())
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/endpoints"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
 	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/Layr-Labs/eigenda/api/clients"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type StoreConfig struct {
@@ -21,26 +26,74 @@ type StoreConfig struct {
 
 	// total duration time that client waits for blob to confirm
 	StatusQueryTimeout time.Duration
+
+	// how long recovered or failed dispersals are kept in the journal
+	JournalRetention time.Duration
+
+	// policy for retrying failed dispersals
+	Retry RetryConfig
+
+	// how often the confirmation depth is checked, defaults to the Ethereum block time
+	ConfirmationPollInterval time.Duration
+
+	// whether dispersals are canceled or finished in the background when the client goes away
+	Disconnect DisconnectConfig
 }
 
+// ethBlockTime ... average Ethereum block time
+const ethBlockTime = 12 * time.Second
+
 // Store does storage interactions and verifications for blobs with DA.
 type Store struct {
-	client   *clients.EigenDAClient
-	verifier *verify.Verifier
-	cfg      *StoreConfig
-	log      log.Logger
+	// client of the primary disperser, whose config and codec apply to every endpoint
+	client    *clients.EigenDAClient
+	endpoints *endpoints.Pool
+	verifier  *verify.Verifier
+	cfg       *StoreConfig
+	log       log.Logger
+	metrics   metrics.Metricer
+
+	// optional write-ahead journal of in-flight dispersals
+	journal *journal.Journal
+
+	// dispersals that outlive their put, nil unless the disconnect policy is detach
+	detached *detachedPuts
 }
 
 var _ store.GeneratedKeyStore = (*Store)(nil)
+var _ store.CertReporter = (*Store)(nil)
+var _ store.CertVerifier = (*Store)(nil)
 
 func NewStore(client *clients.EigenDAClient,
 	v *verify.Verifier, log log.Logger, cfg *StoreConfig) (*Store, error) {
-	return &Store{
-		client:   client,
-		verifier: v,
-		log:      log,
-		cfg:      cfg,
-	}, nil
+	return NewStoreWithJournal(client, v, nil, log, metrics.NoopMetrics, cfg)
+}
+
+// NewStoreWithJournal ... constructor for a store that records in-flight dispersals in the journal,
+// call Resume on startup to finish the dispersals a previous run was interrupted in.
+func NewStoreWithJournal(client *clients.EigenDAClient,
+	v *verify.Verifier, j *journal.Journal, log log.Logger, m metrics.Metricer, cfg *StoreConfig) (*Store, error) {
+	p := endpoints.NewWithClients([]*clients.EigenDAClient{client}, endpoints.DefaultConfig(), log, m)
+	return NewStoreWithEndpoints(p, v, j, log, m, cfg)
+}
+
+// NewStoreWithEndpoints ... constructor for a store that fails over across the pool's disperser
+// endpoints, and records in-flight dispersals in the journal (if not nil)
+func NewStoreWithEndpoints(p *endpoints.Pool,
+	v *verify.Verifier, j *journal.Journal, log log.Logger, m metrics.Metricer, cfg *StoreConfig) (*Store, error) {
+	s := &Store{
+		client:    p.Primary().Client,
+		endpoints: p,
+		verifier:  v,
+		log:       log,
+		metrics:   m,
+		cfg:       cfg,
+		journal:   j,
+	}
+	if cfg.Disconnect.Policy == DisconnectDetach {
+		s.detached = newDetachedPuts(cfg.Disconnect.ResultTTL)
+	}
+	return s, nil
 }
 
 // Get fetches a blob from DA using certificate fields and verifies blob
@@ -49,19 +102,47 @@ func (e Store) Get(ctx context.Context, key []byte) ([]byte, error) {
 	var cert verify.Certificate
 	err := rlp.DecodeBytes(key, &cert)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode DA cert to RLP format: %w", err)
+		return nil, fmt.Errorf("%w: failed to decode DA cert to RLP format: %w", verify.ErrInvalidCert, err)
 	}
 
-	decodedBlob, err := e.client.GetBlob(ctx, cert.BlobVerificationProof.BatchMetadata.BatchHeaderHash, cert.BlobVerificationProof.BlobIndex)
-	if err != nil {
-		return nil, fmt.Errorf("EigenDA client failed to retrieve decoded blob: %w", err)
+	// any disperser of the network may serve the blob, so every endpoint is tried in turn
+	var errs []error
+	notFound := true
+	for _, endpoint := range e.endpoints.Ordered() {
+		decodedBlob, err := endpoint.Client.GetBlob(ctx, cert.BlobVerificationProof.BatchMetadata.BatchHeaderHash, cert.BlobVerificationProof.BlobIndex)
+		e.endpoints.Report(endpoint, endpoints.MethodRetrieve, err)
+		if err == nil {
+			return decodedBlob, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("EigenDA client failed to retrieve decoded blob: %w", err)
+		}
+
+		e.log.Warn("Failed to retrieve blob from disperser endpoint", "endpoint", endpoint.RPC, "err", err)
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint.RPC, err))
+		notFound = notFound && status.Code(err) == codes.NotFound
 	}
 
-	return decodedBlob, nil
+	// the blob is only reported missing if every disperser said so, otherwise it may be served once
+	// the failing dispersers are back
+	category := store.ErrBackendUnavailable
+	if notFound {
+		category = store.ErrNotFound
+	}
+	return nil, fmt.Errorf("EigenDA client failed to retrieve decoded blob: %w: %w", category, errors.Join(errs...))
 }
 
 // Put disperses a blob for some pre-image and returns the associated RLP encoded certificate commit.
+// When the context is canceled (e.g, the client went away) the dispersal is canceled, unless the
+// disconnect policy is detach in which case it's finished in the background.
 func (e Store) Put(ctx context.Context, value []byte) ([]byte, error) {
+	if e.detached != nil {
+		return e.putDetached(ctx, value)
+	}
+	return e.put(ctx, value)
+}
+
+func (e Store) put(ctx context.Context, value []byte) ([]byte, error) {
 	encodedBlob, err := e.client.GetCodec().EncodeBlob(value)
 	if err != nil {
 		return nil, fmt.Errorf("EigenDA client failed to re-encode blob: %w", err)
@@ -71,52 +152,88 @@ func (e Store) Put(ctx context.Context, value []byte) ([]byte, error) {
 	}
 
 	dispersalStart := time.Now()
-	blobInfo, err := e.client.PutBlob(ctx, value)
-	if err != nil {
-		return nil, err
+	store.ReportPutStatus(ctx, store.PutStatusDispersing)
+	d, err := e.disperseWithRetries(ctx, value, encodedBlob)
+	if endpoints.IsUnavailable(err) {
+		return nil, fmt.Errorf("%w: %w", store.ErrBackendUnavailable, err)
 	}
-	cert := (*verify.Certificate)(blobInfo)
-
-	err = e.verifier.VerifyCommitment(cert.BlobHeader.Commitment, encodedBlob)
 	if err != nil {
 		return nil, err
 	}
+	store.ReportPutStatus(ctx, store.PutStatusConfirmed)
+	// the dispersal is over once the cert is known, whatever the outcome of its verification
+	defer e.journalRemove(d.entry)
 
 	dispersalDuration := time.Since(dispersalStart)
 	remainingTimeout := e.cfg.StatusQueryTimeout - dispersalDuration
 
-	ticker := time.NewTicker(12 * time.Second) // avg. eth block time
-	defer ticker.Stop()
-	ctx, cancel := context.WithTimeout(context.Background(), remainingTimeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, remainingTimeout)
 	defer cancel()
 
-	done := false
-	for !done {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("timed out when trying to verify the DA certificate for a blob batch after dispersal")
-		case <-ticker.C:
-			err = e.verifier.VerifyCert(cert)
-			switch {
-			case err == nil:
-				done = true
-			case errors.Is(err, verify.ErrBatchMetadataHashNotFound):
-				e.log.Info("Blob confirmed, waiting for sufficient confirmation depth...", "targetDepth", e.cfg.EthConfirmationDepth)
-				done = true
-			default:
-				return nil, err
-			}
-		}
+	cert, err := e.waitForConfirmation(timeoutCtx, d, encodedBlob)
+	if err != nil {
+		return nil, err
 	}
+	store.ReportPutStatus(ctx, store.PutStatusVerified)
 
 	bytes, err := rlp.EncodeToBytes(cert)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode DA cert to RLP format: %w", err)
 	}
-
 	return bytes, nil
 }
 
+// waitForConfirmation waits for the block the blob's batch was confirmed in to reach the confirmation
+// depth and verifies the cert, along with the security params requested for the put (if any). If
+// the confirmation block is reorged out (or the batch can't be found once it's deep enough), the
+// blob status is polled again, one confirmation poll interval later, until the disperser reports
+// the batch's new confirmation, and the new cert is verified instead.
+func (e Store) waitForConfirmation(ctx context.Context, d *dispersal, encodedBlob []byte) (*verify.Certificate, error) {
+	blobInfo := d.blobInfo
+	for {
+		cert := (*verify.Certificate)(blobInfo)
+		if err := e.verifier.VerifyCommitment(cert.BlobHeader.Commitment, encodedBlob); err != nil {
+			return nil, err
+		}
+		e.journalConfirmed(d.entry, cert)
+
+		confirmationBlock := cert.Proof().GetBatchMetadata().GetConfirmationBlockNumber()
+		e.log.Info("Blob confirmed, waiting for sufficient confirmation depth...",
+			"confirmationBlock", confirmationBlock, "targetDepth", e.cfg.EthConfirmationDepth)
+
+		err := e.verifier.WaitForConfirmation(ctx, cert, verify.SecurityParamsFromContext(ctx), e.confirmationPollInterval())
+		switch {
+		case err == nil:
+			return cert, nil
+		case errors.Is(err, verify.ErrConfirmationBlockReorged), errors.Is(err, verify.ErrBatchMetadataHashNotFound):
+			e.log.Warn("Blob confirmation was reorged out, waiting for the batch to be confirmed again",
+				"confirmationBlock", confirmationBlock, "err", err)
+		default:
+			return nil, err
+		}
+
+		// the disperser may keep reporting the reorged confirmation until it notices the reorg, so
+		// back off before polling it again rather than re-verifying the same cert in a tight loop
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("context done while waiting to poll the reorged blob's status: %w", ctx.Err())
+		case <-time.After(e.confirmationPollInterval()):
+		}
+
+		blobInfo, err = e.pollBlobStatus(ctx, d.endpoint, d.requestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blob status after its confirmation was reorged out: %w", err)
+		}
+	}
+}
+
+func (e Store) confirmationPollInterval() time.Duration {
+	if e.cfg.ConfirmationPollInterval > 0 {
+		return e.cfg.ConfirmationPollInterval
+	}
+	return ethBlockTime
+}
+
 // Entries are a no-op for EigenDA Store
 func (e Store) Stats() *store.Stats {
 	return nil
@@ -133,7 +250,7 @@ func (e Store) Verify(key []byte, value []byte) error {
 	var cert verify.Certificate
 	err := rlp.DecodeBytes(key, &cert)
 	if err != nil {
-		return fmt.Errorf("failed to decode DA cert to RLP format: %w", err)
+		return fmt.Errorf("%w: failed to decode DA cert to RLP format: %w", verify.ErrInvalidCert, err)
 	}
 
 	// re-encode blob for verification
@@ -151,3 +268,26 @@ func (e Store) Verify(key []byte, value []byte) error {
 	// verify DA certificate against EigenDA's batch metadata that's bridged to Ethereum
 	return e.verifier.VerifyCert(&cert)
 }
+
+// VerifyCert ... verifies the DA certificate of the key, but not the blob it commits to
+func (e Store) VerifyCert(key []byte) error {
+	var cert verify.Certificate
+	if err := rlp.DecodeBytes(key, &cert); err != nil {
+		return fmt.Errorf("%w: failed to decode DA cert to RLP format: %w", verify.ErrInvalidCert, err)
+	}
+	return e.verifier.VerifyCert(&cert)
+}
+
+// ReportCert ... runs every verification step of the cert, and of the payload of its blob unless it's
+// nil, which is encoded with the codec of the EigenDA client first
+func (e Store) ReportCert(ctx context.Context, cert *verify.Certificate, value []byte) (*verify.Report, error) {
+	var encodedBlob []byte
+	if value != nil {
+		var err error
+		encodedBlob, err = e.client.GetCodec().EncodeBlob(value)
+		if err != nil {
+			return nil, fmt.Errorf("EigenDA client failed to re-encode blob: %w", err)
+		}
+	}
+	return e.verifier.ReportCert(ctx, cert, encodedBlob), nil
+}
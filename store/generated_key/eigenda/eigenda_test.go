@@ -0,0 +1,204 @@
+package eigenda
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/journal"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/mockdisperser"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda-proxy/verify/simulated"
+	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfirmationDepth = 3
+
+// newSimulatedTestStore ... store whose certs are verified against a simulated chain, on which the
+// mock disperser confirms its batches
+func newSimulatedTestStore(t *testing.T, j *journal.Journal) (*Store, *mockdisperser.Server, *simulated.Backend) {
+	backend, err := simulated.NewBackend(log.New())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, backend.Close())
+	})
+
+	vCfg := getDefaultVerifierTestConfig()
+	vCfg.VerifyCerts = true
+	vCfg.SvcManagerAddr = simulated.ServiceManagerAddr.Hex()
+	vCfg.EthConfirmationDepth = testConfirmationDepth
+	verifier, err := verify.NewVerifierWithClient(vCfg, backend.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	cfg := mockdisperser.DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	// batches are confirmed in the block that bridges their metadata hash
+	cfg.BlockNumber = func() uint64 {
+		latest, err := backend.BlockNumber(context.Background())
+		require.NoError(t, err)
+		return latest + 1
+	}
+	cfg.OnBatchConfirmed = func(batchID uint32, batchMetadataHash [32]byte) error {
+		return backend.SetBatchMetadataHash(context.Background(), batchID, batchMetadataHash)
+	}
+	server := mockdisperser.New(cfg, verifier, log.New())
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	client, err := clients.NewEigenDAClient(log.New(), clients.EigenDAClientConfig{
+		RPC:                      server.Endpoint(),
+		StatusQueryTimeout:       10 * time.Second,
+		StatusQueryRetryInterval: 20 * time.Millisecond,
+		ResponseTimeout:          5 * time.Second,
+		DisableTLS:               true,
+		SignerPrivateKeyHex:      testSignerHex,
+	})
+	require.NoError(t, err)
+
+	s, err := NewStoreWithJournal(client, verifier, j, log.New(), metrics.NoopMetrics, &StoreConfig{
+		MaxBlobSizeBytes:         testMaxBlobLen,
+		EthConfirmationDepth:     testConfirmationDepth,
+		StatusQueryTimeout:       10 * time.Second,
+		JournalRetention:         time.Hour,
+		Retry:                    RetryConfig{MaxAttempts: 1},
+		ConfirmationPollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return s, server, backend
+}
+
+type putResult struct {
+	key []byte
+	err error
+}
+
+func putAsync(s *Store, payload []byte) <-chan putResult {
+	done := make(chan putResult, 1)
+	go func() {
+		key, err := s.Put(context.Background(), payload)
+		done <- putResult{key, err}
+	}()
+	return done
+}
+
+// waitForBatchConfirmation returns the block the mock disperser confirmed its next batch in
+func waitForBatchConfirmation(t *testing.T, backend *simulated.Backend) uint64 {
+	start, err := backend.BlockNumber(context.Background())
+	require.NoError(t, err)
+
+	var confirmationBlock uint64
+	require.Eventually(t, func() bool {
+		confirmationBlock, err = backend.BlockNumber(context.Background())
+		require.NoError(t, err)
+		return confirmationBlock > start
+	}, 5*time.Second, 10*time.Millisecond)
+	return confirmationBlock
+}
+
+func TestPutWaitsForConfirmationDepth(t *testing.T) {
+	s, _, backend := newSimulatedTestStore(t, nil)
+
+	payload := []byte("confirmed at depth")
+	done := putAsync(s, payload)
+	confirmationBlock := waitForBatchConfirmation(t, backend)
+
+	for i := 0; i < testConfirmationDepth; i++ {
+		require.Never(t, func() bool { return len(done) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+		backend.Commit()
+	}
+
+	res := <-done
+	require.NoError(t, res.err)
+
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(res.key, &cert))
+	require.Equal(t, uint32(confirmationBlock), cert.Proof().GetBatchMetadata().GetConfirmationBlockNumber())
+	require.NoError(t, s.Verify(res.key, payload))
+}
+
+func TestPutReverifiesReorgedConfirmation(t *testing.T) {
+	j, err := journal.New(t.TempDir())
+	require.NoError(t, err)
+	s, server, backend := newSimulatedTestStore(t, j)
+	ctx := context.Background()
+
+	payload := []byte("confirmed twice")
+	done := putAsync(s, payload)
+	confirmationBlock := waitForBatchConfirmation(t, backend)
+
+	// the cert of the first confirmation is journaled while waiting for the confirmation depth
+	var reorgedCert []byte
+	require.Eventually(t, func() bool {
+		entries, err := j.Entries()
+		require.NoError(t, err)
+		if len(entries) != 1 || entries[0].State != journal.StateConfirmed {
+			return false
+		}
+		reorgedCert = entries[0].Cert
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	// let the confirmation block hash be observed before it's reorged out
+	require.Never(t, func() bool { return len(done) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	parent, err := backend.Client().HeaderByNumber(ctx, new(big.Int).SetUint64(confirmationBlock-1))
+	require.NoError(t, err)
+	require.NoError(t, backend.Fork(parent.Hash()))
+	// replace the confirmation block, then confirm the batch again in the next one
+	require.NoError(t, backend.SetQuorumNumbersRequired(simulated.DefaultQuorumNumbersRequired))
+	require.NoError(t, server.ReconfirmBatches())
+
+	for i := 0; i < testConfirmationDepth; i++ {
+		backend.Commit()
+	}
+
+	var res putResult
+	require.Eventually(t, func() bool {
+		select {
+		case res = <-done:
+			return true
+		default:
+			backend.Commit()
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+	require.NoError(t, res.err)
+	require.NotEqual(t, reorgedCert, res.key)
+
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(res.key, &cert))
+	require.Equal(t, uint32(confirmationBlock+1), cert.Proof().GetBatchMetadata().GetConfirmationBlockNumber())
+	require.NoError(t, s.Verify(res.key, payload))
+
+	// the reorged out cert doesn't match the batch metadata on the canonical chain anymore
+	require.Error(t, s.Verify(reorgedCert, payload))
+}
+
+func TestPutEnforcesRequestedSecurityParams(t *testing.T) {
+	s := newTestStore(t, nil)
+
+	// the mock disperser confirms blobs with a 33% adversary threshold and 100% signed stake
+	ctx := verify.WithSecurityParams(context.Background(), verify.SecurityParams{
+		QuorumIDs:             []uint8{2},
+		ConfirmationThreshold: 100,
+	})
+	payload := []byte("dispersed to an extra quorum")
+	key, err := s.Put(ctx, payload)
+	require.NoError(t, err)
+
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(key, &cert))
+	quorums := make([]uint8, len(cert.ReadBlobHeader().QuorumBlobParams))
+	for i, qp := range cert.ReadBlobHeader().QuorumBlobParams {
+		quorums[i] = qp.QuorumNumber
+	}
+	require.Contains(t, quorums, uint8(2))
+
+	ctx = verify.WithSecurityParams(context.Background(), verify.SecurityParams{AdversaryThreshold: 40})
+	_, err = s.Put(ctx, payload)
+	require.ErrorContains(t, err, "adversary threshold")
+}
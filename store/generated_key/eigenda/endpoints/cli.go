@@ -0,0 +1,93 @@
+package endpoints
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	BackupRPCsFlagName          = withFlagPrefix("backup-rpcs")
+	HealthCheckIntervalFlagName = withFlagPrefix("health-check-interval")
+	FailureThresholdFlagName    = withFlagPrefix("failure-threshold")
+)
+
+func withFlagPrefix(s string) string {
+	return "eigenda.endpoints." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_EIGENDA_ENDPOINTS_" + s}
+}
+
+// Config ... failover configuration of the disperser endpoints, the primary endpoint is the
+// eigenda.disperser-rpc of the client config
+type Config struct {
+	// disperser RPCs that are failed over to, in priority order, when the primary one is unavailable
+	BackupRPCs []string
+	// how often every endpoint is checked, so that unhealthy endpoints are used again once they recover
+	HealthCheckInterval time.Duration
+	// consecutive unavailability errors after which an endpoint is considered unhealthy
+	FailureThreshold uint
+}
+
+// DefaultConfig ... failover configuration of the CLI flag defaults, without backup RPCs
+func DefaultConfig() Config {
+	return Config{
+		HealthCheckInterval: 10 * time.Second,
+		FailureThreshold:    3,
+	}
+}
+
+// Check ... verifies that the backup RPCs are host:port pairs and that unhealthy endpoints can recover
+func (c Config) Check() error {
+	for _, rpc := range c.BackupRPCs {
+		if _, _, err := net.SplitHostPort(rpc); err != nil {
+			return fmt.Errorf("invalid backup disperser RPC %q: %w", rpc, err)
+		}
+	}
+	if c.FailureThreshold == 0 {
+		return fmt.Errorf("disperser endpoint failure threshold must be at least 1")
+	}
+	if len(c.BackupRPCs) > 0 && c.HealthCheckInterval <= 0 {
+		return fmt.Errorf("disperser endpoint health check interval must be positive when backup RPCs are set")
+	}
+	return nil
+}
+
+// CLIFlags ... used for disperser endpoint failover configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     BackupRPCsFlagName,
+			Usage:    "Backup disperser RPCs, in priority order, that are failed over to when the disperser RPC is unavailable. Backups share the rest of the EigenDA client configuration.",
+			EnvVars:  withEnvPrefix(envPrefix, "BACKUP_RPCS"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     HealthCheckIntervalFlagName,
+			Usage:    "Interval between health checks of the disperser endpoints. Only used when backup RPCs are set.",
+			Value:    DefaultConfig().HealthCheckInterval,
+			EnvVars:  withEnvPrefix(envPrefix, "HEALTH_CHECK_INTERVAL"),
+			Category: category,
+		},
+		&cli.UintFlag{
+			Name:     FailureThresholdFlagName,
+			Usage:    "Number of consecutive unavailability errors after which a disperser endpoint is considered unhealthy and only used as a last resort.",
+			Value:    DefaultConfig().FailureThreshold,
+			EnvVars:  withEnvPrefix(envPrefix, "FAILURE_THRESHOLD"),
+			Category: category,
+		},
+	}
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	return Config{
+		BackupRPCs:          ctx.StringSlice(BackupRPCsFlagName),
+		HealthCheckInterval: ctx.Duration(HealthCheckIntervalFlagName),
+		FailureThreshold:    ctx.Uint(FailureThresholdFlagName),
+	}
+}
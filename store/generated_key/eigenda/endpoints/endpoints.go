@@ -0,0 +1,234 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/signer"
+	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Methods ... disperser requests recorded in the per endpoint metrics
+const (
+	MethodDisperse    = "disperse"
+	MethodBlobStatus  = "blob_status"
+	MethodRetrieve    = "retrieve"
+	MethodHealthCheck = "health_check"
+)
+
+// healthCheckRequestID ... request ID polled by health checks, the disperser answers with a
+// NotFound error when it's up
+var healthCheckRequestID = []byte("eigenda-proxy-health-check")
+
+// IsUnavailable returns true for errors that indicate the disperser can't serve requests at the
+// moment (e.g, down for maintenance or overloaded), as opposed to errors about the request itself.
+func IsUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoint ... a disperser and the client connected to it
+type Endpoint struct {
+	RPC    string
+	Client *clients.EigenDAClient
+
+	mu       sync.Mutex
+	healthy  bool
+	failures uint
+}
+
+// Healthy ... returns false once the endpoint failed FailureThreshold requests in a row, until a
+// request or health check succeeds again
+func (e *Endpoint) Healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy
+}
+
+// Pool holds the disperser endpoints in priority order (the primary first, then the backups) and
+// tracks their health so that requests can fail over to the next healthy endpoint.
+type Pool struct {
+	endpoints []*Endpoint
+	cfg       Config
+	log       log.Logger
+	metrics   metrics.Metricer
+}
+
+// New ... creates a client for the disperser of the client config and one for every backup RPC,
+// which share the rest of the client config. Dispersals are authenticated by the signers when
+// any are given, instead of the signer private key of the client config.
+func New(clientCfg clients.EigenDAClientConfig, cfg Config, signers []core.BlobRequestSigner, l log.Logger, m metrics.Metricer) (*Pool, error) {
+	if len(signers) > 0 {
+		clientCfg.SignerPrivateKeyHex = ""
+	}
+
+	rpcs := append([]string{clientCfg.RPC}, cfg.BackupRPCs...)
+	cs := make([]*clients.EigenDAClient, len(rpcs))
+	for i, rpc := range rpcs {
+		endpointCfg := clientCfg
+		endpointCfg.RPC = rpc
+
+		client, err := clients.NewEigenDAClient(l.With("endpoint", rpc), endpointCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for disperser %s: %w", rpc, err)
+		}
+		if len(signers) > 0 {
+			host, port, err := net.SplitHostPort(rpc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse disperser RPC %s: %w", rpc, err)
+			}
+			llConfig := clients.NewConfig(host, port, client.Config.ResponseTimeout, !client.Config.DisableTLS)
+			client.Client = signer.NewDisperserClient(llConfig, signers)
+		}
+		cs[i] = client
+	}
+	return NewWithClients(cs, cfg, l, m), nil
+}
+
+// NewWithClients ... pool over already created clients, in priority order
+func NewWithClients(cs []*clients.EigenDAClient, cfg Config, l log.Logger, m metrics.Metricer) *Pool {
+	p := &Pool{
+		endpoints: make([]*Endpoint, len(cs)),
+		cfg:       cfg,
+		log:       l,
+		metrics:   m,
+	}
+	for i, c := range cs {
+		p.endpoints[i] = &Endpoint{RPC: c.Config.RPC, Client: c, healthy: true}
+		m.RecordDisperserHealth(c.Config.RPC, true)
+	}
+	return p
+}
+
+// Primary ... highest priority endpoint, whose client config applies to every endpoint
+func (p *Pool) Primary() *Endpoint {
+	return p.endpoints[0]
+}
+
+// Get ... returns the endpoint with the given RPC or nil if it's not part of the pool
+func (p *Pool) Get(rpc string) *Endpoint {
+	for _, e := range p.endpoints {
+		if e.RPC == rpc {
+			return e
+		}
+	}
+	return nil
+}
+
+// Ordered ... returns the healthy endpoints in priority order, followed by the unhealthy ones
+// which are only tried as a last resort
+func (p *Pool) Ordered() []*Endpoint {
+	ordered := make([]*Endpoint, 0, len(p.endpoints))
+	var unhealthy []*Endpoint
+	for _, e := range p.endpoints {
+		if e.Healthy() {
+			ordered = append(ordered, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(ordered, unhealthy...)
+}
+
+// Report records the outcome of a request to the endpoint. Only unavailability errors count
+// towards marking the endpoint unhealthy, since other errors are about the request itself.
+func (p *Pool) Report(e *Endpoint, method string, err error) {
+	result := "success"
+	switch {
+	case err == nil:
+		p.recordSuccess(e)
+	case IsUnavailable(err):
+		result = "unavailable"
+		p.recordFailure(e, err)
+	default:
+		result = "error"
+		// the disperser answered, so it's up
+		p.recordSuccess(e)
+	}
+	p.metrics.RecordDisperserRequest(e.RPC, method, result)
+}
+
+func (p *Pool) recordFailure(e *Endpoint, err error) {
+	e.mu.Lock()
+	e.failures++
+	unhealthy := e.healthy && e.failures >= p.cfg.FailureThreshold
+	if unhealthy {
+		e.healthy = false
+	}
+	e.mu.Unlock()
+
+	if unhealthy {
+		p.log.Warn("Disperser endpoint is unhealthy, failing over to the next endpoint", "endpoint", e.RPC, "err", err)
+		p.metrics.RecordDisperserHealth(e.RPC, false)
+	}
+}
+
+func (p *Pool) recordSuccess(e *Endpoint) {
+	e.mu.Lock()
+	e.failures = 0
+	recovered := !e.healthy
+	e.healthy = true
+	e.mu.Unlock()
+
+	if recovered {
+		p.log.Info("Disperser endpoint is healthy again", "endpoint", e.RPC)
+		p.metrics.RecordDisperserHealth(e.RPC, true)
+	}
+}
+
+// Start health checks every endpoint on the configured interval until the context is done. Health
+// checks are only needed to bring back failed endpoints, so they're skipped when there is a
+// single endpoint.
+func (p *Pool) Start(ctx context.Context) {
+	if len(p.endpoints) < 2 || p.cfg.HealthCheckInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.checkHealth(ctx)
+			}
+		}
+	}()
+}
+
+func (p *Pool) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *Endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, e.Client.Config.ResponseTimeout)
+			defer cancel()
+
+			_, err := e.Client.Client.GetBlobStatus(ctx, healthCheckRequestID)
+			p.Report(e, MethodHealthCheck, err)
+		}(e)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,100 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestPool(t *testing.T, rpcs ...string) *Pool {
+	p, err := New(clients.EigenDAClientConfig{
+		RPC:                      rpcs[0],
+		StatusQueryTimeout:       time.Second,
+		StatusQueryRetryInterval: 100 * time.Millisecond,
+		ResponseTimeout:          time.Second,
+		DisableTLS:               true,
+	}, Config{
+		BackupRPCs:          rpcs[1:],
+		HealthCheckInterval: 10 * time.Millisecond,
+		FailureThreshold:    2,
+	}, nil, log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+	return p
+}
+
+func rpcs(endpoints []*Endpoint) []string {
+	out := make([]string, len(endpoints))
+	for i, e := range endpoints {
+		out[i] = e.RPC
+	}
+	return out
+}
+
+func TestFailoverOrder(t *testing.T) {
+	p := newTestPool(t, "primary:443", "backup-1:443", "backup-2:443")
+	primary := p.Get("primary:443")
+	require.Equal(t, primary, p.Primary())
+	require.Equal(t, []string{"primary:443", "backup-1:443", "backup-2:443"}, rpcs(p.Ordered()))
+
+	unavailable := status.Error(codes.Unavailable, "down for maintenance")
+
+	// a single failure is below the threshold
+	p.Report(primary, MethodDisperse, unavailable)
+	require.True(t, primary.Healthy())
+
+	// errors about the request itself reset the failure count since the disperser answered
+	p.Report(primary, MethodDisperse, status.Error(codes.InvalidArgument, "blob too large"))
+	p.Report(primary, MethodDisperse, unavailable)
+	require.True(t, primary.Healthy())
+
+	p.Report(primary, MethodDisperse, unavailable)
+	require.False(t, primary.Healthy())
+	// unhealthy endpoints are only tried as a last resort
+	require.Equal(t, []string{"backup-1:443", "backup-2:443", "primary:443"}, rpcs(p.Ordered()))
+
+	p.Report(primary, MethodRetrieve, nil)
+	require.True(t, primary.Healthy())
+	require.Equal(t, []string{"primary:443", "backup-1:443", "backup-2:443"}, rpcs(p.Ordered()))
+}
+
+func TestHealthCheckMarksUnreachableEndpoints(t *testing.T) {
+	// nothing listens on port 1
+	p := newTestPool(t, "127.0.0.1:1", "127.0.0.1:2")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	require.Eventually(t, func() bool {
+		return !p.Primary().Healthy() && !p.Get("127.0.0.1:2").Healthy()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestIsUnavailable(t *testing.T) {
+	require.True(t, IsUnavailable(status.Error(codes.Unavailable, "")))
+	require.True(t, IsUnavailable(status.Error(codes.ResourceExhausted, "")))
+	require.True(t, IsUnavailable(context.DeadlineExceeded))
+	require.False(t, IsUnavailable(status.Error(codes.NotFound, "")))
+	require.False(t, IsUnavailable(status.Error(codes.InvalidArgument, "")))
+}
+
+func TestDefaultConfig(t *testing.T) {
+	require.NoError(t, DefaultConfig().Check())
+
+	// a single endpoint stays healthy until it fails the default threshold of requests in a row
+	client := &clients.EigenDAClient{Config: clients.EigenDAClientConfig{RPC: "primary:443"}}
+	p := NewWithClients([]*clients.EigenDAClient{client}, DefaultConfig(), log.New(), metrics.NoopMetrics)
+	unavailable := status.Error(codes.Unavailable, "down for maintenance")
+	for i := uint(1); i < DefaultConfig().FailureThreshold; i++ {
+		p.Report(p.Primary(), MethodDisperse, unavailable)
+		require.True(t, p.Primary().Healthy())
+	}
+	p.Report(p.Primary(), MethodDisperse, unavailable)
+	require.False(t, p.Primary().Healthy())
+}
@@ -0,0 +1,54 @@
+package journal
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	DirFlagName       = withFlagPrefix("dir")
+	RetentionFlagName = withFlagPrefix("retention")
+)
+
+func withFlagPrefix(s string) string {
+	return "eigenda.journal." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_EIGENDA_JOURNAL_" + s}
+}
+
+// Config ... dispersal journal configuration, the journal is disabled when Dir is empty
+type Config struct {
+	Dir string
+	// how long recovered or failed entries are kept for inspection before being pruned
+	Retention time.Duration
+}
+
+// CLIFlags ... used for dispersal journal configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     DirFlagName,
+			Usage:    "Directory of the write-ahead journal of in-flight EigenDA dispersals. When set, dispersals interrupted by a restart are resumed on startup. Disabled when empty.",
+			EnvVars:  withEnvPrefix(envPrefix, "DIR"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     RetentionFlagName,
+			Usage:    "Duration that recovered, failed or unknown dispersals are kept in the journal for inspection.",
+			Value:    24 * time.Hour,
+			EnvVars:  withEnvPrefix(envPrefix, "RETENTION"),
+			Category: category,
+		},
+	}
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	return Config{
+		Dir:       ctx.String(DirFlagName),
+		Retention: ctx.Duration(RetentionFlagName),
+	}
+}
@@ -0,0 +1,183 @@
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// State ... progress of a journaled dispersal
+type State string
+
+const (
+	// StateSubmitting ... blob is about to be sent to the disperser, it's unknown whether the
+	// disperser accepted it until the request ID is recorded
+	StateSubmitting State = "submitting"
+	// StateDispersed ... disperser accepted the blob and the request ID can be polled for its status
+	StateDispersed State = "dispersed"
+	// StateConfirmed ... blob batch was confirmed and the cert is recorded
+	StateConfirmed State = "confirmed"
+
+	// StateRecovered ... dispersal was finished after a restart, the cert is recorded
+	StateRecovered State = "recovered"
+	// StateFailed ... dispersal was resumed after a restart but failed, see the entry error
+	StateFailed State = "failed"
+	// StateUnknown ... proxy stopped before the disperser acknowledged the blob, so it may or
+	// may not have been dispersed (and paid for)
+	StateUnknown State = "unknown"
+)
+
+// Outstanding ... returns true for states that were interrupted mid dispersal
+func (s State) Outstanding() bool {
+	return s == StateSubmitting || s == StateDispersed || s == StateConfirmed
+}
+
+// Entry ... write-ahead record of a single dispersal
+type Entry struct {
+	ID string `json:"id"`
+	// keccak256 hash of the payload that was dispersed
+	PayloadHash common.Hash `json:"payload_hash"`
+	// disperser RPC the blob was sent to, its request ID can only be polled there
+	Endpoint  string        `json:"endpoint,omitempty"`
+	RequestID hexutil.Bytes `json:"request_id,omitempty"`
+	State     State         `json:"state"`
+	// RLP encoded cert (only set once the blob is confirmed)
+	Cert      hexutil.Bytes `json:"cert,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// NewEntry ... creates a submitting entry with a random ID for the given payload
+func NewEntry(payload []byte) (*Entry, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate journal entry id: %w", err)
+	}
+
+	now := time.Now()
+	return &Entry{
+		ID:          hex.EncodeToString(id),
+		PayloadHash: crypto.Keccak256Hash(payload),
+		State:       StateSubmitting,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Journal keeps in-flight dispersals on disk so that they survive proxy restarts. Every entry is
+// stored as a JSON file named by its ID.
+type Journal struct {
+	dir string
+}
+
+// New ... creates the journal directory if it doesn't exist yet
+func New(dir string) (*Journal, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("dispersal journal requires a directory path")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create dispersal journal directory %s: %w", dir, err)
+	}
+
+	return &Journal{dir: dir}, nil
+}
+
+func (j *Journal) path(id string) string {
+	return filepath.Join(j.dir, id+".json")
+}
+
+// Put writes the entry to a temporary file, syncs it and then renames it into place, so that a
+// crash never leaves a partially written entry behind.
+func (j *Journal) Put(e *Entry) error {
+	e.UpdatedAt = time.Now()
+	value, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(j.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), j.path(e.ID))
+}
+
+// Remove deletes the entry, removing an unknown entry is a no-op
+func (j *Journal) Remove(id string) error {
+	err := os.Remove(j.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Entries returns all entries in the journal
+func (j *Journal) Entries() ([]*Entry, error) {
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		value, err := os.ReadFile(filepath.Join(j.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var e Entry
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal journal entry %s: %w", file.Name(), err)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+// Prune removes entries that are no longer outstanding and haven't been updated for the
+// retention period
+func (j *Journal) Prune(retention time.Duration) error {
+	entries, err := j.Entries()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if !e.State.Outstanding() && time.Since(e.UpdatedAt) >= retention {
+			if err := j.Remove(e.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalPutRemove(t *testing.T) {
+	j, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	entry, err := NewEntry([]byte("payload"))
+	require.NoError(t, err)
+	require.Equal(t, StateSubmitting, entry.State)
+	require.NoError(t, j.Put(entry))
+
+	entry.State = StateDispersed
+	entry.RequestID = []byte{0x1, 0x2}
+	require.NoError(t, j.Put(entry))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entry.ID, entries[0].ID)
+	require.Equal(t, entry.PayloadHash, entries[0].PayloadHash)
+	require.Equal(t, StateDispersed, entries[0].State)
+	require.Equal(t, entry.RequestID, entries[0].RequestID)
+
+	require.NoError(t, j.Remove(entry.ID))
+	// removing an unknown entry is a no-op
+	require.NoError(t, j.Remove(entry.ID))
+
+	entries, err = j.Entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestJournalIgnoresTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("{"), 0o600))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestJournalPrune(t *testing.T) {
+	j, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	outstanding, err := NewEntry([]byte("outstanding"))
+	require.NoError(t, err)
+	require.NoError(t, j.Put(outstanding))
+
+	recovered, err := NewEntry([]byte("recovered"))
+	require.NoError(t, err)
+	recovered.State = StateRecovered
+	require.NoError(t, j.Put(recovered))
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, j.Prune(5*time.Millisecond))
+
+	// outstanding entries are never pruned
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, outstanding.ID, entries[0].ID)
+}
@@ -0,0 +1,105 @@
+package mockdisperser
+
+import (
+	"sort"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// buildMerkleTree computes the keccak256 merkle root over the blob header hashes of a batch and the
+// inclusion proof of every leaf. Leaves are padded with zero hashes up to the next power of two so that
+// every proof is non-empty, matching verify.ProcessInclusionProof.
+func buildMerkleTree(leaves [][32]byte) ([32]byte, [][]byte) {
+	size := 2
+	for size < len(leaves) {
+		size *= 2
+	}
+
+	layer := make([][32]byte, size)
+	copy(layer, leaves)
+
+	proofs := make([][]byte, len(leaves))
+	indexes := make([]int, len(leaves))
+	for i := range leaves {
+		indexes[i] = i
+	}
+
+	for len(layer) > 1 {
+		for i, idx := range indexes {
+			sibling := layer[idx^1]
+			proofs[i] = append(proofs[i], sibling[:]...)
+			indexes[i] = idx / 2
+		}
+
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(layer[2*i][:], layer[2*i+1][:])
+		}
+		layer = next
+	}
+
+	return layer[0], proofs
+}
+
+// batchQuorums returns the union of all quorums blobs in a batch were dispersed to, with the percentage
+// of stake that signed for each of them.
+func batchQuorums(blobs []*blob, signedPercentage uint8) ([]byte, []byte) {
+	seen := make(map[uint8]bool)
+	quorums := make([]byte, 0)
+	for _, b := range blobs {
+		for _, q := range b.quorums {
+			if !seen[q] {
+				seen[q] = true
+				quorums = append(quorums, q)
+			}
+		}
+	}
+	sort.Slice(quorums, func(i, j int) bool { return quorums[i] < quorums[j] })
+
+	signed := make([]byte, len(quorums))
+	for i := range signed {
+		signed[i] = signedPercentage
+	}
+	return quorums, signed
+}
+
+// hashReducedBatchHeader replicates core.BatchHeader.GetBatchHeaderHash, which is the batch header hash
+// clients use to retrieve blobs.
+func hashReducedBatchHeader(root [32]byte, referenceBlockNumber uint32) ([32]byte, error) {
+	reducedBatchHeaderType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "blobHeadersRoot", Type: "bytes32"},
+		{Name: "referenceBlockNumber", Type: "uint32"},
+	})
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	arguments := abi.Arguments{
+		{Type: reducedBatchHeaderType},
+	}
+
+	bytes, err := arguments.Pack(struct {
+		BlobHeadersRoot      [32]byte
+		ReferenceBlockNumber uint32
+	}{
+		BlobHeadersRoot:      root,
+		ReferenceBlockNumber: referenceBlockNumber,
+	})
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return crypto.Keccak256Hash(bytes), nil
+}
+
+func toBindingHeader(h *disperser.BatchHeader) *binding.IEigenDAServiceManagerBatchHeader {
+	return &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte(h.GetBatchRoot()),
+		QuorumNumbers:         h.GetQuorumNumbers(),
+		SignedStakeForQuorums: h.GetQuorumSignedPercentages(),
+		ReferenceBlockNumber:  h.GetReferenceBlockNumber(),
+	}
+}
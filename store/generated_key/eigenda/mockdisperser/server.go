@@ -0,0 +1,571 @@
+package mockdisperser
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/Layr-Labs/eigenda/encoding/rs"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// mirrors the disperser's hard limit on blob size
+	DefaultMaxBlobSizeBytes = 16 * 1024 * 1024
+	// quorums 0 and 1 are required for every blob, any higher quorum can be requested as a custom quorum
+	DefaultQuorumCount = 3
+
+	// large enough to always reach the blob size check instead of failing at the transport layer
+	maxMessageSizeBytes = 100 * 1024 * 1024
+)
+
+// Config ... mock disperser configuration
+type Config struct {
+	// how often pending blobs are grouped into a batch and confirmed
+	BatchInterval time.Duration
+	// how long a confirmed batch waits before being reported as finalized
+	FinalizationDelay time.Duration
+	MaxBlobSizeBytes  uint64
+	// number of quorums registered on the mock network, custom quorums must be below this number
+	QuorumCount uint8
+	// security parameters attached to every dispersed blob
+	AdversaryThresholdPercentage    uint32
+	ConfirmationThresholdPercentage uint32
+	QuorumSignedPercentage          uint8
+	// BlockNumber returns the current L1 block number that is used as the reference and confirmation
+	// block number of batches. Defaults to a counter that is incremented on every batch.
+	BlockNumber func() uint64
+	// OnBatchConfirmed is called whenever a batch is confirmed so that the batch metadata hash can be
+	// bridged to a (simulated) EigenDAServiceManager contract.
+	OnBatchConfirmed func(batchID uint32, batchMetadataHash [32]byte) error
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BatchInterval:                   100 * time.Millisecond,
+		FinalizationDelay:               time.Second,
+		MaxBlobSizeBytes:                DefaultMaxBlobSizeBytes,
+		QuorumCount:                     DefaultQuorumCount,
+		AdversaryThresholdPercentage:    33,
+		ConfirmationThresholdPercentage: 55,
+		QuorumSignedPercentage:          100,
+	}
+}
+
+// Faults ... failures injected into upcoming requests to exercise client retries. Every counter is
+// decremented as its fault gets injected.
+type Faults struct {
+	// number of upcoming dispersal requests rejected as unavailable
+	UnavailableDispersals int
+	// number of upcoming batches whose blobs end up FAILED
+	FailedBatches int
+	// number of upcoming batches whose blobs end up with INSUFFICIENT_SIGNATURES
+	InsufficientSignatureBatches int
+}
+
+// blob ... a dispersed blob and its current processing state
+type blob struct {
+	data        []byte
+	quorums     []uint8
+	status      disperser.BlobStatus
+	info        *disperser.BlobInfo
+	confirmedAt time.Time
+}
+
+// Server is a local implementation of the EigenDA disperser gRPC service. Blobs are grouped into
+// batches on a fixed interval and receive certificates with real KZG commitments and merkle inclusion
+// proofs, so that the high-level EigenDA client and the eigenda store can be exercised without network access.
+type Server struct {
+	disperser.UnimplementedDisperserServer
+
+	cfg      Config
+	log      log.Logger
+	verifier *verify.Verifier
+
+	mu        sync.Mutex
+	requests  map[string]*blob
+	pending   [][]byte
+	batches   map[string][]*blob
+	nextBatch uint32
+	blockNum  uint64
+	faults    Faults
+	// account IDs of the authenticated dispersals, in the order they were accepted
+	accounts []string
+
+	grpcServer *grpc.Server
+	listener   net.Listener
+	cancel     context.CancelFunc
+}
+
+var _ disperser.DisperserServer = (*Server)(nil)
+
+// New ... constructor
+func New(cfg Config, verifier *verify.Verifier, l log.Logger) *Server {
+	return &Server{
+		cfg:      cfg,
+		log:      l,
+		verifier: verifier,
+		requests: make(map[string]*blob),
+		batches:  make(map[string][]*blob),
+	}
+}
+
+// Start serves the disperser API on the given address (e.g, "127.0.0.1:0") and starts batching.
+func (s *Server) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	s.listener = listener
+
+	s.grpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSizeBytes))
+	disperser.RegisterDisperserServer(s.grpcServer, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.batchingLoop(ctx)
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.log.Error("mock disperser server stopped", "err", err)
+		}
+	}()
+
+	s.log.Info("Started mock EigenDA disperser", "endpoint", listener.Addr().String())
+	return nil
+}
+
+// InjectFaults replaces the faults injected into upcoming requests.
+func (s *Server) InjectFaults(f Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = f
+}
+
+// Endpoint returns the host:port the server is listening on.
+func (s *Server) Endpoint() string {
+	return s.listener.Addr().String()
+}
+
+// Accounts returns the account IDs of the accepted authenticated dispersals, in the order they were accepted.
+func (s *Server) Accounts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.accounts...)
+}
+
+func (s *Server) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+// DisperseBlob accepts a blob for dispersal and returns a request ID that can be polled via GetBlobStatus.
+func (s *Server) DisperseBlob(_ context.Context, req *disperser.DisperseBlobRequest) (*disperser.DisperseBlobReply, error) {
+	requestID, err := s.enqueue(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &disperser.DisperseBlobReply{
+		Result:    disperser.BlobStatus_PROCESSING,
+		RequestId: requestID,
+	}, nil
+}
+
+// DisperseBlobAuthenticated runs the challenge/response authentication protocol before accepting the blob.
+func (s *Server) DisperseBlobAuthenticated(stream disperser.Disperser_DisperseBlobAuthenticatedServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	req := msg.GetDisperseRequest()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "expected DisperseBlobRequest")
+	}
+
+	pubKeyBytes, err := hexutil.Decode(req.GetAccountId())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to decode account ID: %v", err)
+	}
+
+	var nonce [4]byte
+	if _, err = rand.Read(nonce[:]); err != nil {
+		return status.Errorf(codes.Internal, "failed to generate challenge: %v", err)
+	}
+	challenge := binary.BigEndian.Uint32(nonce[:])
+
+	err = stream.Send(&disperser.AuthenticatedReply{Payload: &disperser.AuthenticatedReply_BlobAuthHeader{
+		BlobAuthHeader: &disperser.BlobAuthHeader{ChallengeParameter: challenge},
+	}})
+	if err != nil {
+		return err
+	}
+
+	msg, err = stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	authData := msg.GetAuthenticationData()
+	if authData == nil {
+		return status.Error(codes.InvalidArgument, "expected AuthenticationData")
+	}
+
+	// the client signs keccak256(challenge) with the key that belongs to its account ID
+	sig := authData.GetAuthenticationData()
+	if len(sig) != crypto.SignatureLength {
+		return status.Error(codes.Unauthenticated, "invalid signature length")
+	}
+	pubKey, err := crypto.SigToPub(crypto.Keccak256(nonce[:]), sig)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "failed to recover public key: %v", err)
+	}
+	if !bytes.Equal(crypto.FromECDSAPub(pubKey), pubKeyBytes) {
+		return status.Error(codes.Unauthenticated, "signature does not match account ID")
+	}
+
+	requestID, err := s.enqueue(req)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.accounts = append(s.accounts, req.GetAccountId())
+	s.mu.Unlock()
+
+	return stream.Send(&disperser.AuthenticatedReply{Payload: &disperser.AuthenticatedReply_DisperseReply{
+		DisperseReply: &disperser.DisperseBlobReply{
+			Result:    disperser.BlobStatus_PROCESSING,
+			RequestId: requestID,
+		},
+	}})
+}
+
+// GetBlobStatus reports the processing status of a blob and, once confirmed, its certificate.
+func (s *Server) GetBlobStatus(_ context.Context, req *disperser.BlobStatusRequest) (*disperser.BlobStatusReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.requests[string(req.GetRequestId())]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no blob found for request ID")
+	}
+
+	if b.status == disperser.BlobStatus_CONFIRMED && time.Since(b.confirmedAt) >= s.cfg.FinalizationDelay {
+		b.status = disperser.BlobStatus_FINALIZED
+	}
+
+	return &disperser.BlobStatusReply{
+		Status: b.status,
+		Info:   b.info,
+	}, nil
+}
+
+// RetrieveBlob returns the encoded blob for a batch header hash and blob index.
+func (s *Server) RetrieveBlob(_ context.Context, req *disperser.RetrieveBlobRequest) (*disperser.RetrieveBlobReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, ok := s.batches[string(req.GetBatchHeaderHash())]
+	if !ok || int(req.GetBlobIndex()) >= len(batch) {
+		return nil, status.Error(codes.NotFound, "no blob found for batch header hash and blob index")
+	}
+
+	return &disperser.RetrieveBlobReply{Data: batch[req.GetBlobIndex()].data}, nil
+}
+
+// enqueue validates the dispersal request the same way the disperser does and queues it for the next batch.
+func (s *Server) enqueue(req *disperser.DisperseBlobRequest) ([]byte, error) {
+	data := req.GetData()
+	if len(data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "blob is empty")
+	}
+	if uint64(len(data)) > s.cfg.MaxBlobSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "blob size cannot exceed %v bytes", s.cfg.MaxBlobSizeBytes)
+	}
+	if _, err := rs.ToFrArray(data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "encountered an error to convert a 32-bytes into a valid field element: %v", err)
+	}
+
+	// quorums 0 and 1 are always required
+	quorums := []uint8{0, 1}
+	for _, q := range req.GetCustomQuorumNumbers() {
+		if q >= uint32(s.cfg.QuorumCount) {
+			return nil, status.Errorf(codes.InvalidArgument, "custom quorum number %d must be less than the number of quorums %d", q, s.cfg.QuorumCount)
+		}
+		if q == 0 || q == 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "custom quorum number %d is a required quorum", q)
+		}
+		quorums = append(quorums, uint8(q)) // #nosec G115
+	}
+
+	requestID := make([]byte, 32)
+	if _, err := rand.Read(requestID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate request ID: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.faults.UnavailableDispersals > 0 {
+		s.faults.UnavailableDispersals--
+		return nil, status.Error(codes.Unavailable, "injected fault: disperser unavailable")
+	}
+
+	s.requests[string(requestID)] = &blob{
+		data:    data,
+		quorums: quorums,
+		status:  disperser.BlobStatus_PROCESSING,
+	}
+	s.pending = append(s.pending, requestID)
+
+	return requestID, nil
+}
+
+// batchingLoop ... groups pending blobs into batches on a regular interval
+func (s *Server) batchingLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.BatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.createBatch(); err != nil {
+				s.log.Error("mock disperser failed to create batch", "err", err)
+			}
+		}
+	}
+}
+
+// createBatch builds certificates for all pending blobs, confirms the batch and makes the blobs retrievable.
+func (s *Server) createBatch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	switch {
+	case s.faults.FailedBatches > 0:
+		s.faults.FailedBatches--
+		s.fail(errors.New("injected fault"))
+		return nil
+	case s.faults.InsufficientSignatureBatches > 0:
+		s.faults.InsufficientSignatureBatches--
+		for _, requestID := range s.pending {
+			s.requests[string(requestID)].status = disperser.BlobStatus_INSUFFICIENT_SIGNATURES
+		}
+		s.pending = nil
+		s.log.Warn("mock disperser batch has insufficient signatures", "err", "injected fault")
+		return nil
+	}
+
+	blobs := make([]*blob, len(s.pending))
+	headers := make([]*disperser.BlobHeader, len(s.pending))
+	leaves := make([][32]byte, len(s.pending))
+
+	for i, requestID := range s.pending {
+		b := s.requests[string(requestID)]
+		blobs[i] = b
+
+		commitment, err := s.verifier.Commit(b.data)
+		if err != nil {
+			s.fail(err)
+			return err
+		}
+
+		params := make([]*disperser.BlobQuorumParam, len(b.quorums))
+		for j, q := range b.quorums {
+			params[j] = &disperser.BlobQuorumParam{
+				QuorumNumber:                    uint32(q),
+				AdversaryThresholdPercentage:    s.cfg.AdversaryThresholdPercentage,
+				ConfirmationThresholdPercentage: s.cfg.ConfirmationThresholdPercentage,
+				ChunkLength:                     1,
+			}
+		}
+
+		headers[i] = &disperser.BlobHeader{
+			Commitment: &common.G1Commitment{
+				X: commitment.X.Marshal(),
+				Y: commitment.Y.Marshal(),
+			},
+			DataLength:       uint32((len(b.data) + 31) / 32), // #nosec G115
+			BlobQuorumParams: params,
+		}
+
+		cert := &verify.Certificate{BlobHeader: headers[i]}
+		leaf, err := verify.HashEncodeBlobHeader(cert.ReadBlobHeader())
+		if err != nil {
+			s.fail(err)
+			return err
+		}
+		leaves[i] = leaf
+	}
+
+	root, proofs := buildMerkleTree(leaves)
+	batchID := s.nextBatch
+	s.nextBatch++
+
+	refBlock := s.currentBlockNumber()
+	quorums, signed := batchQuorums(blobs, s.cfg.QuorumSignedPercentage)
+
+	batchHeader := &disperser.BatchHeader{
+		BatchRoot:               root[:],
+		QuorumNumbers:           quorums,
+		QuorumSignedPercentages: signed,
+		ReferenceBlockNumber:    uint32(refBlock), // #nosec G115
+	}
+
+	batchHeaderHash, err := hashReducedBatchHeader(root, batchHeader.ReferenceBlockNumber)
+	if err != nil {
+		s.fail(err)
+		return err
+	}
+
+	var signatoryRecordHash [32]byte
+	if _, err := rand.Read(signatoryRecordHash[:]); err != nil {
+		s.fail(err)
+		return err
+	}
+
+	confirmationBlock := uint32(s.currentBlockNumber()) // #nosec G115
+	metadata := &disperser.BatchMetadata{
+		BatchHeader:             batchHeader,
+		SignatoryRecordHash:     signatoryRecordHash[:],
+		Fee:                     []byte{0},
+		ConfirmationBlockNumber: confirmationBlock,
+		BatchHeaderHash:         batchHeaderHash[:],
+	}
+
+	metadataHash, err := verify.HashBatchMetadata(toBindingHeader(batchHeader), signatoryRecordHash, confirmationBlock)
+	if err != nil {
+		s.fail(err)
+		return err
+	}
+
+	if s.cfg.OnBatchConfirmed != nil {
+		if err := s.cfg.OnBatchConfirmed(batchID, metadataHash); err != nil {
+			s.fail(err)
+			return fmt.Errorf("failed to confirm batch %d: %w", batchID, err)
+		}
+	}
+
+	now := time.Now()
+	for i, b := range blobs {
+		quorumIndexes := make([]byte, len(b.quorums))
+		for j, q := range b.quorums {
+			for k, bq := range quorums {
+				if bq == q {
+					quorumIndexes[j] = byte(k) // #nosec G115
+				}
+			}
+		}
+
+		b.info = &disperser.BlobInfo{
+			BlobHeader: headers[i],
+			BlobVerificationProof: &disperser.BlobVerificationProof{
+				BatchId:        batchID,
+				BlobIndex:      uint32(i), // #nosec G115
+				BatchMetadata:  metadata,
+				InclusionProof: proofs[i],
+				QuorumIndexes:  quorumIndexes,
+			},
+		}
+		b.status = disperser.BlobStatus_CONFIRMED
+		b.confirmedAt = now
+	}
+
+	s.batches[string(batchHeaderHash[:])] = blobs
+	s.pending = nil
+
+	s.log.Debug("mock disperser confirmed batch", "batchID", batchID, "blobs", len(blobs))
+	return nil
+}
+
+// ReconfirmBatches confirms every confirmed batch again at the current block, as the disperser does
+// when the transactions that confirmed its batches are reorged out. Blobs keep their batch ID and
+// inclusion proof, only the confirmation block of their batch metadata changes.
+func (s *Server) ReconfirmBatches() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	confirmationBlock := uint32(s.currentBlockNumber()) // #nosec G115
+	for _, blobs := range s.batches {
+		proof := blobs[0].info.GetBlobVerificationProof()
+		metadata := &disperser.BatchMetadata{
+			BatchHeader:             proof.GetBatchMetadata().GetBatchHeader(),
+			SignatoryRecordHash:     proof.GetBatchMetadata().GetSignatoryRecordHash(),
+			Fee:                     proof.GetBatchMetadata().GetFee(),
+			ConfirmationBlockNumber: confirmationBlock,
+			BatchHeaderHash:         proof.GetBatchMetadata().GetBatchHeaderHash(),
+		}
+
+		metadataHash, err := verify.HashBatchMetadata(toBindingHeader(metadata.BatchHeader),
+			[32]byte(metadata.SignatoryRecordHash), confirmationBlock)
+		if err != nil {
+			return err
+		}
+		if s.cfg.OnBatchConfirmed != nil {
+			if err := s.cfg.OnBatchConfirmed(proof.GetBatchId(), metadataHash); err != nil {
+				return fmt.Errorf("failed to reconfirm batch %d: %w", proof.GetBatchId(), err)
+			}
+		}
+
+		now := time.Now()
+		for _, b := range blobs {
+			// replaced rather than updated in place since previous status replies may still be in use
+			b.info = &disperser.BlobInfo{
+				BlobHeader: b.info.GetBlobHeader(),
+				BlobVerificationProof: &disperser.BlobVerificationProof{
+					BatchId:        b.info.GetBlobVerificationProof().GetBatchId(),
+					BlobIndex:      b.info.GetBlobVerificationProof().GetBlobIndex(),
+					BatchMetadata:  metadata,
+					InclusionProof: b.info.GetBlobVerificationProof().GetInclusionProof(),
+					QuorumIndexes:  b.info.GetBlobVerificationProof().GetQuorumIndexes(),
+				},
+			}
+			b.status = disperser.BlobStatus_CONFIRMED
+			b.confirmedAt = now
+		}
+		s.log.Debug("mock disperser reconfirmed batch", "batchID", proof.GetBatchId(), "confirmationBlock", confirmationBlock)
+	}
+	return nil
+}
+
+// fail marks all pending blobs as failed, mimicking a batch that couldn't be dispersed.
+func (s *Server) fail(err error) {
+	for _, requestID := range s.pending {
+		s.requests[string(requestID)].status = disperser.BlobStatus_FAILED
+	}
+	s.pending = nil
+	s.log.Warn("mock disperser failed batch", "err", err)
+}
+
+func (s *Server) currentBlockNumber() uint64 {
+	if s.cfg.BlockNumber != nil {
+		return s.cfg.BlockNumber()
+	}
+	s.blockNum++
+	return s.blockNum
+}
@@ -0,0 +1,147 @@
+package mockdisperser
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/clients"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPreimage   = "Four score and seven years ago"
+	testSignerHex  = "0000000000000000000100000000000000000000000000000000000000000000"
+	testMaxBlobLen = 3000 * 32
+)
+
+func getDefaultVerifierTestConfig() *verify.Config {
+	return &verify.Config{
+		VerifyCerts: false,
+		KzgConfig: &kzg.KzgConfig{
+			G1Path:          "../../../../resources/g1.point",
+			G2PowerOf2Path:  "../../../../resources/g2.point.powerOf2",
+			CacheDir:        "../../../../resources/SRSTables",
+			SRSOrder:        3000,
+			SRSNumberToLoad: 3000,
+			NumWorker:       uint64(runtime.GOMAXPROCS(0)),
+		},
+	}
+}
+
+func startMockDisperser(t *testing.T, cfg Config) (*Server, *verify.Verifier) {
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	server := New(cfg, verifier, log.New())
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	return server, verifier
+}
+
+func newClient(t *testing.T, endpoint string, signer string) *clients.EigenDAClient {
+	client, err := clients.NewEigenDAClient(log.New(), clients.EigenDAClientConfig{
+		RPC:                      endpoint,
+		StatusQueryTimeout:       10 * time.Second,
+		StatusQueryRetryInterval: 50 * time.Millisecond,
+		ResponseTimeout:          5 * time.Second,
+		DisableTLS:               true,
+		SignerPrivateKeyHex:      signer,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestPutGetBlob(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	server, verifier := startMockDisperser(t, cfg)
+	client := newClient(t, server.Endpoint(), testSignerHex)
+
+	ctx := context.Background()
+	blobInfo, err := client.PutBlob(ctx, []byte(testPreimage))
+	require.NoError(t, err)
+
+	// certificate carries a real kzg commitment to the encoded blob
+	encoded, err := client.GetCodec().EncodeBlob([]byte(testPreimage))
+	require.NoError(t, err)
+	cert := (*verify.Certificate)(blobInfo)
+	require.NoError(t, verifier.VerifyCommitment(cert.BlobHeader.Commitment, encoded))
+
+	// and a valid merkle inclusion proof against the batch root
+	cv := &verify.CertVerifier{}
+	err = cv.VerifyMerkleProof(cert.Proof().GetInclusionProof(), cert.BatchHeaderRoot(), cert.Proof().GetBlobIndex(), cert.ReadBlobHeader())
+	require.NoError(t, err)
+
+	actual, err := client.GetBlob(ctx, cert.Proof().GetBatchMetadata().GetBatchHeaderHash(), cert.Proof().GetBlobIndex())
+	require.NoError(t, err)
+	require.Equal(t, []byte(testPreimage), actual)
+}
+
+func TestBatchedBlobsHaveDistinctInclusionProofs(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	cfg.BatchInterval = 500 * time.Millisecond
+	server, _ := startMockDisperser(t, cfg)
+	client := newClient(t, server.Endpoint(), testSignerHex)
+
+	ctx := context.Background()
+	results := make(chan *verify.Certificate, 3)
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			blobInfo, err := client.PutBlob(ctx, []byte{byte(i), 1, 2, 3})
+			if err != nil {
+				errs <- err
+				return
+			}
+			results <- (*verify.Certificate)(blobInfo)
+		}(i)
+	}
+
+	cv := &verify.CertVerifier{}
+	for i := 0; i < 3; i++ {
+		var cert *verify.Certificate
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+		case cert = <-results:
+		}
+		err := cv.VerifyMerkleProof(cert.Proof().GetInclusionProof(), cert.BatchHeaderRoot(), cert.Proof().GetBlobIndex(), cert.ReadBlobHeader())
+		require.NoError(t, err)
+	}
+}
+
+func TestUnauthenticatedDispersalFails(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.MaxBlobSizeBytes = testMaxBlobLen
+	server, _ := startMockDisperser(t, cfg)
+	client := newClient(t, server.Endpoint(), "")
+
+	_, err := client.PutBlob(context.Background(), []byte(testPreimage))
+	require.Error(t, err)
+}
+
+func TestOversizedBlobIsRejected(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.MaxBlobSizeBytes = 64
+	server, _ := startMockDisperser(t, cfg)
+	client := newClient(t, server.Endpoint(), testSignerHex)
+
+	_, err := client.PutBlob(context.Background(), make([]byte, 1024))
+	require.ErrorContains(t, err, "blob size cannot exceed")
+}
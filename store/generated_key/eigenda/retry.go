@@ -0,0 +1,151 @@
+package eigenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorClass ... category of dispersal errors that can be selected for retries
+type ErrorClass string
+
+const (
+	// ErrorClassDisperser ... transient disperser errors (e.g, unavailable or rate limited)
+	ErrorClassDisperser ErrorClass = "disperser"
+	// ErrorClassFailed ... disperser reported the blob as FAILED, retrying re-disperses the blob
+	ErrorClassFailed ErrorClass = "failed"
+	// ErrorClassInsufficientSignatures ... not enough operators signed for the blob
+	ErrorClassInsufficientSignatures ErrorClass = "insufficient-signatures"
+	// ErrorClassTimeout ... blob wasn't confirmed within the status query timeout
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassPermanent ... errors that are never retried (e.g, invalid blobs or bad credentials)
+	ErrorClassPermanent ErrorClass = "permanent"
+)
+
+var (
+	errInsufficientSignatures = fmt.Errorf("%w with insufficient signatures", errDispersalFailed)
+	errStatusQueryTimeout     = errors.New("timed out waiting for EigenDA blob to confirm")
+)
+
+// RetryableErrorClasses ... error classes that can be retried
+var RetryableErrorClasses = []ErrorClass{
+	ErrorClassDisperser,
+	ErrorClassFailed,
+	ErrorClassInsufficientSignatures,
+	ErrorClassTimeout,
+}
+
+func StringToErrorClass(s string) (ErrorClass, error) {
+	for _, class := range RetryableErrorClasses {
+		if strings.ToLower(s) == string(class) {
+			return class, nil
+		}
+	}
+	return "", fmt.Errorf("unknown retryable dispersal error class: %s", s)
+}
+
+// RetryConfig ... policy for retrying failed dispersals in the store
+type RetryConfig struct {
+	// maximum number of dispersal attempts per put, 1 disables retries
+	MaxAttempts uint
+	// delay before the first retry, doubled after every attempt up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// error classes that are retried
+	RetryOn []ErrorClass
+}
+
+func (c RetryConfig) retries(class ErrorClass) bool {
+	for _, c := range c.RetryOn {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Check ... verifies that the retry policy is usable
+func (c RetryConfig) Check() error {
+	if c.MaxAttempts == 0 {
+		return fmt.Errorf("max dispersal attempts must be at least 1")
+	}
+	if c.MaxAttempts > 1 && c.Backoff <= 0 {
+		return fmt.Errorf("dispersal retry backoff must be positive")
+	}
+	if c.MaxBackoff < c.Backoff {
+		return fmt.Errorf("max dispersal retry backoff %s is lower than the initial backoff %s", c.MaxBackoff, c.Backoff)
+	}
+	return nil
+}
+
+// classifyDispersalError maps a dispersal error to the class used to decide whether it's retried
+func classifyDispersalError(err error) ErrorClass {
+	switch {
+	case errors.Is(err, errInsufficientSignatures):
+		return ErrorClassInsufficientSignatures
+	case errors.Is(err, errDispersalFailed):
+		return ErrorClassFailed
+	case errors.Is(err, errStatusQueryTimeout):
+		return ErrorClassTimeout
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted, codes.Internal, codes.Unknown:
+			return ErrorClassDisperser
+		}
+	}
+	return ErrorClassPermanent
+}
+
+// disperseWithRetries disperses the blob until an attempt succeeds, the error isn't retried
+// per the retry policy or the attempts are exhausted. Every attempt is journaled as a distinct
+// dispersal.
+func (e Store) disperseWithRetries(ctx context.Context, payload []byte, encodedBlob []byte) (*dispersal, error) {
+	policy := e.cfg.Retry
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+	backoff := policy.Backoff
+
+	for attempt := uint(1); ; attempt++ {
+		entry := e.journalSubmit(payload)
+		d, err := e.disperseBlob(ctx, encodedBlob, entry)
+		if err == nil {
+			e.metrics.RecordDispersalAttempt("success")
+			if attempt > 1 {
+				e.log.Info("Dispersal succeeded after retries", "attempt", attempt)
+			}
+			return d, nil
+		}
+
+		class := classifyDispersalError(err)
+		e.metrics.RecordDispersalAttempt(string(class))
+
+		if ctx.Err() != nil || attempt >= maxAttempts || !policy.retries(class) {
+			e.log.Error("Dispersal attempt failed", "attempt", attempt, "maxAttempts", maxAttempts, "class", class, "err", err)
+			if attempt > 1 {
+				return nil, fmt.Errorf("dispersal failed after %d attempts: %w", attempt, err)
+			}
+			return nil, err
+		}
+
+		e.log.Warn("Dispersal attempt failed, retrying", "attempt", attempt, "maxAttempts", maxAttempts, "class", class, "backoff", backoff, "err", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("dispersal retry interrupted after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
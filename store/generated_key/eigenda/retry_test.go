@@ -0,0 +1,113 @@
+package eigenda
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/store/generated_key/eigenda/mockdisperser"
+	"github.com/stretchr/testify/require"
+)
+
+// attemptRecorder ... metricer that records the results of dispersal attempts
+type attemptRecorder struct {
+	metrics.Metricer
+
+	mu      sync.Mutex
+	results []string
+}
+
+func (r *attemptRecorder) RecordDispersalAttempt(result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+}
+
+func testRetryConfig(maxAttempts uint, retryOn ...ErrorClass) RetryConfig {
+	return RetryConfig{
+		MaxAttempts: maxAttempts,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		RetryOn:     retryOn,
+	}
+}
+
+func TestDispersalRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		faults  mockdisperser.Faults
+		retry   RetryConfig
+		results []string
+		wantErr string
+	}{
+		{
+			name:    "TransientDisperserErrors",
+			faults:  mockdisperser.Faults{UnavailableDispersals: 2},
+			retry:   testRetryConfig(3, RetryableErrorClasses...),
+			results: []string{"disperser", "disperser", "success"},
+		},
+		{
+			name:    "RedispersesFailedBlobs",
+			faults:  mockdisperser.Faults{FailedBatches: 1},
+			retry:   testRetryConfig(2, ErrorClassFailed),
+			results: []string{"failed", "success"},
+		},
+		{
+			name:    "InsufficientSignatures",
+			faults:  mockdisperser.Faults{InsufficientSignatureBatches: 1},
+			retry:   testRetryConfig(2, ErrorClassInsufficientSignatures),
+			results: []string{"insufficient-signatures", "success"},
+		},
+		{
+			name:    "UnselectedErrorClassIsNotRetried",
+			faults:  mockdisperser.Faults{FailedBatches: 1},
+			retry:   testRetryConfig(3, ErrorClassDisperser),
+			results: []string{"failed"},
+			wantErr: "EigenDA blob dispersal failed",
+		},
+		{
+			name:    "AttemptsExhausted",
+			faults:  mockdisperser.Faults{UnavailableDispersals: 3},
+			retry:   testRetryConfig(2, RetryableErrorClasses...),
+			results: []string{"disperser", "disperser"},
+			wantErr: "dispersal failed after 2 attempts",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, server := newTestStoreWithRetries(t, nil, tt.retry)
+			recorder := &attemptRecorder{Metricer: metrics.NoopMetrics}
+			s.metrics = recorder
+			server.InjectFaults(tt.faults)
+
+			payload := []byte("retried dispersal")
+			encoded, err := s.client.GetCodec().EncodeBlob(payload)
+			require.NoError(t, err)
+
+			d, err := s.disperseWithRetries(context.Background(), payload, encoded)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, d.blobInfo)
+			}
+			require.Equal(t, tt.results, recorder.results)
+		})
+	}
+}
+
+func TestRetryConfigCheck(t *testing.T) {
+	require.NoError(t, RetryConfig{MaxAttempts: 1}.Check())
+	require.NoError(t, testRetryConfig(3, ErrorClassFailed).Check())
+	require.Error(t, RetryConfig{}.Check())
+	require.Error(t, RetryConfig{MaxAttempts: 2}.Check())
+	require.Error(t, RetryConfig{MaxAttempts: 2, Backoff: time.Second, MaxBackoff: time.Millisecond}.Check())
+}
@@ -0,0 +1,163 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	KeystoreFilesFlagName        = withFlagPrefix("keystore-files")
+	KeystorePasswordFileFlagName = withFlagPrefix("keystore-password-file")
+	RemoteURLsFlagName           = withFlagPrefix("remote-urls")
+	RemoteTimeoutFlagName        = withFlagPrefix("remote-timeout")
+	KMSProviderFlagName          = withFlagPrefix("kms-provider")
+	KMSKeyIDsFlagName            = withFlagPrefix("kms-key-ids")
+)
+
+func withFlagPrefix(s string) string {
+	return "eigenda.signer." + s
+}
+
+func withEnvPrefix(envPrefix, s string) []string {
+	return []string{envPrefix + "_EIGENDA_SIGNER_" + s}
+}
+
+// Config ... keys that authenticate dispersals with the disperser. Dispersals rotate across all
+// of the configured keys.
+type Config struct {
+	// encrypted JSON keystore files, all decrypted with the password in KeystorePasswordFile
+	KeystoreFiles        []string
+	KeystorePasswordFile string
+	// base URLs of HTTP remote signers
+	RemoteURLs []string
+	// timeout of remote signer and KMS requests
+	RemoteTimeout time.Duration
+	// registered KMS provider holding the KMSKeyIDs keys
+	KMSProvider string
+	KMSKeyIDs   []string
+}
+
+// Enabled ... returns true when any key is configured, in which case the signer private key hex
+// of the client config must be left empty
+func (c Config) Enabled() bool {
+	return len(c.KeystoreFiles) > 0 || len(c.RemoteURLs) > 0 || len(c.KMSKeyIDs) > 0
+}
+
+// Check ... verifies that every configured key can be loaded
+func (c Config) Check() error {
+	if len(c.KeystoreFiles) > 0 && c.KeystorePasswordFile == "" {
+		return fmt.Errorf("keystore password file is required when keystore files are set")
+	}
+	if (len(c.RemoteURLs) > 0 || len(c.KMSKeyIDs) > 0) && c.RemoteTimeout <= 0 {
+		return fmt.Errorf("remote signer timeout must be positive")
+	}
+	if len(c.KMSKeyIDs) > 0 {
+		if c.KMSProvider == "" {
+			return fmt.Errorf("KMS provider is required when KMS key IDs are set")
+		}
+		if _, ok := kmsProvider(c.KMSProvider); !ok {
+			return fmt.Errorf("unknown KMS provider %q, registered providers are %v", c.KMSProvider, KMSProviders())
+		}
+	}
+	return nil
+}
+
+// New ... creates a signer for every configured key
+func New(ctx context.Context, cfg Config) ([]core.BlobRequestSigner, error) {
+	var signers []core.BlobRequestSigner
+
+	for _, path := range cfg.KeystoreFiles {
+		s, err := NewKeystore(path, cfg.KeystorePasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, s)
+	}
+
+	for _, url := range cfg.RemoteURLs {
+		s, err := NewRemote(ctx, url, cfg.RemoteTimeout)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, s)
+	}
+
+	if len(cfg.KMSKeyIDs) > 0 {
+		factory, ok := kmsProvider(cfg.KMSProvider)
+		if !ok {
+			return nil, fmt.Errorf("unknown KMS provider %q", cfg.KMSProvider)
+		}
+		kms, err := factory(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s KMS client: %w", cfg.KMSProvider, err)
+		}
+		for _, keyID := range cfg.KMSKeyIDs {
+			s, err := NewKMS(ctx, kms, keyID, cfg.RemoteTimeout)
+			if err != nil {
+				return nil, err
+			}
+			signers = append(signers, s)
+		}
+	}
+
+	return signers, nil
+}
+
+// CLIFlags ... used for disperser signer configuration
+// category is used to group the flags in the help output (see https://cli.urfave.org/v2/examples/flags/#grouping)
+func CLIFlags(envPrefix, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     KeystoreFilesFlagName,
+			Usage:    "Encrypted JSON keystore files of the keys authenticating dispersals. Replaces eigenda.signer-private-key-hex.",
+			EnvVars:  withEnvPrefix(envPrefix, "KEYSTORE_FILES"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     KeystorePasswordFileFlagName,
+			Usage:    "File containing the password of the keystore files.",
+			EnvVars:  withEnvPrefix(envPrefix, "KEYSTORE_PASSWORD_FILE"),
+			Category: category,
+		},
+		&cli.StringSliceFlag{
+			Name:     RemoteURLsFlagName,
+			Usage:    "URLs of HTTP remote signers holding the keys authenticating dispersals.",
+			EnvVars:  withEnvPrefix(envPrefix, "REMOTE_URLS"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     RemoteTimeoutFlagName,
+			Usage:    "Timeout of remote signer and KMS requests.",
+			Value:    10 * time.Second,
+			EnvVars:  withEnvPrefix(envPrefix, "REMOTE_TIMEOUT"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     KMSProviderFlagName,
+			Usage:    "KMS provider holding the eigenda.signer.kms-key-ids keys. Providers are registered by the build.",
+			EnvVars:  withEnvPrefix(envPrefix, "KMS_PROVIDER"),
+			Category: category,
+		},
+		&cli.StringSliceFlag{
+			Name:     KMSKeyIDsFlagName,
+			Usage:    "IDs of the KMS keys authenticating dispersals.",
+			EnvVars:  withEnvPrefix(envPrefix, "KMS_KEY_IDS"),
+			Category: category,
+		},
+	}
+}
+
+func ReadConfig(ctx *cli.Context) Config {
+	return Config{
+		KeystoreFiles:        ctx.StringSlice(KeystoreFilesFlagName),
+		KeystorePasswordFile: ctx.String(KeystorePasswordFileFlagName),
+		RemoteURLs:           ctx.StringSlice(RemoteURLsFlagName),
+		RemoteTimeout:        ctx.Duration(RemoteTimeoutFlagName),
+		KMSProvider:          ctx.String(KMSProviderFlagName),
+		KMSKeyIDs:            ctx.StringSlice(KMSKeyIDsFlagName),
+	}
+}
@@ -0,0 +1,122 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMS ... key management service holding secp256k1 keys that never leave it. Implementations
+// are registered with RegisterKMS and selected with the eigenda.signer.kms-provider flag.
+type KMS interface {
+	// PublicKey returns the public key of the key with the given ID
+	PublicKey(ctx context.Context, keyID string) (*ecdsa.PublicKey, error)
+	// SignDigest signs the 32 byte digest with the key with the given ID and returns the ASN.1 DER
+	// encoded signature, which is what cloud KMSes return
+	SignDigest(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// KMSFactory ... creates the client of a KMS provider
+type KMSFactory func(ctx context.Context) (KMS, error)
+
+var (
+	kmsMu        sync.Mutex
+	kmsProviders = map[string]KMSFactory{}
+)
+
+// RegisterKMS makes a KMS provider available under the given name. It's meant to be called from
+// the init function of the package implementing the provider.
+func RegisterKMS(name string, factory KMSFactory) {
+	kmsMu.Lock()
+	defer kmsMu.Unlock()
+	if _, ok := kmsProviders[name]; ok {
+		panic(fmt.Sprintf("KMS provider %s registered twice", name))
+	}
+	kmsProviders[name] = factory
+}
+
+// KMSProviders ... names of the registered KMS providers
+func KMSProviders() []string {
+	kmsMu.Lock()
+	defer kmsMu.Unlock()
+	names := make([]string, 0, len(kmsProviders))
+	for name := range kmsProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func kmsProvider(name string) (KMSFactory, bool) {
+	kmsMu.Lock()
+	defer kmsMu.Unlock()
+	factory, ok := kmsProviders[name]
+	return factory, ok
+}
+
+// NewKMS ... signer for the key with the given ID held by the KMS
+func NewKMS(ctx context.Context, kms KMS, keyID string, timeout time.Duration) (core.BlobRequestSigner, error) {
+	pub, err := kms.PublicKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of KMS key %s: %w", keyID, err)
+	}
+
+	return &ecdsaSigner{
+		name: "kms",
+		pub:  pub,
+		sign: func(ctx context.Context, digest []byte) ([]byte, error) {
+			der, err := kms.SignDigest(ctx, keyID, digest)
+			if err != nil {
+				return nil, err
+			}
+			return toRecoverableSignature(der, digest, pub)
+		},
+		timeout: timeout,
+	}, nil
+}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// toRecoverableSignature converts a DER encoded ECDSA signature into the 65 byte [R || S || V]
+// format expected by the disperser, normalizing S to the lower half of the curve order and finding
+// the recovery ID that recovers to the public key.
+func toRecoverableSignature(der []byte, digest []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	var parsed struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid DER signature: %w", err)
+	}
+	if parsed.R.Sign() <= 0 || parsed.S.Sign() <= 0 || parsed.R.Cmp(secp256k1N) >= 0 || parsed.S.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("signature values out of range")
+	}
+	if parsed.S.Cmp(secp256k1HalfN) > 0 {
+		parsed.S.Sub(secp256k1N, parsed.S)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	parsed.R.FillBytes(sig[0:32])
+	parsed.S.FillBytes(sig[32:64])
+
+	expected := crypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		sig[crypto.RecoveryIDOffset] = v
+		recovered, err := crypto.Ecrecover(digest, sig)
+		if err == nil && bytes.Equal(recovered, expected) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature doesn't match the public key of the KMS key")
+}
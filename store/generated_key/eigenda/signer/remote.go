@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// remoteClient talks to an HTTP remote signer, which serves
+//
+//	GET  /public-key -> {"public_key": "0x04..."}  uncompressed secp256k1 public key
+//	POST /sign {"digest": "0x..."} -> {"signature": "0x..."}  65 byte [R || S || V] signature of the 32 byte digest
+type remoteClient struct {
+	url    string
+	client *http.Client
+}
+
+type remotePublicKeyResponse struct {
+	PublicKey hexutil.Bytes `json:"public_key"`
+}
+
+type remoteSignRequest struct {
+	Digest hexutil.Bytes `json:"digest"`
+}
+
+type remoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// NewRemote ... signer whose key is held by the HTTP remote signer at url. The public key is
+// fetched once, so the remote signer must be reachable on startup.
+func NewRemote(ctx context.Context, url string, timeout time.Duration) (core.BlobRequestSigner, error) {
+	r := &remoteClient{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+
+	var resp remotePublicKeyResponse
+	if err := r.do(ctx, http.MethodGet, "/public-key", nil, &resp); err != nil {
+		return nil, err
+	}
+	pub, err := crypto.UnmarshalPubkey(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer %s returned an invalid public key: %w", url, err)
+	}
+
+	return &ecdsaSigner{
+		name:    "remote",
+		pub:     pub,
+		sign:    r.sign,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *remoteClient) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	var resp remoteSignResponse
+	if err := r.do(ctx, http.MethodPost, "/sign", remoteSignRequest{Digest: digest}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+func (r *remoteClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach remote signer %s: %w", r.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("remote signer %s%s returned status %d: %s", r.url, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode remote signer %s%s response: %w", r.url, path, err)
+	}
+	return nil
+}
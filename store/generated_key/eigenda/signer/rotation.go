@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Layr-Labs/eigenda/api/clients"
+	disperser_rpc "github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/Layr-Labs/eigenda/disperser"
+)
+
+// NewDisperserClient ... disperser client that rotates across the signers, one per authenticated
+// dispersal, e.g. to spread the load over the rate limits of several accounts.
+//
+// The low level client asks the signer for the account ID and the signature in separate calls, so
+// a single signer can't rotate keys without racing concurrent dispersals. Instead every signer
+// gets its own client and each dispersal is sent through one of them.
+func NewDisperserClient(cfg *clients.Config, signers []core.BlobRequestSigner) clients.DisperserClient {
+	if len(signers) == 1 {
+		return clients.NewDisperserClient(cfg, signers[0])
+	}
+
+	cs := make([]clients.DisperserClient, len(signers))
+	for i, s := range signers {
+		cs[i] = clients.NewDisperserClient(cfg, s)
+	}
+	return newRotatingClient(cs)
+}
+
+type rotatingClient struct {
+	clients []clients.DisperserClient
+	next    atomic.Uint64
+}
+
+var _ clients.DisperserClient = (*rotatingClient)(nil)
+
+func newRotatingClient(cs []clients.DisperserClient) *rotatingClient {
+	return &rotatingClient{clients: cs}
+}
+
+func (r *rotatingClient) DisperseBlobAuthenticated(ctx context.Context, data []byte, customQuorums []uint8) (*disperser.BlobStatus, []byte, error) {
+	i := (r.next.Add(1) - 1) % uint64(len(r.clients))
+	return r.clients[i].DisperseBlobAuthenticated(ctx, data, customQuorums)
+}
+
+// the other requests aren't authenticated, so any client serves them
+
+func (r *rotatingClient) DisperseBlob(ctx context.Context, data []byte, customQuorums []uint8) (*disperser.BlobStatus, []byte, error) {
+	return r.clients[0].DisperseBlob(ctx, data, customQuorums)
+}
+
+func (r *rotatingClient) GetBlobStatus(ctx context.Context, key []byte) (*disperser_rpc.BlobStatusReply, error) {
+	return r.clients[0].GetBlobStatus(ctx, key)
+}
+
+func (r *rotatingClient) RetrieveBlob(ctx context.Context, batchHeaderHash []byte, blobIndex uint32) ([]byte, error) {
+	return r.clients[0].RetrieveBlob(ctx, batchHeaderHash, blobIndex)
+}
@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Digest ... hash of the disperser's challenge that blob requests are authenticated with
+func Digest(header core.BlobAuthHeader) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, header.Nonce)
+	return crypto.Keccak256(buf)
+}
+
+// signFunc returns the 65 byte [R || S || V] signature of the digest
+type signFunc func(ctx context.Context, digest []byte) ([]byte, error)
+
+// ecdsaSigner authenticates blob requests with a secp256k1 key that is held by a local or
+// remote backend. The account ID of the disperser is the uncompressed public key.
+type ecdsaSigner struct {
+	name    string
+	pub     *ecdsa.PublicKey
+	sign    signFunc
+	timeout time.Duration
+}
+
+var _ core.BlobRequestSigner = (*ecdsaSigner)(nil)
+
+func (s *ecdsaSigner) GetAccountID() (string, error) {
+	return hexutil.Encode(crypto.FromECDSAPub(s.pub)), nil
+}
+
+// SignBlobRequest signs the challenge of the header and checks that the signature recovers to the
+// account ID, so that a misconfigured backend fails here rather than at the disperser.
+func (s *ecdsaSigner) SignBlobRequest(header core.BlobAuthHeader) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	digest := Digest(header)
+	sig, err := s.sign(ctx, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%s signer: %w", s.name, err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%s signer returned a %d byte signature, expected %d", s.name, len(sig), crypto.SignatureLength)
+	}
+	// accept the 27/28 recovery IDs of the Ethereum signing convention
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.Ecrecover(digest, sig)
+	if err != nil {
+		return nil, fmt.Errorf("%s signer returned an invalid signature: %w", s.name, err)
+	}
+	if !bytes.Equal(pub, crypto.FromECDSAPub(s.pub)) {
+		return nil, fmt.Errorf("%s signer signed with a different key than its account ID", s.name)
+	}
+	return sig, nil
+}
+
+// NewLocal ... signer for a hex encoded private key
+func NewLocal(privateKeyHex string) (core.BlobRequestSigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer private key: %w", err)
+	}
+	return newKeySigner("local", key), nil
+}
+
+// NewKeystore ... signer for an encrypted JSON keystore file, decrypted with the password read
+// from passwordFile
+func NewKeystore(path, passwordFile string) (core.BlobRequestSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore password file: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore file %s: %w", path, err)
+	}
+	return newKeySigner("keystore", key.PrivateKey), nil
+}
+
+func newKeySigner(name string, key *ecdsa.PrivateKey) *ecdsaSigner {
+	return &ecdsaSigner{
+		name: name,
+		pub:  &key.PublicKey,
+		sign: func(_ context.Context, digest []byte) ([]byte, error) {
+			return crypto.Sign(digest, key)
+		},
+		timeout: time.Second,
+	}
+}
@@ -0,0 +1,183 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/api/clients"
+	disperser_rpc "github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/Layr-Labs/eigenda/core"
+	"github.com/Layr-Labs/eigenda/core/auth"
+	"github.com/Layr-Labs/eigenda/disperser"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// requireAuthenticates checks that the disperser accepts the signatures of the signer
+func requireAuthenticates(t *testing.T, s core.BlobRequestSigner, key *ecdsa.PrivateKey) {
+	accountID, err := s.GetAccountID()
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Encode(crypto.FromECDSAPub(&key.PublicKey)), accountID)
+
+	for nonce := uint32(0); nonce < 8; nonce++ {
+		header := core.BlobAuthHeader{Nonce: nonce}
+		header.AuthenticationData, err = s.SignBlobRequest(header)
+		require.NoError(t, err)
+
+		header.AccountID = accountID
+		require.NoError(t, auth.NewAuthenticator(auth.AuthConfig{}).AuthenticateBlobRequest(header))
+	}
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, "correct horse", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("correct horse\n"), 0o600))
+	s, err := NewKeystore(keyFile, passwordFile)
+	require.NoError(t, err)
+	requireAuthenticates(t, s, key)
+
+	require.NoError(t, os.WriteFile(passwordFile, []byte("wrong"), 0o600))
+	_, err = NewKeystore(keyFile, passwordFile)
+	require.Error(t, err)
+}
+
+func newRemoteSigner(t *testing.T, key *ecdsa.PrivateKey, signingKey *ecdsa.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/public-key", func(w http.ResponseWriter, _ *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(remotePublicKeyResponse{PublicKey: crypto.FromECDSAPub(&key.PublicKey)}))
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		var req remoteSignRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sig, err := crypto.Sign(req.Digest, signingKey)
+		require.NoError(t, err)
+		// use the Ethereum recovery IDs to check that they are accepted
+		sig[crypto.RecoveryIDOffset] += 27
+		require.NoError(t, json.NewEncoder(w).Encode(remoteSignResponse{Signature: sig}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	server := newRemoteSigner(t, key, key)
+
+	s, err := NewRemote(context.Background(), server.URL+"/", time.Second)
+	require.NoError(t, err)
+	requireAuthenticates(t, s, key)
+
+	t.Run("SignsWithAnotherKey", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		server := newRemoteSigner(t, key, other)
+
+		s, err := NewRemote(context.Background(), server.URL, time.Second)
+		require.NoError(t, err)
+		_, err = s.SignBlobRequest(core.BlobAuthHeader{Nonce: 1})
+		require.ErrorContains(t, err, "different key")
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		_, err := NewRemote(context.Background(), "http://127.0.0.1:1", time.Second)
+		require.Error(t, err)
+	})
+}
+
+// fakeKMS returns DER signatures with high S values, as KMSes don't normalize them
+type fakeKMS struct {
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func (k fakeKMS) PublicKey(_ context.Context, keyID string) (*ecdsa.PublicKey, error) {
+	return &k.keys[keyID].PublicKey, nil
+}
+
+func (k fakeKMS) SignDigest(_ context.Context, keyID string, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, k.keys[keyID])
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	return asn1.Marshal(struct{ R, S *big.Int }{r, new(big.Int).Sub(secp256k1N, s)})
+}
+
+func TestKMSSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	kms := fakeKMS{keys: map[string]*ecdsa.PrivateKey{"key-1": key}}
+
+	s, err := NewKMS(context.Background(), kms, "key-1", time.Second)
+	require.NoError(t, err)
+	requireAuthenticates(t, s, key)
+
+	RegisterKMS("fake", func(context.Context) (KMS, error) { return kms, nil })
+	require.Contains(t, KMSProviders(), "fake")
+
+	cfg := Config{KMSProvider: "fake", KMSKeyIDs: []string{"key-1"}, RemoteTimeout: time.Second}
+	require.NoError(t, cfg.Check())
+	signers, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, signers, 1)
+	requireAuthenticates(t, signers[0], key)
+}
+
+type accountClient struct {
+	clients.DisperserClient
+	account string
+	used    *[]string
+}
+
+func (c accountClient) DisperseBlobAuthenticated(context.Context, []byte, []uint8) (*disperser.BlobStatus, []byte, error) {
+	*c.used = append(*c.used, c.account)
+	return nil, nil, nil
+}
+
+func (c accountClient) GetBlobStatus(context.Context, []byte) (*disperser_rpc.BlobStatusReply, error) {
+	*c.used = append(*c.used, c.account)
+	return nil, nil
+}
+
+func TestRotatingClient(t *testing.T) {
+	var used []string
+	r := newRotatingClient([]clients.DisperserClient{
+		accountClient{account: "a", used: &used},
+		accountClient{account: "b", used: &used},
+		accountClient{account: "c", used: &used},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		_, _, err := r.DisperseBlobAuthenticated(ctx, nil, nil)
+		require.NoError(t, err)
+	}
+	_, err := r.GetBlobStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c", "a", "a"}, used)
+}
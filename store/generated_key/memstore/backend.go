@@ -0,0 +1,307 @@
+package memstore
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/go-redis/redis/v8"
+)
+
+type BackendType string
+
+const (
+	// MemoryBackend keeps entries in a process-local map. Certs can only be read back
+	// through the proxy instance that created them.
+	MemoryBackend BackendType = "memory"
+	// RedisBackend keeps entries in a shared redis instance so that several proxy replicas
+	// can serve each other's certs.
+	RedisBackend BackendType = "redis"
+	// FilesystemBackend keeps entries as files in a shared directory (e.g, a docker volume)
+	// so that several proxy replicas can serve each other's certs.
+	FilesystemBackend BackendType = "filesystem"
+
+	redisKeyPrefix = "memstore:"
+)
+
+var (
+	errKeyNotFound = fmt.Errorf("commitment key %w", store.ErrNotFound)
+	errKeyExpired  = fmt.Errorf("commitment key %w", store.ErrExpired)
+	errKeyExists   = errors.New("commitment key already exists")
+)
+
+func StringToBackendType(s string) (BackendType, error) {
+	switch strings.ToLower(s) {
+	case "", string(MemoryBackend):
+		return MemoryBackend, nil
+	case string(RedisBackend):
+		return RedisBackend, nil
+	case string(FilesystemBackend):
+		return FilesystemBackend, nil
+	default:
+		return "", fmt.Errorf("unknown memstore backend: %s", s)
+	}
+}
+
+// backend persists the memstore entries (i.e, encoded blob and mock cert) keyed by the
+// cert's inclusion proof.
+type backend interface {
+	// get returns the entry stored under key or errKeyNotFound if it doesn't exist. Backends that
+	// remember pruned keys return errKeyExpired for them instead.
+	get(ctx context.Context, key []byte) ([]byte, error)
+	// put stores the entry under key or returns errKeyExists if it already exists.
+	put(ctx context.Context, key []byte, value []byte) error
+	// prune removes entries older than the expiration. Backends that expire entries on
+	// their own can treat this as a no-op.
+	prune(ctx context.Context, expiration time.Duration) error
+	// len returns the number of stored entries.
+	len(ctx context.Context) (int, error)
+}
+
+func newBackend(cfg Config) (backend, error) {
+	kind, err := StringToBackendType(string(cfg.Backend))
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case RedisBackend:
+		return newRedisBackend(cfg)
+	case FilesystemBackend:
+		return newFSBackend(cfg.FSPath)
+	case MemoryBackend:
+		fallthrough
+	default:
+		return newMemoryBackend(), nil
+	}
+}
+
+// memoryBackend ... process-local map, not shared across replicas
+type memoryBackend struct {
+	sync.RWMutex
+
+	keyStarts map[string]time.Time
+	store     map[string][]byte
+	// keys of pruned entries, without their values
+	expired map[string]struct{}
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		keyStarts: make(map[string]time.Time),
+		store:     make(map[string][]byte),
+		expired:   make(map[string]struct{}),
+	}
+}
+
+func (m *memoryBackend) get(_ context.Context, key []byte) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	value, exists := m.store[string(key)]
+	if !exists {
+		if _, expired := m.expired[string(key)]; expired {
+			return nil, errKeyExpired
+		}
+		return nil, errKeyNotFound
+	}
+	return value, nil
+}
+
+func (m *memoryBackend) put(_ context.Context, key []byte, value []byte) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.store[string(key)]; exists {
+		return errKeyExists
+	}
+
+	m.store[string(key)] = value
+	m.keyStarts[string(key)] = time.Now()
+	return nil
+}
+
+func (m *memoryBackend) prune(_ context.Context, expiration time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for key, start := range m.keyStarts {
+		if time.Since(start) >= expiration {
+			delete(m.keyStarts, key)
+			delete(m.store, key)
+			m.expired[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (m *memoryBackend) len(_ context.Context) (int, error) {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.store), nil
+}
+
+// redisBackend ... shared redis instance, expiration is handled by redis key TTLs
+type redisBackend struct {
+	client     *redis.Client
+	expiration time.Duration
+}
+
+func newRedisBackend(cfg Config) (*redisBackend, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisEndpoint,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis server: %w", err)
+	}
+
+	return &redisBackend{
+		client:     client,
+		expiration: cfg.BlobExpiration,
+	}, nil
+}
+
+func (r *redisBackend) get(ctx context.Context, key []byte) ([]byte, error) {
+	value, err := r.client.Get(ctx, redisKeyPrefix+hex.EncodeToString(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, errKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %w", store.ErrBackendUnavailable, err)
+	}
+	return value, nil
+}
+
+func (r *redisBackend) put(ctx context.Context, key []byte, value []byte) error {
+	// a zero expiration means the key never expires
+	ok, err := r.client.SetNX(ctx, redisKeyPrefix+hex.EncodeToString(key), value, r.expiration).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errKeyExists
+	}
+	return nil
+}
+
+func (r *redisBackend) prune(_ context.Context, _ time.Duration) error {
+	return nil
+}
+
+func (r *redisBackend) len(ctx context.Context) (int, error) {
+	count := 0
+	iter := r.client.Scan(ctx, 0, redisKeyPrefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+	return count, iter.Err()
+}
+
+// fsBackend ... shared directory where every entry is stored as a file named by its hex encoded key
+type fsBackend struct {
+	dir string
+}
+
+func newFSBackend(dir string) (*fsBackend, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("filesystem memstore backend requires a directory path")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create memstore directory %s: %w", dir, err)
+	}
+
+	return &fsBackend{dir: dir}, nil
+}
+
+func (f *fsBackend) path(key []byte) string {
+	return filepath.Join(f.dir, hex.EncodeToString(key))
+}
+
+func (f *fsBackend) get(_ context.Context, key []byte) ([]byte, error) {
+	value, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// put writes the entry to a temporary file first and then hard links it into place, so that
+// other replicas never observe a partially written entry and concurrent writers can't clobber
+// an existing one.
+func (f *fsBackend) put(_ context.Context, key []byte, value []byte) error {
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	err = os.Link(tmp.Name(), f.path(key))
+	if errors.Is(err, os.ErrExist) {
+		return errKeyExists
+	}
+	return err
+}
+
+func (f *fsBackend) prune(_ context.Context, expiration time.Duration) error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// entry was likely pruned by another replica
+			continue
+		}
+
+		if time.Since(info.ModTime()) >= expiration {
+			if err := os.Remove(filepath.Join(f.dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fsBackend) len(_ context.Context) (int, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -14,6 +14,16 @@ var (
 	ExpirationFlagName = withFlagPrefix("expiration")
 	PutLatencyFlagName = withFlagPrefix("put-latency")
 	GetLatencyFlagName = withFlagPrefix("get-latency")
+
+	// shared backend flags
+	BackendFlagName       = withFlagPrefix("backend")
+	RedisEndpointFlagName = withFlagPrefix("redis-endpoint")
+	RedisPasswordFlagName = withFlagPrefix("redis-password")
+	RedisDBFlagName       = withFlagPrefix("redis-db")
+	FSPathFlagName        = withFlagPrefix("fs-path")
+
+	// simulated ethereum flags (only used when cert verification is enabled)
+	SimulatedBlockTimeFlagName = withFlagPrefix("simulated-block-time")
 )
 
 func withFlagPrefix(s string) string {
@@ -79,6 +89,49 @@ func CLIFlags(envPrefix, category string) []cli.Flag {
 			EnvVars:  []string{withEnvPrefix(envPrefix, "GET_LATENCY")},
 			Category: category,
 		},
+		&cli.StringFlag{
+			Name:     BackendFlagName,
+			Usage:    "Where memstore blobs and certs are kept, options are [memory, redis, filesystem]. Use redis or filesystem to share a memstore between multiple proxy replicas.",
+			Value:    string(MemoryBackend),
+			EnvVars:  []string{withEnvPrefix(envPrefix, "BACKEND")},
+			Category: category,
+			Action: func(_ *cli.Context, s string) error {
+				_, err := StringToBackendType(s)
+				return err
+			},
+		},
+		&cli.StringFlag{
+			Name:     RedisEndpointFlagName,
+			Usage:    "Redis endpoint used when memstore backend is redis.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "REDIS_ENDPOINT")},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     RedisPasswordFlagName,
+			Usage:    "Redis password used when memstore backend is redis.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "REDIS_PASSWORD")},
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     RedisDBFlagName,
+			Usage:    "Redis database used when memstore backend is redis.",
+			Value:    0,
+			EnvVars:  []string{withEnvPrefix(envPrefix, "REDIS_DB")},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     FSPathFlagName,
+			Usage:    "Directory used when memstore backend is filesystem. Should be a volume shared by all proxy replicas.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "FS_PATH")},
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     SimulatedBlockTimeFlagName,
+			Usage:    "Block time of the simulated Ethereum chain that memstore certs are confirmed on when cert verification is enabled.",
+			Value:    12 * time.Second,
+			EnvVars:  []string{withEnvPrefix(envPrefix, "SIMULATED_BLOCK_TIME")},
+			Category: category,
+		},
 	}
 }
 
@@ -92,5 +145,12 @@ func ReadConfig(ctx *cli.Context) Config {
 		BlobExpiration:   ctx.Duration(ExpirationFlagName),
 		PutLatency:       ctx.Duration(PutLatencyFlagName),
 		GetLatency:       ctx.Duration(GetLatencyFlagName),
+		Backend:          BackendType(ctx.String(BackendFlagName)),
+		RedisEndpoint:    ctx.String(RedisEndpointFlagName),
+		RedisPassword:    ctx.String(RedisPasswordFlagName),
+		RedisDB:          ctx.Int(RedisDBFlagName),
+		FSPath:           ctx.String(FSPathFlagName),
+
+		SimulatedBlockTime: ctx.Duration(SimulatedBlockTimeFlagName),
 	}
 }
@@ -1,11 +1,13 @@
 package memstore
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -16,6 +18,8 @@ import (
 	"github.com/Layr-Labs/eigenda/api/clients/codecs"
 	"github.com/Layr-Labs/eigenda/api/grpc/common"
 	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -29,39 +33,85 @@ type Config struct {
 	// artificial latency added for memstore backend to mimic eigenda's latency
 	PutLatency time.Duration
 	GetLatency time.Duration
+
+	// backend used to persist encoded blobs and certs, shared backends allow several
+	// proxy replicas to serve each other's certs
+	Backend       BackendType
+	RedisEndpoint string
+	RedisPassword string
+	RedisDB       int
+	FSPath        string
+
+	// block time of the simulated Ethereum chain that certs are confirmed on when
+	// cert verification is enabled
+	SimulatedBlockTime time.Duration
+}
+
+// entry is the value persisted in the backend for every blob written to the memstore
+type entry struct {
+	Cert        []byte
+	EncodedBlob []byte
 }
 
 /*
 MemStore is a simple in-memory store for blobs which uses an expiration
 time to evict blobs to best emulate the ephemeral nature of blobs dispersed to
-EigenDA operators.
+EigenDA operators. Entries can optionally be kept in a shared redis or filesystem
+backend so that multiple proxy replicas can serve each other's certs.
 */
 type MemStore struct {
 	sync.RWMutex
 
-	config    Config
-	l         log.Logger
-	keyStarts map[string]time.Time
-	store     map[string][]byte
-	verifier  *verify.Verifier
-	codec     codecs.BlobCodec
+	config   Config
+	l        log.Logger
+	db       backend
+	verifier *verify.Verifier
+	codec    codecs.BlobCodec
+
+	// registrar is optional and confirms generated certs on a (simulated) Ethereum chain
+	registrar BatchRegistrar
+	// batchID is the last batch id assigned to a generated cert
+	batchID atomic.Uint32
 
 	reads int
 }
 
+// BatchRegistrar ... records the batch metadata hash of generated certs, as the EigenDAServiceManager
+// does when a batch is confirmed. verify/simulated.Backend is the canonical implementation.
+type BatchRegistrar interface {
+	// ConfirmBatch records the batch metadata hash built for the confirmation block, and returns
+	// the number of the block it was recorded in
+	ConfirmBatch(ctx context.Context, batchID uint32, metadataHash func(confirmationBlock uint64) ([32]byte, error)) (uint64, error)
+}
+
 var _ store.GeneratedKeyStore = (*MemStore)(nil)
+var _ store.CertReporter = (*MemStore)(nil)
+var _ store.CertVerifier = (*MemStore)(nil)
 
 // New ... constructor
 func New(
 	ctx context.Context, verifier *verify.Verifier, l log.Logger, config Config,
 ) (*MemStore, error) {
+	return NewWithRegistrar(ctx, verifier, nil, l, config)
+}
+
+// NewWithRegistrar ... constructor for a memstore whose certs are registered with a batch registrar,
+// allowing them to be fully verified by a verifier that reads from the registrar's chain
+func NewWithRegistrar(
+	ctx context.Context, verifier *verify.Verifier, registrar BatchRegistrar, l log.Logger, config Config,
+) (*MemStore, error) {
+	db, err := newBackend(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memstore backend: %w", err)
+	}
+
 	store := &MemStore{
 		l:         l,
 		config:    config,
-		keyStarts: make(map[string]time.Time),
-		store:     make(map[string][]byte),
+		db:        db,
 		verifier:  verifier,
 		codec:     codecs.NewIFFTCodec(codecs.NewDefaultBlobCodec()),
+		registrar: registrar,
 	}
 
 	if store.config.BlobExpiration != 0 {
@@ -83,64 +133,58 @@ func (e *MemStore) pruningLoop(ctx context.Context) {
 
 		case <-timer.C:
 			e.l.Debug("pruning expired blobs")
-			e.pruneExpired()
-		}
-	}
-}
-
-// pruneExpired ... removes expired blobs from the store based on the expiration time.
-func (e *MemStore) pruneExpired() {
-	e.Lock()
-	defer e.Unlock()
-
-	for commit, dur := range e.keyStarts {
-		if time.Since(dur) >= e.config.BlobExpiration {
-			delete(e.keyStarts, commit)
-			delete(e.store, commit)
-
-			e.l.Info("blob pruned", "commit", commit)
+			if err := e.db.prune(ctx, e.config.BlobExpiration); err != nil {
+				e.l.Warn("failed to prune expired blobs", "err", err)
+			}
 		}
 	}
 }
 
 // Get fetches a value from the store.
-func (e *MemStore) Get(_ context.Context, commit []byte) ([]byte, error) {
+func (e *MemStore) Get(ctx context.Context, commit []byte) ([]byte, error) {
 	time.Sleep(e.config.GetLatency)
+	e.Lock()
 	e.reads++
-	e.RLock()
-	defer e.RUnlock()
+	e.Unlock()
 
 	var cert verify.Certificate
 	err := rlp.DecodeBytes(commit, &cert)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode DA cert to RLP format: %w", err)
+		return nil, fmt.Errorf("%w: failed to decode DA cert to RLP format: %w", verify.ErrInvalidCert, err)
 	}
 
-	var encodedBlob []byte
-	var exists bool
-	if encodedBlob, exists = e.store[string(cert.BlobVerificationProof.InclusionProof)]; !exists {
-		return nil, fmt.Errorf("commitment key not found")
+	value, err := e.db.get(ctx, cert.BlobVerificationProof.InclusionProof)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored entry
+	err = rlp.DecodeBytes(value, &stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode memstore entry: %w", err)
+	}
+
+	if !bytes.Equal(stored.Cert, commit) {
+		return nil, fmt.Errorf("%w: DA cert does not match the one stored for its commitment key", verify.ErrInvalidCert)
 	}
 
 	// Don't need to do this really since it's a mock store
-	err = e.verifier.VerifyCommitment(cert.BlobHeader.Commitment, encodedBlob)
+	err = e.verifier.VerifyCommitment(cert.BlobHeader.Commitment, stored.EncodedBlob)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.codec.DecodeBlob(encodedBlob)
+	return e.codec.DecodeBlob(stored.EncodedBlob)
 }
 
 // Put inserts a value into the store.
-func (e *MemStore) Put(_ context.Context, value []byte) ([]byte, error) {
+func (e *MemStore) Put(ctx context.Context, value []byte) ([]byte, error) {
+	store.ReportPutStatus(ctx, store.PutStatusDispersing)
 	time.Sleep(e.config.PutLatency)
 	if uint64(len(value)) > e.config.MaxBlobSizeBytes {
 		return nil, fmt.Errorf("%w: blob length %d, max blob size %d", store.ErrProxyOversizedBlob, len(value), e.config.MaxBlobSizeBytes)
 	}
 
-	e.Lock()
-	defer e.Unlock()
-
 	encodedVal, err := e.codec.EncodeBlob(value)
 	if err != nil {
 		return nil, err
@@ -151,83 +195,178 @@ func (e *MemStore) Put(_ context.Context, value []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	// generate batch header hash
-	entropy := make([]byte, 10)
-	_, err = rand.Read(entropy)
+	cert, err := e.generateCert(ctx, commitment, uint32(len(encodedVal))) // #nosec G115
+	if err != nil {
+		return nil, err
+	}
+
+	certBytes, err := rlp.EncodeToBytes(cert)
+	if err != nil {
+		return nil, err
+	}
+	entryBytes, err := rlp.EncodeToBytes(&entry{
+		Cert:        certBytes,
+		EncodedBlob: encodedVal,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// construct key
+	err = e.db.put(ctx, cert.BlobVerificationProof.InclusionProof, entryBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cert is only verifiable once its batch has been registered
+	if e.registrar != nil {
+		store.ReportPutStatus(ctx, store.PutStatusConfirmed)
+	} else {
+		store.ReportPutStatus(ctx, store.PutStatusVerified)
+	}
+
+	return certBytes, nil
+}
+
+// generateCert ... creates a mock DA cert for the blob commitment. The cert is internally consistent
+// (i.e, the inclusion proof, batch root and security parameters check out) so that it passes
+// cert verification once its batch metadata hash is registered with the batch registrar.
+func (e *MemStore) generateCert(ctx context.Context, commitment *bn254.G1Affine, dataLength uint32) (*verify.Certificate, error) {
+	// the random sibling leaf doubles as the cert's unique storage key
+	entropy := make([]byte, 32)
+	_, err := rand.Read(entropy)
 	if err != nil {
 		return nil, err
 	}
-	mockBatchRoot := crypto.Keccak256Hash(entropy)
-	blockNum, _ := rand.Int(rand.Reader, big.NewInt(1000))
 
+	// certs registered with the batch registrar get the block their batch is confirmed in below
+	blockNum, _ := rand.Int(rand.Reader, big.NewInt(1000))
 	num := uint32(blockNum.Uint64()) // #nosec G115
 
-	cert := &verify.Certificate{
-		BlobHeader: &disperser.BlobHeader{
-			Commitment: &common.G1Commitment{
-				X: commitment.X.Marshal(),
-				Y: commitment.Y.Marshal(),
+	blobHeader := &disperser.BlobHeader{
+		Commitment: &common.G1Commitment{
+			X: commitment.X.Marshal(),
+			Y: commitment.Y.Marshal(),
+		},
+		DataLength: dataLength,
+		BlobQuorumParams: []*disperser.BlobQuorumParam{
+			{
+				QuorumNumber:                    0,
+				AdversaryThresholdPercentage:    33,
+				ConfirmationThresholdPercentage: 55,
+				ChunkLength:                     300,
 			},
-			DataLength: uint32(len(encodedVal)), // #nosec G115
-			BlobQuorumParams: []*disperser.BlobQuorumParam{
-				{
-					QuorumNumber:                    1,
-					AdversaryThresholdPercentage:    29,
-					ConfirmationThresholdPercentage: 30,
-					ChunkLength:                     300,
-				},
+			{
+				QuorumNumber:                    1,
+				AdversaryThresholdPercentage:    33,
+				ConfirmationThresholdPercentage: 55,
+				ChunkLength:                     300,
 			},
 		},
+	}
+
+	cert := &verify.Certificate{
+		BlobHeader: blobHeader,
 		BlobVerificationProof: &disperser.BlobVerificationProof{
 			BatchMetadata: &disperser.BatchMetadata{
 				BatchHeader: &disperser.BatchHeader{
-					BatchRoot:               mockBatchRoot[:],
-					QuorumNumbers:           []byte{0x1, 0x0},
-					QuorumSignedPercentages: []byte{0x60, 0x90},
+					QuorumNumbers:           []byte{0x0, 0x1},
+					QuorumSignedPercentages: []byte{0x60, 0x60},
 					ReferenceBlockNumber:    num,
 				},
-				SignatoryRecordHash:     mockBatchRoot[:],
 				Fee:                     []byte{},
 				ConfirmationBlockNumber: num,
 				BatchHeaderHash:         []byte{},
 			},
-			BatchId:        69,
-			BlobIndex:      420,
+			BatchId:        e.batchID.Add(1),
+			BlobIndex:      0,
 			InclusionProof: entropy,
-			QuorumIndexes:  []byte{0x1, 0x0},
+			QuorumIndexes:  []byte{0x0, 0x1},
 		},
 	}
 
-	certBytes, err := rlp.EncodeToBytes(cert)
+	// the batch is a two leaf merkle tree of the blob header and the random sibling
+	leaf, err := verify.HashEncodeBlobHeader(cert.ReadBlobHeader())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to hash blob header: %w", err)
+	}
+	batchRoot := crypto.Keccak256(leaf.Bytes(), entropy)
+	signatoryRecordHash := crypto.Keccak256(batchRoot, entropy)
+
+	batchMetadata := cert.BlobVerificationProof.BatchMetadata
+	batchMetadata.BatchHeader.BatchRoot = batchRoot
+	batchMetadata.SignatoryRecordHash = signatoryRecordHash
+
+	if e.registrar != nil {
+		// the batch is referenced and confirmed in the block the registrar records it in, which
+		// the batch metadata hash commits to
+		confirmationBlock, err := e.registrar.ConfirmBatch(ctx, cert.BlobVerificationProof.BatchId,
+			func(confirmationBlock uint64) ([32]byte, error) {
+				num := uint32(confirmationBlock) // #nosec G115
+				header := binding.IEigenDAServiceManagerBatchHeader{
+					BlobHeadersRoot:       [32]byte(batchRoot),
+					QuorumNumbers:         batchMetadata.BatchHeader.QuorumNumbers,
+					SignedStakeForQuorums: batchMetadata.BatchHeader.QuorumSignedPercentages,
+					ReferenceBlockNumber:  num,
+				}
+				hash, err := verify.HashBatchMetadata(&header, [32]byte(signatoryRecordHash), num)
+				if err != nil {
+					return [32]byte{}, fmt.Errorf("failed to hash batch metadata: %w", err)
+				}
+				return hash, nil
+			})
+		if err != nil {
+			return nil, fmt.Errorf("failed to register batch metadata hash: %w", err)
+		}
+		num = uint32(confirmationBlock) // #nosec G115
+		batchMetadata.BatchHeader.ReferenceBlockNumber = num
+		batchMetadata.ConfirmationBlockNumber = num
 	}
-	// construct key
-	bytesKeys := cert.BlobVerificationProof.InclusionProof
 
-	certStr := string(bytesKeys)
+	return cert, nil
+}
 
-	if _, exists := e.store[certStr]; exists {
-		return nil, fmt.Errorf("commitment key already exists")
-	}
+// Verify ... checks the DA cert against the batch registrar's chain when cert verification is enabled.
+// The blob commitment is already checked on Get.
+func (e *MemStore) Verify(key []byte, _ []byte) error {
+	return e.VerifyCert(key)
+}
 
-	e.store[certStr] = encodedVal
-	// add expiration
-	e.keyStarts[certStr] = time.Now()
+// VerifyCert ... checks the DA cert against the batch registrar's chain when cert verification is enabled
+func (e *MemStore) VerifyCert(key []byte) error {
+	var cert verify.Certificate
+	err := rlp.DecodeBytes(key, &cert)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode DA cert to RLP format: %w", verify.ErrInvalidCert, err)
+	}
 
-	return certBytes, nil
+	return e.verifier.VerifyCert(&cert)
 }
 
-func (e *MemStore) Verify(_, _ []byte) error {
-	return nil
+// ReportCert ... runs every verification step of the cert, and of the payload of its blob unless it's nil
+func (e *MemStore) ReportCert(ctx context.Context, cert *verify.Certificate, value []byte) (*verify.Report, error) {
+	var encodedBlob []byte
+	if value != nil {
+		var err error
+		encodedBlob, err = e.codec.EncodeBlob(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.verifier.ReportCert(ctx, cert, encodedBlob), nil
 }
 
 // Stats ... returns the current usage metrics of the in-memory key-value data store.
 func (e *MemStore) Stats() *store.Stats {
+	entries, err := e.db.len(context.Background())
+	if err != nil {
+		e.l.Warn("failed to count memstore entries", "err", err)
+	}
+
 	e.RLock()
 	defer e.RUnlock()
 	return &store.Stats{
-		Entries: len(e.store),
+		Entries: entries,
 		Reads:   e.reads,
 	}
 }
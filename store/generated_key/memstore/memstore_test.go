@@ -3,12 +3,17 @@ package memstore
 import (
 	"context"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/store"
 	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda-proxy/verify/simulated"
 	"github.com/Layr-Labs/eigenda/encoding/kzg"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,7 +48,7 @@ func TestGetSet(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil)
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
 	require.NoError(t, err)
 
 	ms, err := New(
@@ -70,7 +75,7 @@ func TestExpiration(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil)
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
 	require.NoError(t, err)
 
 	memstoreConfig := getDefaultMemStoreTestConfig()
@@ -92,8 +97,16 @@ func TestExpiration(t *testing.T) {
 	time.Sleep(time.Second * 1)
 
 	_, err = ms.Get(ctx, key)
-	require.Error(t, err)
+	require.ErrorIs(t, err, store.ErrExpired)
 
+	// keys that were never stored aren't reported as expired
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(key, &cert))
+	cert.BlobVerificationProof.InclusionProof = make([]byte, 32)
+	unknown, err := rlp.EncodeToBytes(&cert)
+	require.NoError(t, err)
+	_, err = ms.Get(ctx, unknown)
+	require.ErrorIs(t, err, store.ErrNotFound)
 }
 
 func TestLatency(t *testing.T) {
@@ -105,7 +118,7 @@ func TestLatency(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil)
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
 	require.NoError(t, err)
 
 	config := getDefaultMemStoreTestConfig()
@@ -127,3 +140,140 @@ func TestLatency(t *testing.T) {
 	require.GreaterOrEqual(t, time.Since(timeBeforeGet), getLatency)
 
 }
+
+func TestSharedFilesystemBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	verifier, err := verify.NewVerifier(getDefaultVerifierTestConfig(), nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	config := getDefaultMemStoreTestConfig()
+	config.Backend = FilesystemBackend
+	config.FSPath = t.TempDir()
+
+	// two replicas sharing the same directory
+	replicaA, err := New(ctx, verifier, log.New(), config)
+	require.NoError(t, err)
+	replicaB, err := New(ctx, verifier, log.New(), config)
+	require.NoError(t, err)
+
+	expected := []byte(testPreimage)
+	key, err := replicaA.Put(ctx, expected)
+	require.NoError(t, err)
+
+	actual, err := replicaB.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	require.Equal(t, 1, replicaA.Stats().Entries)
+	require.Equal(t, 1, replicaB.Stats().Entries)
+}
+
+func TestCertVerificationWithSimulatedChain(t *testing.T) {
+	t.Parallel()
+
+	const confirmationDepth = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sim, err := simulated.NewBackend(log.New())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sim.Close())
+	}()
+
+	verifierCfg := getDefaultVerifierTestConfig()
+	verifierCfg.VerifyCerts = true
+	verifierCfg.SvcManagerAddr = simulated.ServiceManagerAddr.Hex()
+	verifierCfg.ReadConfirmationDepth = confirmationDepth
+	verifier, err := verify.NewVerifierWithClient(verifierCfg, sim.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	ms, err := NewWithRegistrar(ctx, verifier, sim, log.New(), getDefaultMemStoreTestConfig())
+	require.NoError(t, err)
+
+	expected := []byte(testPreimage)
+	key, err := ms.Put(ctx, expected)
+	require.NoError(t, err)
+
+	actual, err := ms.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// the batch isn't confirmation depth deep yet
+	err = ms.Verify(key, actual)
+	require.ErrorIs(t, err, verify.ErrBatchMetadataHashNotFound)
+	require.ErrorIs(t, err, verify.ErrInsufficientDepth)
+
+	for i := 0; i < confirmationDepth; i++ {
+		sim.Commit()
+	}
+	require.NoError(t, ms.Verify(key, actual))
+
+	// a cert whose fields were tampered with no longer verifies
+	var cert verify.Certificate
+	require.NoError(t, rlp.DecodeBytes(key, &cert))
+	cert.BlobVerificationProof.BatchMetadata.BatchHeader.QuorumSignedPercentages = []byte{0x60, 0x10}
+	tampered, err := rlp.EncodeToBytes(&cert)
+	require.NoError(t, err)
+	require.Error(t, ms.Verify(tampered, actual))
+
+	// as does a cert whose blob isn't part of the confirmed batch
+	require.NoError(t, rlp.DecodeBytes(key, &cert))
+	cert.BlobHeader.DataLength++
+	tampered, err = rlp.EncodeToBytes(&cert)
+	require.NoError(t, err)
+	require.Error(t, ms.Verify(tampered, actual))
+}
+
+func TestConcurrentPutsAreVerifiableAtTheirConfirmationBlock(t *testing.T) {
+	t.Parallel()
+
+	const confirmationDepth = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sim, err := simulated.NewBackend(log.New())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sim.Close())
+	}()
+	// blocks mined in between puts must not move the batches out of their certs' confirmation blocks
+	sim.Start(time.Millisecond)
+
+	verifierCfg := getDefaultVerifierTestConfig()
+	verifierCfg.VerifyCerts = true
+	verifierCfg.VerifyAtConfirmationBlock = true
+	verifierCfg.SvcManagerAddr = simulated.ServiceManagerAddr.Hex()
+	verifierCfg.ReadConfirmationDepth = confirmationDepth
+	verifier, err := verify.NewVerifierWithClient(verifierCfg, sim.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	ms, err := NewWithRegistrar(ctx, verifier, sim, log.New(), getDefaultMemStoreTestConfig())
+	require.NoError(t, err)
+
+	keys := make([][]byte, 8)
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key, err := ms.Put(ctx, []byte(testPreimage))
+			require.NoError(t, err)
+			keys[i] = key
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < confirmationDepth; i++ {
+		sim.Commit()
+	}
+	for _, key := range keys {
+		require.NoError(t, ms.Verify(key, []byte(testPreimage)))
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
-	"errors"
+	"fmt"
 	"io"
 	"net/url"
 	"path"
 	"time"
 
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/minio/minio-go/v7"
 
@@ -92,7 +93,7 @@ func (s *Store) Get(ctx context.Context, key []byte) ([]byte, error) {
 	if err != nil {
 		errResponse := minio.ToErrorResponse(err)
 		if errResponse.Code == "NoSuchKey" {
-			return nil, errors.New("value not found in s3 bucket")
+			return nil, fmt.Errorf("value %w in s3 bucket", store.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -125,7 +126,7 @@ func (s *Store) Put(ctx context.Context, key []byte, value []byte) error {
 func (s *Store) Verify(key []byte, value []byte) error {
 	h := crypto.Keccak256Hash(value)
 	if !bytes.Equal(h[:], key) {
-		return errors.New("key does not match value")
+		return fmt.Errorf("%w: key does not match value", verify.ErrCommitmentMismatch)
 	}
 
 	return nil
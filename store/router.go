@@ -9,13 +9,16 @@ import (
 	"sync"
 
 	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 type IRouter interface {
-	Get(ctx context.Context, key []byte, cm commitments.CommitmentMode) ([]byte, error)
-	Put(ctx context.Context, cm commitments.CommitmentMode, key, value []byte) ([]byte, error)
+	Get(ctx context.Context, key []byte, meta commitments.CommitmentMeta) ([]byte, error)
+	Put(ctx context.Context, meta commitments.CommitmentMeta, key, value []byte) ([]byte, error)
+	PutCertVersion() commitments.CertEncodingCommitment
+	CertHandler(version commitments.CertEncodingCommitment) (CertHandler, error)
 
 	GetEigenDAStore() GeneratedKeyStore
 	GetS3Store() PrecomputedKeyStore
@@ -25,9 +28,9 @@ type IRouter interface {
 
 // Router ... storage backend routing layer
 type Router struct {
-	log     log.Logger
-	eigenda GeneratedKeyStore
-	s3      PrecomputedKeyStore
+	log   log.Logger
+	certs *CertRegistry
+	s3    PrecomputedKeyStore
 
 	caches    []PrecomputedKeyStore
 	cacheLock sync.RWMutex
@@ -36,11 +39,24 @@ type Router struct {
 	fallbackLock sync.RWMutex
 }
 
+// NewRouter ... routes V0 certs to the EigenDA backend, which may be nil if it's disabled
 func NewRouter(eigenda GeneratedKeyStore, s3 PrecomputedKeyStore, l log.Logger,
+	caches []PrecomputedKeyStore, fallbacks []PrecomputedKeyStore) (IRouter, error) {
+	certs := NewCertRegistry()
+	if eigenda != nil {
+		if err := certs.Register(commitments.CertV0, CertHandler{Store: eigenda}); err != nil {
+			return nil, err
+		}
+	}
+	return NewRouterWithCerts(certs, s3, l, caches, fallbacks)
+}
+
+// NewRouterWithCerts ... routes the certs of each registered cert version to its handler
+func NewRouterWithCerts(certs *CertRegistry, s3 PrecomputedKeyStore, l log.Logger,
 	caches []PrecomputedKeyStore, fallbacks []PrecomputedKeyStore) (IRouter, error) {
 	return &Router{
 		log:          l,
-		eigenda:      eigenda,
+		certs:        certs,
 		s3:           s3,
 		caches:       caches,
 		cacheLock:    sync.RWMutex{},
@@ -49,9 +65,10 @@ func NewRouter(eigenda GeneratedKeyStore, s3 PrecomputedKeyStore, l log.Logger,
 	}, nil
 }
 
-// Get ... fetches a value from a storage backend based on the (commitment mode, type)
-func (r *Router) Get(ctx context.Context, key []byte, cm commitments.CommitmentMode) ([]byte, error) {
-	switch cm {
+// Get ... fetches a value from a storage backend based on the (commitment mode, type). Certs are read
+// from the backend of their cert version.
+func (r *Router) Get(ctx context.Context, key []byte, meta commitments.CommitmentMeta) ([]byte, error) {
+	switch meta.Mode {
 	case commitments.OptimismKeccak:
 
 		if r.s3 == nil {
@@ -70,15 +87,25 @@ func (r *Router) Get(ctx context.Context, key []byte, cm commitments.CommitmentM
 		}
 		return value, nil
 
-	case commitments.SimpleCommitmentMode, commitments.OptimismGeneric:
-		if r.eigenda == nil {
+	case commitments.SimpleCommitmentMode, commitments.OptimismGeneric, commitments.ArbitrumNitro:
+		if len(r.certs.Versions()) == 0 {
 			return nil, errors.New("expected EigenDA backend for DA commitment type, but none configured")
 		}
 
+		handler, err := r.certs.Handler(commitments.CertEncodingCommitment(meta.CertVersion))
+		if err != nil {
+			return nil, err
+		}
+		if handler.Decode != nil {
+			if err := handler.Decode(key); err != nil {
+				return nil, fmt.Errorf("%w: failed to decode cert of version %d: %w", verify.ErrInvalidCert, meta.CertVersion, err)
+			}
+		}
+
 		// 1 - read blob from cache if enabled
 		if r.cacheEnabled() {
 			r.log.Debug("Retrieving data from cached backends")
-			data, err := r.multiSourceRead(ctx, key, false)
+			data, err := r.multiSourceRead(ctx, key, handler.Store, false)
 			if err == nil {
 				return data, nil
 			}
@@ -87,10 +114,10 @@ func (r *Router) Get(ctx context.Context, key []byte, cm commitments.CommitmentM
 		}
 
 		// 2 - read blob from EigenDA
-		data, err := r.eigenda.Get(ctx, key)
+		data, err := handler.Store.Get(ctx, key)
 		if err == nil {
 			// verify
-			err = r.eigenda.Verify(key, data)
+			err = handler.Store.Verify(key, data)
 			if err != nil {
 				return nil, err
 			}
@@ -99,32 +126,35 @@ func (r *Router) Get(ctx context.Context, key []byte, cm commitments.CommitmentM
 
 		// 3 - read blob from fallbacks if enabled and data is non-retrievable from EigenDA
 		if r.fallbackEnabled() {
-			data, err = r.multiSourceRead(ctx, key, true)
-			if err != nil {
-				r.log.Error("Failed to read from fallback targets", "err", err)
-				return nil, err
+			var fallbackErr error
+			data, fallbackErr = r.multiSourceRead(ctx, key, handler.Store, true)
+			if fallbackErr != nil {
+				r.log.Error("Failed to read from fallback targets", "err", fallbackErr)
+				// the EigenDA error is kept, since it tells why the blob couldn't be served
+				return nil, fmt.Errorf("%w (fallback read failed: %w)", err, fallbackErr)
 			}
 		} else {
 			return nil, err
 		}
 
-		return data, err
+		return data, nil
 
 	default:
 		return nil, errors.New("could not determine which storage backend to route to based on unknown commitment mode")
 	}
 }
 
-// Put ... inserts a value into a storage backend based on the commitment mode
-func (r *Router) Put(ctx context.Context, cm commitments.CommitmentMode, key, value []byte) ([]byte, error) {
+// Put ... inserts a value into a storage backend based on the commitment mode. Certs are dispersed by
+// the backend of the cert version of the commitment meta.
+func (r *Router) Put(ctx context.Context, meta commitments.CommitmentMeta, key, value []byte) ([]byte, error) {
 	var commit []byte
 	var err error
 
-	switch cm {
+	switch meta.Mode {
 	case commitments.OptimismKeccak: // caching and fallbacks are unsupported for this commitment mode
 		return r.putWithKey(ctx, key, value)
-	case commitments.OptimismGeneric, commitments.SimpleCommitmentMode:
-		commit, err = r.putWithoutKey(ctx, value)
+	case commitments.OptimismGeneric, commitments.SimpleCommitmentMode, commitments.ArbitrumNitro:
+		commit, err = r.putWithoutKey(ctx, commitments.CertEncodingCommitment(meta.CertVersion), value)
 	default:
 		return nil, fmt.Errorf("unknown commitment mode")
 	}
@@ -180,7 +210,7 @@ func (r *Router) handleRedundantWrites(ctx context.Context, commitment []byte, v
 }
 
 // multiSourceRead ... reads from a set of backends and returns the first successfully read blob
-func (r *Router) multiSourceRead(ctx context.Context, commitment []byte, fallback bool) ([]byte, error) {
+func (r *Router) multiSourceRead(ctx context.Context, commitment []byte, certStore GeneratedKeyStore, fallback bool) ([]byte, error) {
 	var sources []PrecomputedKeyStore
 	if fallback {
 		r.fallbackLock.RLock()
@@ -208,7 +238,7 @@ func (r *Router) multiSourceRead(ctx context.Context, commitment []byte, fallbac
 		}
 
 		// verify cert:data using EigenDA verification checks
-		err = r.eigenda.Verify(commitment, data)
+		err = certStore.Verify(commitment, data)
 		if err != nil {
 			log.Warn("Failed to verify blob", "err", err, "backend", src.BackendType())
 			continue
@@ -216,17 +246,21 @@ func (r *Router) multiSourceRead(ctx context.Context, commitment []byte, fallbac
 
 		return data, nil
 	}
-	return nil, errors.New("no data found in any redundant backend")
+	return nil, fmt.Errorf("no data found in any redundant backend: %w", ErrNotFound)
 }
 
 // putWithoutKey ... inserts a value into a storage backend that computes the key on-demand (i.e, EigenDA)
-func (r *Router) putWithoutKey(ctx context.Context, value []byte) ([]byte, error) {
-	if r.eigenda != nil {
-		r.log.Debug("Storing data to EigenDA backend")
-		return r.eigenda.Put(ctx, value)
+func (r *Router) putWithoutKey(ctx context.Context, version commitments.CertEncodingCommitment, value []byte) ([]byte, error) {
+	if len(r.certs.Versions()) == 0 {
+		return nil, errors.New("no DA storage backend found")
 	}
 
-	return nil, errors.New("no DA storage backend found")
+	handler, err := r.certs.Handler(version)
+	if err != nil {
+		return nil, err
+	}
+	r.log.Debug("Storing data to EigenDA backend", "cert_version", version)
+	return handler.Store.Put(ctx, value)
 }
 
 // putWithKey ... only supported for S3 storage backends using OP's alt-da keccak256 commitment type
@@ -251,9 +285,23 @@ func (r *Router) cacheEnabled() bool {
 	return len(r.caches) > 0
 }
 
-// GetEigenDAStore ...
+// PutCertVersion ... cert version of the commitments returned for puts
+func (r *Router) PutCertVersion() commitments.CertEncodingCommitment {
+	return r.certs.PutVersion()
+}
+
+// CertHandler ... handler of a registered cert version
+func (r *Router) CertHandler(version commitments.CertEncodingCommitment) (CertHandler, error) {
+	return r.certs.Handler(version)
+}
+
+// GetEigenDAStore ... backend of the cert version emitted by puts
 func (r *Router) GetEigenDAStore() GeneratedKeyStore {
-	return r.eigenda
+	handler, err := r.certs.Handler(r.certs.PutVersion())
+	if err != nil {
+		return nil
+	}
+	return handler.Store
 }
 
 // GetS3Store ...
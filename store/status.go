@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+)
+
+// PutStatus ... progress of a blob write through a GeneratedKeyStore
+type PutStatus string
+
+const (
+	// PutStatusDispersing ... blob is being dispersed
+	PutStatusDispersing PutStatus = "dispersing"
+	// PutStatusConfirmed ... blob batch has been confirmed on Ethereum but the cert hasn't been verified yet
+	PutStatusConfirmed PutStatus = "confirmed"
+	// PutStatusVerified ... cert has been verified against the on-chain batch metadata
+	PutStatusVerified PutStatus = "verified"
+)
+
+// PutStatusReporter is invoked by a store every time a Put makes progress
+type PutStatusReporter func(status PutStatus)
+
+type putStatusReporterKey struct{}
+
+// WithPutStatusReporter returns a context that makes stores report the progress of a Put to r
+func WithPutStatusReporter(ctx context.Context, r PutStatusReporter) context.Context {
+	return context.WithValue(ctx, putStatusReporterKey{}, r)
+}
+
+// ReportPutStatus notifies the reporter attached to ctx (if any) of a Put's progress
+func ReportPutStatus(ctx context.Context, status PutStatus) {
+	if r, ok := ctx.Value(putStatusReporterKey{}).(PutStatusReporter); ok && r != nil {
+		r(status)
+	}
+}
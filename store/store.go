@@ -2,8 +2,11 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 )
 
 type BackendType uint8
@@ -20,6 +23,14 @@ const (
 var (
 	ErrProxyOversizedBlob   = fmt.Errorf("encoded blob is larger than max blob size")
 	ErrEigenDAOversizedBlob = fmt.Errorf("blob size cannot exceed")
+
+	// ErrNotFound is returned when no backend holds the value of a key
+	ErrNotFound = errors.New("not found")
+	// ErrExpired is returned when the value of a key was stored, but has since been pruned
+	ErrExpired = errors.New("expired")
+	// ErrBackendUnavailable is returned when a backend can't serve requests at the moment, and the
+	// request may succeed once retried
+	ErrBackendUnavailable = errors.New("backend unavailable")
 )
 
 func (b BackendType) String() string {
@@ -88,3 +99,31 @@ type PrecomputedKeyStore interface {
 	// Put inserts the given value into the key-value data store.
 	Put(ctx context.Context, key []byte, value []byte) error
 }
+
+// CertReporter ... implemented by the EigenDA backends, whose keys are RLP encoded certs
+type CertReporter interface {
+	// ReportCert runs every verification step of the cert, and of the payload of its blob unless it's nil
+	ReportCert(ctx context.Context, cert *verify.Certificate, value []byte) (*verify.Report, error)
+}
+
+// CertVerifier ... implemented by the EigenDA backends, and the stores wrapping them, to verify the
+// certs of a key without the blob they commit to. Wrapping stores that only verify their share of
+// a blob (e.g, a frame of an aggregated blob) still verify the certs on cache and fallback reads.
+type CertVerifier interface {
+	// VerifyCert verifies every cert of the key against EigenDA's batch metadata bridged to Ethereum
+	VerifyCert(key []byte) error
+}
+
+// VerifyCert ... verifies the certs of the key with the store, which must be a CertVerifier
+func VerifyCert(s GeneratedKeyStore, key []byte) error {
+	v, ok := s.(CertVerifier)
+	if !ok {
+		return fmt.Errorf("%s backend can't verify certs without their blob", s.BackendType())
+	}
+	return v.VerifyCert(key)
+}
+
+// WrappingStore ... implemented by stores that wrap another store, and encode their own keys
+type WrappingStore interface {
+	Unwrap() GeneratedKeyStore
+}
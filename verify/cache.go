@@ -0,0 +1,124 @@
+package verify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// names of the contract read caches, used as metric labels
+const (
+	quorumNumbersRequiredCache     = "quorum_numbers_required"
+	quorumAdversaryThresholdsCache = "quorum_adversary_thresholds"
+	latestBlockNumberCache         = "latest_block_number"
+	finalizedBlockNumberCache      = "finalized_block_number"
+	batchMetadataHashCache         = "batch_metadata_hash"
+)
+
+// CacheConfig ... configures caching of the EigenDAServiceManager contract reads made while verifying
+// certs. A zero value disables the corresponding cache.
+type CacheConfig struct {
+	// how long the required quorums and quorum adversary thresholds are cached for
+	QuorumParamsTTL time.Duration
+	// how long the latest and finalized block numbers are cached for
+	BlockNumberTTL time.Duration
+	// max number of finalized batch metadata hashes cached by batch ID
+	BatchMetadataHashes int
+}
+
+// ttlValue caches a single value for a fixed duration
+type ttlValue[T any] struct {
+	name    string
+	ttl     time.Duration
+	now     func() time.Time
+	metrics metrics.Metricer
+
+	mu      sync.Mutex
+	value   T
+	expires time.Time
+}
+
+func newTTLValue[T any](name string, ttl time.Duration, m metrics.Metricer) *ttlValue[T] {
+	return &ttlValue[T]{name: name, ttl: ttl, now: time.Now, metrics: m}
+}
+
+// get returns the cached value if it hasn't expired yet, and otherwise reads and caches a new one.
+// Failed reads aren't cached.
+func (c *ttlValue[T]) get(read func() (T, error)) (T, error) {
+	if c.ttl <= 0 {
+		return read()
+	}
+
+	c.mu.Lock()
+	if c.now().Before(c.expires) {
+		value := c.value
+		c.mu.Unlock()
+		c.metrics.RecordContractCacheLookup(c.name, true)
+		return value, nil
+	}
+	c.mu.Unlock()
+	c.metrics.RecordContractCacheLookup(c.name, false)
+
+	value, err := read()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	c.value = value
+	c.expires = c.now().Add(c.ttl)
+	c.mu.Unlock()
+	return value, nil
+}
+
+// contractCache holds the cached contract reads of a CertVerifier
+type contractCache struct {
+	quorumNumbersRequired     *ttlValue[[]uint8]
+	quorumAdversaryThresholds *ttlValue[[]uint8]
+	latestBlockNumber         *ttlValue[uint64]
+	finalizedBlockNumber      *ttlValue[uint64]
+	// batch metadata hashes whose confirmation block is finalized, and thus can't change anymore
+	batchMetadataHashes *lru.Cache[uint32, [32]byte]
+	metrics             metrics.Metricer
+}
+
+func newContractCache(cfg CacheConfig, m metrics.Metricer) (*contractCache, error) {
+	c := &contractCache{
+		quorumNumbersRequired:     newTTLValue[[]uint8](quorumNumbersRequiredCache, cfg.QuorumParamsTTL, m),
+		quorumAdversaryThresholds: newTTLValue[[]uint8](quorumAdversaryThresholdsCache, cfg.QuorumParamsTTL, m),
+		latestBlockNumber:         newTTLValue[uint64](latestBlockNumberCache, cfg.BlockNumberTTL, m),
+		finalizedBlockNumber:      newTTLValue[uint64](finalizedBlockNumberCache, cfg.BlockNumberTTL, m),
+		metrics:                   m,
+	}
+
+	if cfg.BatchMetadataHashes > 0 {
+		hashes, err := lru.New[uint32, [32]byte](cfg.BatchMetadataHashes)
+		if err != nil {
+			return nil, err
+		}
+		c.batchMetadataHashes = hashes
+	}
+	return c, nil
+}
+
+// batchMetadataHash returns the cached metadata hash of a finalized batch
+func (c *contractCache) batchMetadataHash(id uint32) ([32]byte, bool) {
+	if c.batchMetadataHashes == nil {
+		return [32]byte{}, false
+	}
+
+	hash, ok := c.batchMetadataHashes.Get(id)
+	c.metrics.RecordContractCacheLookup(batchMetadataHashCache, ok)
+	return hash, ok
+}
+
+// addBatchMetadataHash caches the metadata hash of a batch. It must only be called once the batch's
+// confirmation block is finalized.
+func (c *contractCache) addBatchMetadataHash(id uint32, hash [32]byte) {
+	if c.batchMetadataHashes == nil {
+		return
+	}
+	c.batchMetadataHashes.Add(id, hash)
+}
@@ -0,0 +1,59 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLValue(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newTTLValue[uint64]("test", time.Minute, metrics.NoopMetrics)
+	c.now = func() time.Time { return now }
+
+	reads := 0
+	read := func() (uint64, error) {
+		reads++
+		return uint64(reads), nil
+	}
+
+	value, err := c.get(read)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), value)
+
+	// served from the cache until the ttl expires
+	now = now.Add(59 * time.Second)
+	value, err = c.get(read)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), value)
+
+	now = now.Add(time.Second)
+	value, err = c.get(read)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), value)
+
+	// failed reads aren't cached
+	now = now.Add(time.Minute)
+	_, err = c.get(func() (uint64, error) { return 0, errors.New("rpc down") })
+	require.Error(t, err)
+	value, err = c.get(read)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), value)
+}
+
+func TestTTLValueDisabled(t *testing.T) {
+	c := newTTLValue[uint64]("test", 0, metrics.NoopMetrics)
+
+	reads := 0
+	for i := 0; i < 3; i++ {
+		_, err := c.get(func() (uint64, error) {
+			reads++
+			return 0, nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, reads)
+}
@@ -6,107 +6,336 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/exp/slices"
 )
 
-var ErrBatchMetadataHashNotFound = errors.New("BatchMetadataHash not found for BatchId")
+var (
+	ErrBatchMetadataHashNotFound = errors.New("BatchMetadataHash not found for BatchId")
+	// ErrConfirmationBlockReorged is returned when the block a batch was confirmed in is replaced by
+	// a reorg while waiting for it to reach the confirmation depth. The batch may have been
+	// confirmed again in a later block, with different cert fields.
+	ErrConfirmationBlockReorged = errors.New("batch confirmation block was reorged out")
+)
+
+// EthClient is the subset of the Ethereum RPC client used to read the EigenDAServiceManager
+// contract. It's satisfied by *ethclient.Client as well as go-ethereum's simulated backend client.
+type EthClient interface {
+	bind.ContractCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// confirmationDepth is how deep the block a batch was confirmed in must be
+type confirmationDepth struct {
+	blocks uint64
+	// use the finalized block instead of the block that's blocks below the head
+	finalized bool
+}
 
 // CertVerifier verifies the DA certificate against on-chain EigenDA contracts
 // to ensure disperser returned fields haven't been tampered with
 type CertVerifier struct {
-	l                    log.Logger
-	ethConfirmationDepth uint64
-	manager              *binding.ContractEigenDAServiceManagerCaller
-	ethClient            *ethclient.Client
+	l         log.Logger
+	manager   *binding.ContractEigenDAServiceManagerCaller
+	ethClient EthClient
+	// depth that confirmation blocks must reach before puts return (write path), and before certs
+	// are accepted on gets (read path)
+	writeDepth confirmationDepth
+	readDepth  confirmationDepth
+	// read the batch metadata hash at the cert's confirmation block instead of the depth block
+	verifyAtConfirmationBlock bool
+	cache                     *contractCache
 }
 
-func NewCertVerifier(cfg *Config, l log.Logger) (*CertVerifier, error) {
-	log.Info("Enabling certificate verification", "confirmation_depth", cfg.EthConfirmationDepth)
+func NewCertVerifier(cfg *Config, l log.Logger, m metrics.Metricer) (*CertVerifier, error) {
+	log.Info("Enabling certificate verification",
+		"write_confirmation_depth", cfg.EthConfirmationDepth, "write_wait_for_finalized", cfg.WaitForFinalized,
+		"read_confirmation_depth", cfg.ReadConfirmationDepth, "read_wait_for_finalized", cfg.ReadWaitForFinalized,
+		"verify_at_confirmation_block", cfg.VerifyAtConfirmationBlock,
+		"eth_rpc_endpoints", 1+len(cfg.AdditionalRPCURLs), "eth_rpc_mode", cfg.RPCMode, "eth_rpc_quorum", cfg.RPCQuorum)
 
-	client, err := ethclient.Dial(cfg.RPCURL)
+	client, err := dialRPCs(cfg, l, m)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial ETH RPC node: %s", err.Error())
+		return nil, err
 	}
 
+	return NewCertVerifierWithClient(cfg, client, l, m)
+}
+
+// NewCertVerifierWithClient ... constructs a cert verifier that reads the service manager
+// contract through an already established client
+func NewCertVerifierWithClient(cfg *Config, client EthClient, l log.Logger, m metrics.Metricer) (*CertVerifier, error) {
 	// construct caller binding
-	m, err := binding.NewContractEigenDAServiceManagerCaller(common.HexToAddress(cfg.SvcManagerAddr), client)
+	manager, err := binding.NewContractEigenDAServiceManagerCaller(common.HexToAddress(cfg.SvcManagerAddr), client)
 	if err != nil {
 		return nil, err
 	}
 
+	cache, err := newContractCache(cfg.Cache, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract cache: %w", err)
+	}
+
 	return &CertVerifier{
-		l:                    l,
-		manager:              m,
-		ethConfirmationDepth: cfg.EthConfirmationDepth,
-		ethClient:            client,
+		l:                         l,
+		manager:                   manager,
+		ethClient:                 client,
+		writeDepth:                confirmationDepth{blocks: cfg.EthConfirmationDepth, finalized: cfg.WaitForFinalized},
+		readDepth:                 confirmationDepth{blocks: cfg.ReadConfirmationDepth, finalized: cfg.ReadWaitForFinalized},
+		verifyAtConfirmationBlock: cfg.VerifyAtConfirmationBlock,
+		cache:                     cache,
 	}, nil
 }
 
-// verifies on-chain batch ID for equivalence to certificate batch header fields
+// verifies on-chain batch ID for equivalence to certificate batch header fields, with the read path
+// confirmation depth
 func (cv *CertVerifier) VerifyBatch(
 	header *binding.IEigenDAServiceManagerBatchHeader, id uint32, recordHash [32]byte, confirmationNumber uint32,
 ) error {
-	blockNumber, err := cv.getConfDeepBlockNumber()
+	return cv.verifyBatch(context.Background(), cv.readDepth, header, id, recordHash, confirmationNumber)
+}
+
+func (cv *CertVerifier) verifyBatch(ctx context.Context, depth confirmationDepth,
+	header *binding.IEigenDAServiceManagerBatchHeader, id uint32, recordHash [32]byte, confirmationNumber uint32,
+) error {
+	_, err := cv.checkBatch(ctx, depth, header, id, recordHash, confirmationNumber)
+	return err
+}
+
+// batchCheck ... the values a batch was verified with, as far as verification got
+type batchCheck struct {
+	// block the batch metadata hash was read at
+	blockNumber uint64
+	// batch metadata hash stored on-chain, and the one computed from the cert
+	expected, actual [32]byte
+}
+
+func (cv *CertVerifier) checkBatch(ctx context.Context, depth confirmationDepth,
+	header *binding.IEigenDAServiceManagerBatchHeader, id uint32, recordHash [32]byte, confirmationNumber uint32,
+) (batchCheck, error) {
+	var check batchCheck
+
+	// the local hash doesn't depend on the contract, so it's reported even if the contract reads fail
+	actualHash, err := HashBatchMetadata(header, recordHash, confirmationNumber)
 	if err != nil {
-		return fmt.Errorf("failed to get context block: %w", err)
+		return check, fmt.Errorf("%w: failed to hash batch metadata: %w", ErrInvalidCert, err)
 	}
+	check.actual = actualHash
 
-	// 1. ensure that a batch hash can be looked up for a batch ID for a given block number
-	expectedHash, err := cv.manager.BatchIdToBatchMetadataHash(&bind.CallOpts{BlockNumber: blockNumber}, id)
+	blockNumber, err := cv.getConfDeepBlockNumber(ctx, depth)
 	if err != nil {
-		return fmt.Errorf("failed to get batch metadata hash: %w", err)
+		return check, fmt.Errorf("%w: failed to get context block: %w", ErrEthRPCUnavailable, err)
 	}
-	if bytes.Equal(expectedHash[:], make([]byte, 32)) {
-		return ErrBatchMetadataHashNotFound
+	check.blockNumber = blockNumber.Uint64()
+	// a batch confirmed above the depth block can't be looked up yet
+	notDeepEnough := fmt.Errorf("%w: %w: confirmation block %d is above block %d",
+		ErrInsufficientDepth, ErrBatchMetadataHashNotFound, confirmationNumber, blockNumber)
+	if cv.verifyAtConfirmationBlock {
+		// the batch must have been confirmed at the cert's confirmation block, which must be deep enough
+		if uint64(confirmationNumber) > blockNumber.Uint64() {
+			return check, notDeepEnough
+		}
+		blockNumber = new(big.Int).SetUint64(uint64(confirmationNumber))
+		check.blockNumber = blockNumber.Uint64()
 	}
 
-	// 2. ensure that hash generated from local cert matches one stored on-chain
-	actualHash, err := HashBatchMetadata(header, recordHash, confirmationNumber)
-	if err != nil {
-		return fmt.Errorf("failed to hash batch metadata: %w", err)
+	// 1. ensure that a batch hash can be looked up for a batch ID for a given block number
+	expectedHash, cached := cv.cache.batchMetadataHash(id)
+	switch {
+	case cached && uint64(confirmationNumber) > blockNumber.Uint64():
+		// the cached hash is finalized, but the batch still has to be deep enough for the configured depth
+		return check, notDeepEnough
+	case !cached:
+		expectedHash, err = cv.manager.BatchIdToBatchMetadataHash(&bind.CallOpts{BlockNumber: blockNumber}, id)
+		if err != nil {
+			return check, fmt.Errorf("%w: failed to get batch metadata hash: %w", ErrEthRPCUnavailable, err)
+		}
+		if bytes.Equal(expectedHash[:], make([]byte, 32)) {
+			if uint64(confirmationNumber) > blockNumber.Uint64() {
+				return check, notDeepEnough
+			}
+			return check, fmt.Errorf("%w: %w for batch %d at block %d", ErrInvalidCert, ErrBatchMetadataHashNotFound, id, blockNumber)
+		}
 	}
+	check.expected = expectedHash
 
+	// 2. ensure that hash generated from local cert matches one stored on-chain
 	equal := slices.Equal(expectedHash[:], actualHash[:])
 	if !equal {
-		return fmt.Errorf("batch hash mismatch, expected: %x, got: %x", expectedHash, actualHash)
+		return check, fmt.Errorf("%w: batch hash mismatch, expected: %x, got: %x", ErrInvalidCert, expectedHash, actualHash)
+	}
+
+	if !cached {
+		cv.cacheBatchMetadataHash(ctx, id, expectedHash, confirmationNumber)
+	}
+	return check, nil
+}
+
+// cacheBatchMetadataHash caches the verified metadata hash of a batch once its confirmation block is
+// finalized. The hash commits to the confirmation block number, so a matching hash proves where the
+// batch was confirmed.
+func (cv *CertVerifier) cacheBatchMetadataHash(ctx context.Context, id uint32, hash [32]byte, confirmationNumber uint32) {
+	if cv.cache.batchMetadataHashes == nil {
+		return
 	}
 
-	return nil
+	finalized, err := cv.getFinalizedBlockNumber(ctx)
+	if err != nil {
+		cv.l.Debug("Not caching batch metadata hash", "batchID", id, "err", err)
+		return
+	}
+	if uint64(confirmationNumber) <= finalized {
+		cv.cache.addBatchMetadataHash(id, hash)
+	}
 }
 
 // verifies the blob batch inclusion proof against the blob root hash
 func (cv *CertVerifier) VerifyMerkleProof(inclusionProof []byte, root []byte,
 	blobIndex uint32, blobHeader BlobHeader) error {
+	return verifyMerkleProof(inclusionProof, root, blobIndex, blobHeader)
+}
+
+// verifyMerkleProof doesn't depend on the contract state, so certs can be checked without a CertVerifier
+func verifyMerkleProof(inclusionProof []byte, root []byte, blobIndex uint32, blobHeader BlobHeader) error {
+	_, err := checkMerkleProof(inclusionProof, root, blobIndex, blobHeader)
+	return err
+}
+
+// checkMerkleProof returns the root the inclusion proof leads to along with the verification result
+func checkMerkleProof(inclusionProof []byte, root []byte, blobIndex uint32, blobHeader BlobHeader) (common.Hash, error) {
 	leafHash, err := HashEncodeBlobHeader(blobHeader)
 	if err != nil {
-		return err
+		return common.Hash{}, fmt.Errorf("%w: %w", ErrInvalidCert, err)
 	}
 
 	generatedRoot, err := ProcessInclusionProof(inclusionProof, leafHash, uint64(blobIndex))
 	if err != nil {
-		return err
+		return common.Hash{}, fmt.Errorf("%w: %w", ErrInvalidCert, err)
 	}
 
 	equal := slices.Equal(root, generatedRoot.Bytes())
 	if !equal {
-		return fmt.Errorf("root hash mismatch, expected: %x, got: %x", root, generatedRoot)
+		return generatedRoot, fmt.Errorf("%w: root hash mismatch, expected: %x, got: %x", ErrInvalidCert, root, generatedRoot)
+	}
+
+	return generatedRoot, nil
+}
+
+// WaitForConfirmationDepth blocks until the given confirmation block reaches the write path
+// confirmation depth. The hash of the confirmation block is
+// tracked while waiting and ErrConfirmationBlockReorged is returned if it changes.
+func (cv *CertVerifier) WaitForConfirmationDepth(ctx context.Context, confirmationBlock uint64, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var confirmationHash common.Hash
+	for {
+		done, err := cv.checkConfirmationDepth(ctx, confirmationBlock, &confirmationHash)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("confirmation block %d didn't reach the confirmation depth: %w", confirmationBlock, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkConfirmationDepth returns whether the confirmation block is deep enough. RPC errors are
+// logged and treated as not deep enough so that the caller keeps polling.
+func (cv *CertVerifier) checkConfirmationDepth(ctx context.Context, confirmationBlock uint64, confirmationHash *common.Hash) (bool, error) {
+	header, err := cv.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(confirmationBlock))
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		// the RPC node hasn't seen the confirmation block yet
+		cv.l.Debug("Confirmation block not found yet", "block", confirmationBlock)
+		return false, nil
+	case err != nil:
+		cv.l.Warn("Failed to read confirmation block, will retry", "block", confirmationBlock, "err", err)
+		return false, nil
+	case *confirmationHash == (common.Hash{}):
+		*confirmationHash = header.Hash()
+	case header.Hash() != *confirmationHash:
+		return false, fmt.Errorf("%w: block %d hash changed from %s to %s",
+			ErrConfirmationBlockReorged, confirmationBlock, confirmationHash, header.Hash())
 	}
 
-	return nil
+	deepBlock, err := cv.getConfDeepBlockNumber(ctx, cv.writeDepth)
+	if err != nil {
+		cv.l.Warn("Failed to read confirmation depth, will retry", "err", err)
+		return false, nil
+	}
+	if deepBlock.Uint64() < confirmationBlock {
+		cv.l.Debug("Waiting for confirmation depth", "block", confirmationBlock, "deepBlock", deepBlock,
+			"depth", cv.writeDepth.blocks, "finalized", cv.writeDepth.finalized)
+		return false, nil
+	}
+	return true, nil
 }
 
-// fetches a block number provided a subtraction of a user defined conf depth from latest block
-func (cv *CertVerifier) getConfDeepBlockNumber() (*big.Int, error) {
-	blockNumber, err := cv.ethClient.BlockNumber(context.Background())
+// fetches the finalized block number when the depth is finalized, or a block number provided a
+// subtraction of a user defined conf depth from latest block
+func (cv *CertVerifier) getConfDeepBlockNumber(ctx context.Context, depth confirmationDepth) (*big.Int, error) {
+	if depth.finalized {
+		finalized, err := cv.getFinalizedBlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(finalized), nil
+	}
+
+	blockNumber, err := cv.cache.latestBlockNumber.get(func() (uint64, error) {
+		return cv.ethClient.BlockNumber(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest block number: %w", err)
 	}
-	return new(big.Int).SetUint64(max(blockNumber-cv.ethConfirmationDepth, 0)), nil
+	if blockNumber < depth.blocks {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).SetUint64(blockNumber - depth.blocks), nil
+}
+
+// getFinalizedBlockNumber fetches the number of the finalized block
+func (cv *CertVerifier) getFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	finalized, err := cv.cache.finalizedBlockNumber.get(func() (uint64, error) {
+		header, err := cv.ethClient.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		if err != nil {
+			return 0, err
+		}
+		return header.Number.Uint64(), nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get finalized block: %w", err)
+	}
+	return finalized, nil
+}
+
+// quorumNumbersRequired reads the quorums that every blob must be dispersed to
+func (cv *CertVerifier) quorumNumbersRequired() ([]uint8, error) {
+	return cv.cache.quorumNumbersRequired.get(func() ([]uint8, error) {
+		return cv.manager.QuorumNumbersRequired(nil)
+	})
+}
+
+// quorumAdversaryThresholdPercentages reads the adversary threshold percentage of each quorum
+func (cv *CertVerifier) quorumAdversaryThresholdPercentages() ([]uint8, error) {
+	return cv.cache.quorumAdversaryThresholds.get(func() ([]uint8, error) {
+		return cv.manager.QuorumAdversaryThresholdPercentages(nil)
+	})
 }
@@ -1,9 +1,12 @@
 package verify
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // G1Point struct to represent G1Point in Solidity
@@ -29,6 +32,26 @@ type BlobHeader struct {
 
 type Certificate disperser.BlobInfo
 
+// DecodeCertificate ... RLP decodes a cert, and ensures that the fields dereferenced while verifying
+// it are set
+func DecodeCertificate(b []byte) (*Certificate, error) {
+	var cert Certificate
+	if err := rlp.DecodeBytes(b, &cert); err != nil {
+		return nil, fmt.Errorf("failed to decode DA cert to RLP format: %w", err)
+	}
+
+	switch {
+	case cert.BlobHeader == nil || cert.BlobHeader.Commitment == nil:
+		return nil, fmt.Errorf("DA cert has no blob header commitment")
+	case cert.BlobVerificationProof == nil || cert.BlobVerificationProof.BatchMetadata == nil ||
+		cert.BlobVerificationProof.BatchMetadata.BatchHeader == nil:
+		return nil, fmt.Errorf("DA cert has no batch metadata")
+	case len(cert.BatchHeaderRoot()) != 32 || len(cert.Proof().BatchMetadata.GetSignatoryRecordHash()) != 32:
+		return nil, fmt.Errorf("DA cert batch root and signatory record hash must be 32 bytes")
+	}
+	return &cert, nil
+}
+
 func (c *Certificate) BlobIndex() uint32 {
 	return c.BlobVerificationProof.BlobIndex
 }
@@ -63,3 +86,113 @@ func (c *Certificate) ReadBlobHeader() BlobHeader {
 func (c *Certificate) Proof() *disperser.BlobVerificationProof {
 	return c.BlobVerificationProof
 }
+
+// CheckRecency ... verifies that the cert was included on L1 after its reference block and at most window
+// blocks after it, so that its blob can't have expired from EigenDA before the rollup derives it. A cert
+// can't be included before the block it references exists.
+func (c *Certificate) CheckRecency(inclusionBlock uint64, window uint64) error {
+	return CheckRecency(c.ReferenceBlock(), inclusionBlock, window)
+}
+
+// ReferenceBlock ... L1 block that the operator stakes of the cert's batch were read at
+func (c *Certificate) ReferenceBlock() uint64 {
+	return uint64(c.Proof().GetBatchMetadata().GetBatchHeader().GetReferenceBlockNumber())
+}
+
+// CheckRecency ... verifies that a cert of any version, referencing the L1 block referenceBlock, was
+// included on L1 within the recency window after it
+func CheckRecency(referenceBlock uint64, inclusionBlock uint64, window uint64) error {
+	if inclusionBlock <= referenceBlock {
+		return fmt.Errorf("%w: included in L1 block %d, which isn't after reference block %d",
+			ErrRecencyWindowExceeded, inclusionBlock, referenceBlock)
+	}
+	if inclusionBlock > referenceBlock+window {
+		return fmt.Errorf("%w: included in L1 block %d, more than %d blocks after reference block %d",
+			ErrRecencyWindowExceeded, inclusionBlock, window, referenceBlock)
+	}
+	return nil
+}
+
+// CertInfo ... human readable form of a cert, with byte fields hex encoded and the quorum fields
+// listed as numbers
+type CertInfo struct {
+	BlobHeader struct {
+		Commitment struct {
+			X hexutil.Bytes `json:"x"`
+			Y hexutil.Bytes `json:"y"`
+		} `json:"commitment"`
+		DataLength       uint32                `json:"data_length"`
+		BlobQuorumParams []QuorumBlobParamInfo `json:"blob_quorum_params"`
+	} `json:"blob_header"`
+	BlobVerificationProof struct {
+		BatchID       uint32 `json:"batch_id"`
+		BlobIndex     uint32 `json:"blob_index"`
+		BatchMetadata struct {
+			BatchHeader struct {
+				BatchRoot               hexutil.Bytes `json:"batch_root"`
+				QuorumNumbers           []uint        `json:"quorum_numbers"`
+				QuorumSignedPercentages []uint        `json:"quorum_signed_percentages"`
+				ReferenceBlockNumber    uint32        `json:"reference_block_number"`
+			} `json:"batch_header"`
+			SignatoryRecordHash     hexutil.Bytes `json:"signatory_record_hash"`
+			Fee                     hexutil.Bytes `json:"fee"`
+			ConfirmationBlockNumber uint32        `json:"confirmation_block_number"`
+			BatchHeaderHash         hexutil.Bytes `json:"batch_header_hash"`
+		} `json:"batch_metadata"`
+		InclusionProof hexutil.Bytes `json:"inclusion_proof"`
+		QuorumIndexes  []uint        `json:"quorum_indexes"`
+	} `json:"blob_verification_proof"`
+}
+
+// QuorumBlobParamInfo ... human readable form of the security params of a quorum in a cert
+type QuorumBlobParamInfo struct {
+	QuorumNumber                    uint32 `json:"quorum_number"`
+	AdversaryThresholdPercentage    uint32 `json:"adversary_threshold_percentage"`
+	ConfirmationThresholdPercentage uint32 `json:"confirmation_threshold_percentage"`
+	ChunkLength                     uint32 `json:"chunk_length"`
+}
+
+// numbers converts bytes to a list of numbers, since json encodes bytes as base64
+func numbers(b []byte) []uint {
+	n := make([]uint, len(b))
+	for i := range b {
+		n[i] = uint(b[i])
+	}
+	return n
+}
+
+// Info returns the human readable form of the cert
+func (c *Certificate) Info() CertInfo {
+	var info CertInfo
+
+	header := c.BlobHeader
+	info.BlobHeader.Commitment.X = header.GetCommitment().GetX()
+	info.BlobHeader.Commitment.Y = header.GetCommitment().GetY()
+	info.BlobHeader.DataLength = header.GetDataLength()
+	info.BlobHeader.BlobQuorumParams = make([]QuorumBlobParamInfo, len(header.GetBlobQuorumParams()))
+	for i, qp := range header.GetBlobQuorumParams() {
+		info.BlobHeader.BlobQuorumParams[i] = QuorumBlobParamInfo{
+			QuorumNumber:                    qp.GetQuorumNumber(),
+			AdversaryThresholdPercentage:    qp.GetAdversaryThresholdPercentage(),
+			ConfirmationThresholdPercentage: qp.GetConfirmationThresholdPercentage(),
+			ChunkLength:                     qp.GetChunkLength(),
+		}
+	}
+
+	proof := &info.BlobVerificationProof
+	proof.BatchID = c.Proof().GetBatchId()
+	proof.BlobIndex = c.Proof().GetBlobIndex()
+	proof.InclusionProof = c.Proof().GetInclusionProof()
+	proof.QuorumIndexes = numbers(c.Proof().GetQuorumIndexes())
+
+	metadata := c.Proof().GetBatchMetadata()
+	proof.BatchMetadata.BatchHeader.BatchRoot = metadata.GetBatchHeader().GetBatchRoot()
+	proof.BatchMetadata.BatchHeader.QuorumNumbers = numbers(metadata.GetBatchHeader().GetQuorumNumbers())
+	proof.BatchMetadata.BatchHeader.QuorumSignedPercentages = numbers(metadata.GetBatchHeader().GetQuorumSignedPercentages())
+	proof.BatchMetadata.BatchHeader.ReferenceBlockNumber = metadata.GetBatchHeader().GetReferenceBlockNumber()
+	proof.BatchMetadata.SignatoryRecordHash = metadata.GetSignatoryRecordHash()
+	proof.BatchMetadata.Fee = metadata.GetFee()
+	proof.BatchMetadata.ConfirmationBlockNumber = metadata.GetConfirmationBlockNumber()
+	proof.BatchMetadata.BatchHeaderHash = metadata.GetBatchHeaderHash()
+	return info
+}
@@ -0,0 +1,41 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRecency(t *testing.T) {
+	cert := &Certificate{
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader: &disperser.BatchHeader{ReferenceBlockNumber: 1000},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		inclusionBlock uint64
+		valid          bool
+	}{
+		{"BeforeReferenceBlock", 999, false},
+		{"AtReferenceBlock", 1000, false},
+		{"AfterReferenceBlock", 1001, true},
+		{"AtWindowEnd", 1100, true},
+		{"AfterWindowEnd", 1101, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cert.CheckRecency(tt.inclusionBlock, 100)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrRecencyWindowExceeded)
+			}
+		})
+	}
+}
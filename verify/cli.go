@@ -3,6 +3,9 @@ package verify
 import (
 	"fmt"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Layr-Labs/eigenda-proxy/utils"
 	"github.com/Layr-Labs/eigenda/encoding/kzg"
@@ -16,22 +19,43 @@ var (
 	MaxAllowedBlobSize = uint64(MaxSRSPoints * BytesPerSymbol / MaxCodingRatio)
 )
 
+// FinalizedConfirmationDepth ... confirmation depth flag value that waits for the finalized block tag
+const FinalizedConfirmationDepth = "finalized"
+
 // TODO: should this live in the resources pkg?
 // So that if we ever change the SRS files there we can change this value
 const srsOrder = 268435456 // 2 ^ 32
 
 var (
 	// cert verification flags
-	CertVerificationDisabledFlagName = withFlagPrefix("cert-verification-disabled")
-	EthRPCFlagName                   = withFlagPrefix("eth-rpc")
-	SvcManagerAddrFlagName           = withFlagPrefix("svc-manager-addr")
-	EthConfirmationDepthFlagName     = withFlagPrefix("eth-confirmation-depth")
+	CertVerificationDisabledFlagName  = withFlagPrefix("cert-verification-disabled")
+	EthRPCFlagName                    = withFlagPrefix("eth-rpc")
+	AdditionalEthRPCsFlagName         = withFlagPrefix("additional-eth-rpcs")
+	EthRPCModeFlagName                = withFlagPrefix("eth-rpc-mode")
+	EthRPCQuorumFlagName              = withFlagPrefix("eth-rpc-quorum")
+	SvcManagerAddrFlagName            = withFlagPrefix("svc-manager-addr")
+	EthConfirmationDepthFlagName      = withFlagPrefix("eth-confirmation-depth")
+	WriteConfirmationDepthFlagName    = withFlagPrefix("write-confirmation-depth")
+	ReadConfirmationDepthFlagName     = withFlagPrefix("read-confirmation-depth")
+	VerifyAtConfirmationBlockFlagName = withFlagPrefix("verify-at-confirmation-block")
+
+	// contract read cache flags
+	CacheQuorumParamsTTLFlagName     = withFlagPrefix("contract-cache.quorum-params-ttl")
+	CacheBlockNumberTTLFlagName      = withFlagPrefix("contract-cache.block-number-ttl")
+	CacheBatchMetadataHashesFlagName = withFlagPrefix("contract-cache.batch-metadata-hashes")
+
+	// commitment verification flags
+	CommitmentVerificationModeFlagName = withFlagPrefix("commitment-verification-mode")
+	OpeningProofCacheSizeFlagName      = withFlagPrefix("opening-proof-cache-size")
 
 	// kzg flags
-	G1PathFlagName         = withFlagPrefix("g1-path")
-	G2PowerOf2PathFlagName = withFlagPrefix("g2-power-of-2-path")
-	CachePathFlagName      = withFlagPrefix("cache-path")
-	MaxBlobLengthFlagName  = withFlagPrefix("max-blob-length")
+	G1PathFlagName             = withFlagPrefix("g1-path")
+	G2PowerOf2PathFlagName     = withFlagPrefix("g2-power-of-2-path")
+	CachePathFlagName          = withFlagPrefix("cache-path")
+	MaxBlobLengthFlagName      = withFlagPrefix("max-blob-length")
+	SRSLoadingFlagName         = withFlagPrefix("srs-loading")
+	G1ChecksumFlagName         = withFlagPrefix("g1-sha256")
+	G2PowerOf2ChecksumFlagName = withFlagPrefix("g2-power-of-2-sha256")
 )
 
 // we keep the eigenda prefix like eigenda client flags, because we
@@ -61,17 +85,109 @@ func CLIFlags(envPrefix, category string) []cli.Flag {
 			EnvVars:  []string{withEnvPrefix(envPrefix, "ETH_RPC")},
 			Category: category,
 		},
+		&cli.StringSliceFlag{
+			Name:     AdditionalEthRPCsFlagName,
+			Usage:    "Additional JSON RPC node endpoints for the Ethereum network, used along with the eth rpc for cert verification as set by the eth rpc mode. Use endpoints of independent providers.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "ADDITIONAL_ETH_RPCS")},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:    EthRPCModeFlagName,
+			Usage:   fmt.Sprintf("How contract reads are spread across the eth rpc and the additional eth rpcs. %q reads from the first endpoint that doesn't fail, in order. %q reads from every endpoint and requires the eth rpc quorum of them to return the same result.", FailoverRPCMode, ConsensusRPCMode),
+			EnvVars: []string{withEnvPrefix(envPrefix, "ETH_RPC_MODE")},
+			Value:   string(FailoverRPCMode),
+			Action: func(_ *cli.Context, mode string) error {
+				_, err := StringToRPCMode(mode)
+				return err
+			},
+			Category: category,
+		},
+		&cli.UintFlag{
+			Name:     EthRPCQuorumFlagName,
+			Usage:    "Number of eth rpc endpoints that must agree on every contract read in consensus mode. Must be a majority of the endpoints. 0 requires all of them to agree.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "ETH_RPC_QUORUM")},
+			Category: category,
+		},
 		&cli.StringFlag{
 			Name:     SvcManagerAddrFlagName,
 			Usage:    "The deployed EigenDA service manager address. The list can be found here: https://github.com/Layr-Labs/eigenlayer-middleware/?tab=readme-ov-file#current-mainnet-deployment",
 			EnvVars:  []string{withEnvPrefix(envPrefix, "SERVICE_MANAGER_ADDR")},
 			Category: category,
 		},
-		&cli.Uint64Flag{
-			Name:     EthConfirmationDepthFlagName,
-			Usage:    "The number of Ethereum blocks to wait before considering a submitted blob's DA batch submission confirmed. `0` means wait for inclusion only.",
-			EnvVars:  []string{withEnvPrefix(envPrefix, "ETH_CONFIRMATION_DEPTH")},
-			Value:    0,
+		&cli.StringFlag{
+			Name:    EthConfirmationDepthFlagName,
+			Usage:   "The number of Ethereum blocks to wait before considering a submitted blob's DA batch submission confirmed. `0` means wait for inclusion only. `finalized` waits for the batch's confirmation block to be finalized. Applies to both puts and gets, unless overridden by the write or read confirmation depth.",
+			EnvVars: []string{withEnvPrefix(envPrefix, "ETH_CONFIRMATION_DEPTH")},
+			Value:   "0",
+			Action: func(_ *cli.Context, depth string) error {
+				_, _, err := ParseConfirmationDepth(depth)
+				return err
+			},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:    WriteConfirmationDepthFlagName,
+			Usage:   "Confirmation depth (number of blocks or finalized) the batch of a blob must reach before a put returns its cert. Defaults to the eth confirmation depth.",
+			EnvVars: []string{withEnvPrefix(envPrefix, "WRITE_CONFIRMATION_DEPTH")},
+			Action: func(_ *cli.Context, depth string) error {
+				_, _, err := ParseConfirmationDepth(depth)
+				return err
+			},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:    ReadConfirmationDepthFlagName,
+			Usage:   "Confirmation depth (number of blocks or finalized) the batch of a cert must have before a get returns its data. Defaults to the eth confirmation depth.",
+			EnvVars: []string{withEnvPrefix(envPrefix, "READ_CONFIRMATION_DEPTH")},
+			Action: func(_ *cli.Context, depth string) error {
+				_, _, err := ParseConfirmationDepth(depth)
+				return err
+			},
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     VerifyAtConfirmationBlockFlagName,
+			Usage:    "Verify the batch metadata hash of certs against the contract state at their confirmation block, instead of the block at the confirmation depth. Requires an archive node for certs older than the state retained by the eth rpc.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "VERIFY_AT_CONFIRMATION_BLOCK")},
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     CacheQuorumParamsTTLFlagName,
+			Usage:    "How long the required quorums and quorum adversary thresholds read from the service manager are cached for. 0 disables the cache.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "CONTRACT_CACHE_QUORUM_PARAMS_TTL")},
+			Value:    5 * time.Minute,
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     CacheBlockNumberTTLFlagName,
+			Usage:    "How long the latest and finalized block numbers used to check the confirmation depth are cached for. 0 disables the cache.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "CONTRACT_CACHE_BLOCK_NUMBER_TTL")},
+			Value:    time.Second,
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     CacheBatchMetadataHashesFlagName,
+			Usage:    "Max number of batch metadata hashes cached by batch ID once their confirmation block is finalized. 0 disables the cache.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "CONTRACT_CACHE_BATCH_METADATA_HASHES")},
+			Value:    10_000,
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:    CommitmentVerificationModeFlagName,
+			Usage:   fmt.Sprintf("How blobs are verified against the kzg commitment of their cert. %q recomputes the commitment of the blob on every read. %q checks an opening proof of the blob polynomial at a Fiat-Shamir challenge instead, which is computed once per commitment and cached.", RecommitMode, PointOpeningMode),
+			EnvVars: []string{withEnvPrefix(envPrefix, "COMMITMENT_VERIFICATION_MODE")},
+			Value:   string(RecommitMode),
+			Action: func(_ *cli.Context, mode string) error {
+				_, err := StringToCommitmentVerificationMode(mode)
+				return err
+			},
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     OpeningProofCacheSizeFlagName,
+			Usage:    fmt.Sprintf("Max number of opening proofs cached by commitment when the commitment verification mode is %q. 0 disables the cache, so that every read computes a proof.", PointOpeningMode),
+			EnvVars:  []string{withEnvPrefix(envPrefix, "OPENING_PROOF_CACHE_SIZE")},
+			Value:    10_000,
 			Category: category,
 		},
 		// kzg flags
@@ -102,6 +218,31 @@ func CLIFlags(envPrefix, category string) []cli.Flag {
 			Value:    "resources/SRSTables/",
 			Category: category,
 		},
+		&cli.StringFlag{
+			Name:    SRSLoadingFlagName,
+			Usage:   fmt.Sprintf("When the G1 points of the memory-mapped g1.point file are decoded. %q decodes every point the max blob length needs at startup. %q decodes points the first time a blob needs them, which speeds up startup and keeps memory use to the largest blob committed to.", EagerSRSLoading, LazySRSLoading),
+			EnvVars: []string{withEnvPrefix(envPrefix, "SRS_LOADING")},
+			Value:   string(LazySRSLoading),
+			Action: func(_ *cli.Context, mode string) error {
+				_, err := StringToSRSLoadingMode(mode)
+				return err
+			},
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     G1ChecksumFlagName,
+			Usage:    "Hex encoded sha256 checksum that the g1.point file must match at startup. Defaults to the checksum of the file shipped in resources/. Set to the checksum of a custom SRS file, or to an empty string to skip the check.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "TARGET_KZG_G1_SHA256")},
+			Value:    G1SHA256,
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     G2PowerOf2ChecksumFlagName,
+			Usage:    "Hex encoded sha256 checksum that the g2.point.powerOf2 file must match at startup. Defaults to the checksum of the file shipped in resources/. Not checked if empty.",
+			EnvVars:  []string{withEnvPrefix(envPrefix, "TARGET_KZG_G2_POWER_OF_2_SHA256")},
+			Value:    G2PowerOf2SHA256,
+			Category: category,
+		},
 		// TODO: can we use a genericFlag for this, and automatically parse the string into a uint64?
 		&cli.StringFlag{
 			Name:    MaxBlobLengthFlagName,
@@ -137,6 +278,20 @@ func CLIFlags(envPrefix, category string) []cli.Flag {
 // TODO: there's def a better way to deal with this... perhaps a generic flag that can parse the string into a uint64?
 var MaxBlobLengthBytes uint64
 
+// ParseConfirmationDepth parses a confirmation depth flag value, which is either a number of blocks
+// or the "finalized" block tag (also accepted as -1)
+func ParseConfirmationDepth(s string) (depth uint64, finalized bool, err error) {
+	if strings.ToLower(s) == FinalizedConfirmationDepth || s == "-1" {
+		return 0, true, nil
+	}
+
+	depth, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid confirmation depth %q, expected a number of blocks or %q", s, FinalizedConfirmationDepth)
+	}
+	return depth, false, nil
+}
+
 func ReadConfig(ctx *cli.Context) Config {
 	kzgCfg := &kzg.KzgConfig{
 		G1Path:          ctx.String(G1PathFlagName),
@@ -147,11 +302,42 @@ func ReadConfig(ctx *cli.Context) Config {
 		NumWorker:       uint64(runtime.GOMAXPROCS(0)), // #nosec G115
 	}
 
+	// the flag values were already validated by their actions
+	depth, finalized, _ := ParseConfirmationDepth(ctx.String(EthConfirmationDepthFlagName))
+	writeDepth, writeFinalized := depth, finalized
+	if ctx.IsSet(WriteConfirmationDepthFlagName) {
+		writeDepth, writeFinalized, _ = ParseConfirmationDepth(ctx.String(WriteConfirmationDepthFlagName))
+	}
+	readDepth, readFinalized := depth, finalized
+	if ctx.IsSet(ReadConfirmationDepthFlagName) {
+		readDepth, readFinalized, _ = ParseConfirmationDepth(ctx.String(ReadConfirmationDepthFlagName))
+	}
+	mode, _ := StringToCommitmentVerificationMode(ctx.String(CommitmentVerificationModeFlagName))
+	rpcMode, _ := StringToRPCMode(ctx.String(EthRPCModeFlagName))
+	srsLoading, _ := StringToSRSLoadingMode(ctx.String(SRSLoadingFlagName))
+
 	return Config{
-		KzgConfig:            kzgCfg,
-		VerifyCerts:          !ctx.Bool(CertVerificationDisabledFlagName),
-		RPCURL:               ctx.String(EthRPCFlagName),
-		SvcManagerAddr:       ctx.String(SvcManagerAddrFlagName),
-		EthConfirmationDepth: uint64(ctx.Int64(EthConfirmationDepthFlagName)), // #nosec G115
+		KzgConfig:                 kzgCfg,
+		VerifyCerts:               !ctx.Bool(CertVerificationDisabledFlagName),
+		RPCURL:                    ctx.String(EthRPCFlagName),
+		AdditionalRPCURLs:         ctx.StringSlice(AdditionalEthRPCsFlagName),
+		RPCMode:                   rpcMode,
+		RPCQuorum:                 ctx.Uint(EthRPCQuorumFlagName),
+		SvcManagerAddr:            ctx.String(SvcManagerAddrFlagName),
+		EthConfirmationDepth:      writeDepth,
+		WaitForFinalized:          writeFinalized,
+		ReadConfirmationDepth:     readDepth,
+		ReadWaitForFinalized:      readFinalized,
+		VerifyAtConfirmationBlock: ctx.Bool(VerifyAtConfirmationBlockFlagName),
+		Cache: CacheConfig{
+			QuorumParamsTTL:     ctx.Duration(CacheQuorumParamsTTLFlagName),
+			BlockNumberTTL:      ctx.Duration(CacheBlockNumberTTLFlagName),
+			BatchMetadataHashes: ctx.Int(CacheBatchMetadataHashesFlagName),
+		},
+		CommitmentVerification: mode,
+		OpeningProofCacheSize:  ctx.Int(OpeningProofCacheSizeFlagName),
+		SRSLoading:             srsLoading,
+		G1Checksum:             ctx.String(G1ChecksumFlagName),
+		G2PowerOf2Checksum:     ctx.String(G2PowerOf2ChecksumFlagName),
 	}
 }
@@ -0,0 +1,21 @@
+package verify
+
+import "errors"
+
+// Verification errors are wrapped by one of these categories, so that callers can tell certs that
+// will never verify apart from checks that may pass once retried.
+var (
+	// ErrInvalidCert is returned for certs that don't match the batch confirmed on-chain, or that
+	// don't meet the security params
+	ErrInvalidCert = errors.New("invalid cert")
+	// ErrCommitmentMismatch is returned when a blob doesn't match the kzg commitment of its cert, or
+	// a value doesn't match its keccak256 commitment
+	ErrCommitmentMismatch = errors.New("value doesn't match its commitment")
+	// ErrInsufficientDepth is returned when the batch of a cert isn't confirmation depth deep yet
+	ErrInsufficientDepth = errors.New("cert is not confirmation depth deep yet")
+	// ErrRecencyWindowExceeded is returned for certs that were included on L1 more than the recency
+	// window after their reference block, whose batch rollup derivation must drop
+	ErrRecencyWindowExceeded = errors.New("cert was not included within the recency window of its reference block")
+	// ErrEthRPCUnavailable is returned when the contract state can't be read from the eth rpc
+	ErrEthRPCUnavailable = errors.New("eth rpc unavailable")
+)
@@ -0,0 +1,314 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// RPCMode ... how reads are spread across the eth rpc endpoints
+type RPCMode string
+
+const (
+	// FailoverRPCMode reads from the endpoints in order, moving to the next one when a read fails
+	FailoverRPCMode RPCMode = "failover"
+	// ConsensusRPCMode reads from every endpoint, and requires a quorum of them to return the same result
+	ConsensusRPCMode RPCMode = "consensus"
+)
+
+func StringToRPCMode(s string) (RPCMode, error) {
+	switch RPCMode(s) {
+	case FailoverRPCMode, ConsensusRPCMode:
+		return RPCMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown eth rpc mode %q, expected %q or %q", s, FailoverRPCMode, ConsensusRPCMode)
+	}
+}
+
+// ErrRPCDisagreement is returned when fewer eth rpc endpoints than the quorum agree on a read
+var ErrRPCDisagreement = errors.New("eth rpc endpoints disagree")
+
+// RPCResponse ... result of a read from one eth rpc endpoint
+type RPCResponse struct {
+	Endpoint string
+	Result   string
+	Err      error
+}
+
+func (r RPCResponse) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: error: %s", r.Endpoint, r.Err)
+	}
+	return fmt.Sprintf("%s: %s", r.Endpoint, r.Result)
+}
+
+// RPCDisagreementError ... details the responses of every endpoint to a read they didn't agree on
+type RPCDisagreementError struct {
+	Method    string
+	Quorum    int
+	Responses []RPCResponse
+}
+
+func (e *RPCDisagreementError) Error() string {
+	responses := make([]string, len(e.Responses))
+	for i, r := range e.Responses {
+		responses[i] = r.String()
+	}
+	return fmt.Sprintf("%s: %s needs %d of %d endpoints to agree, got [%s]",
+		ErrRPCDisagreement, e.Method, e.Quorum, len(e.Responses), strings.Join(responses, ", "))
+}
+
+func (e *RPCDisagreementError) Unwrap() error {
+	return ErrRPCDisagreement
+}
+
+// RPCEndpointName returns the host of an rpc url, so that it can be logged without the api keys that
+// are often part of the url's path or query
+func RPCEndpointName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "invalid-url"
+	}
+	return u.Host
+}
+
+type rpcEndpoint struct {
+	name   string
+	client EthClient
+}
+
+// multiRPCClient is an EthClient that spreads every read across several eth rpc endpoints
+type multiRPCClient struct {
+	endpoints []rpcEndpoint
+	mode      RPCMode
+	// number of endpoints that must agree on a read in consensus mode
+	quorum  int
+	l       log.Logger
+	metrics metrics.Metricer
+}
+
+var _ EthClient = (*multiRPCClient)(nil)
+
+// dialRPCs connects to the eth rpc endpoints of the config. A single endpoint is used directly.
+func dialRPCs(cfg *Config, l log.Logger, m metrics.Metricer) (EthClient, error) {
+	urls := append([]string{cfg.RPCURL}, cfg.AdditionalRPCURLs...)
+	clients := make([]EthClient, len(urls))
+	for i, rpcURL := range urls {
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial ETH RPC node %s: %s", RPCEndpointName(rpcURL), err.Error())
+		}
+		clients[i] = client
+	}
+
+	if len(clients) == 1 {
+		return clients[0], nil
+	}
+
+	names := make([]string, len(urls))
+	for i, rpcURL := range urls {
+		names[i] = RPCEndpointName(rpcURL)
+	}
+	return NewMultiRPCClient(names, clients, cfg.RPCMode, int(cfg.RPCQuorum), l, m), nil
+}
+
+// NewMultiRPCClient ... constructs an EthClient that spreads reads across the given clients, which
+// are named by the endpoint names in logs, metrics and errors. A quorum of 0 means all the clients.
+func NewMultiRPCClient(names []string, clients []EthClient, mode RPCMode, quorum int, l log.Logger, m metrics.Metricer) EthClient {
+	if quorum <= 0 || quorum > len(clients) {
+		quorum = len(clients)
+	}
+
+	endpoints := make([]rpcEndpoint, len(clients))
+	for i := range clients {
+		endpoints[i] = rpcEndpoint{name: names[i], client: clients[i]}
+	}
+	return &multiRPCClient{endpoints: endpoints, mode: mode, quorum: quorum, l: l, metrics: m}
+}
+
+func (c *multiRPCClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return exactRead(ctx, c, "CodeAt", func(client EthClient) ([]byte, error) {
+		return client.CodeAt(ctx, contract, blockNumber)
+	}, hexutil.Encode)
+}
+
+func (c *multiRPCClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return exactRead(ctx, c, "CallContract", func(client EthClient) ([]byte, error) {
+		return client.CallContract(ctx, call, blockNumber)
+	}, hexutil.Encode)
+}
+
+// BlockNumber returns the highest block that at least a quorum of the endpoints reached in consensus
+// mode, since endpoints that are in sync can still be a block or two apart
+func (c *multiRPCClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return headRead(ctx, c, "BlockNumber", func(client EthClient) (uint64, error) {
+		return client.BlockNumber(ctx)
+	}, func(n uint64) uint64 { return n })
+}
+
+// HeaderByNumber requires the endpoints to agree on the header hash of numbered blocks. For the latest
+// and finalized block tags, the header of the highest block a quorum of endpoints reached is returned.
+func (c *multiRPCClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	read := func(client EthClient) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	}
+	if number == nil || number.Sign() < 0 {
+		return headRead(ctx, c, "HeaderByNumber", read, func(h *types.Header) uint64 { return h.Number.Uint64() })
+	}
+	return exactRead(ctx, c, "HeaderByNumber", read, func(h *types.Header) string { return h.Hash().Hex() })
+}
+
+// failoverRead returns the result of the first endpoint that doesn't fail
+func failoverRead[T any](c *multiRPCClient, method string, read func(EthClient) (T, error)) (T, error) {
+	var errs []error
+	for _, e := range c.endpoints {
+		value, err := read(e.client)
+		if err == nil {
+			c.metrics.RecordEthRPCRequest(e.name, method, "success")
+			return value, nil
+		}
+		c.metrics.RecordEthRPCRequest(e.name, method, "error")
+		c.l.Warn("Eth RPC read failed, failing over to the next endpoint", "endpoint", e.name, "method", method, "err", err)
+		errs = append(errs, fmt.Errorf("%s: %w", e.name, err))
+	}
+
+	var zero T
+	return zero, fmt.Errorf("all eth rpc endpoints failed %s: %w", method, errors.Join(errs...))
+}
+
+type rpcResult[T any] struct {
+	value T
+	err   error
+}
+
+// readAll reads from every endpoint concurrently
+func readAll[T any](c *multiRPCClient, read func(EthClient) (T, error)) []rpcResult[T] {
+	results := make([]rpcResult[T], len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range c.endpoints {
+		wg.Add(1)
+		go func(i int, client EthClient) {
+			defer wg.Done()
+			value, err := read(client)
+			results[i] = rpcResult[T]{value: value, err: err}
+		}(i, e.client)
+	}
+	wg.Wait()
+	return results
+}
+
+// exactRead requires a quorum of the endpoints to return the same result, as identified by key.
+// Endpoints that returned another result or an error are reported, even if the quorum agreed.
+func exactRead[T any](ctx context.Context, c *multiRPCClient, method string, read func(EthClient) (T, error), key func(T) string) (T, error) {
+	if c.mode != ConsensusRPCMode {
+		return failoverRead(c, method, read)
+	}
+
+	results := readAll(c, read)
+	responses := make([]RPCResponse, len(results))
+	votes := make(map[string]int)
+	for i, r := range results {
+		responses[i] = RPCResponse{Endpoint: c.endpoints[i].name, Err: r.err}
+		if r.err == nil {
+			responses[i].Result = key(r.value)
+			votes[responses[i].Result]++
+		}
+	}
+
+	var winner string
+	for result, count := range votes {
+		if count > votes[winner] {
+			winner = result
+		}
+	}
+
+	var zero T
+	if votes[winner] < c.quorum {
+		for i := range responses {
+			c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "disagreement")
+		}
+		err := &RPCDisagreementError{Method: method, Quorum: c.quorum, Responses: responses}
+		c.l.Error("Eth RPC endpoints disagree", "method", method, "err", err)
+		if ctx.Err() != nil {
+			return zero, fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		return zero, err
+	}
+
+	value := zero
+	for i, r := range results {
+		switch {
+		case r.err != nil:
+			c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "error")
+		case responses[i].Result != winner:
+			c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "disagreement")
+		default:
+			c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "success")
+			value = r.value
+		}
+	}
+	if votes[winner] < len(results) {
+		c.l.Warn("Eth RPC endpoints disagree, using the result of the quorum", "method", method,
+			"err", &RPCDisagreementError{Method: method, Quorum: c.quorum, Responses: responses})
+	}
+	return value, nil
+}
+
+// headRead returns the result with the highest block number that at least a quorum of endpoints
+// reached, as reported by the number func
+func headRead[T any](ctx context.Context, c *multiRPCClient, method string, read func(EthClient) (T, error), number func(T) uint64) (T, error) {
+	if c.mode != ConsensusRPCMode {
+		return failoverRead(c, method, read)
+	}
+
+	results := readAll(c, read)
+	responses := make([]RPCResponse, len(results))
+	var succeeded []rpcResult[T]
+	for i, r := range results {
+		responses[i] = RPCResponse{Endpoint: c.endpoints[i].name, Err: r.err}
+		if r.err != nil {
+			c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "error")
+			continue
+		}
+		responses[i].Result = fmt.Sprintf("block %d", number(r.value))
+		c.metrics.RecordEthRPCRequest(responses[i].Endpoint, method, "success")
+		succeeded = append(succeeded, r)
+	}
+
+	if len(succeeded) < c.quorum {
+		var zero T
+		err := &RPCDisagreementError{Method: method, Quorum: c.quorum, Responses: responses}
+		c.l.Error("Too few eth RPC endpoints responded", "method", method, "err", err)
+		if ctx.Err() != nil {
+			return zero, fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		return zero, err
+	}
+
+	// sort by descending block number, so that quorum endpoints are at or above the quorum-th block
+	slices.SortFunc(succeeded, func(a, b rpcResult[T]) int {
+		na, nb := number(a.value), number(b.value)
+		switch {
+		case na > nb:
+			return -1
+		case na < nb:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return succeeded[c.quorum-1].value, nil
+}
@@ -0,0 +1,154 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthClient returns the same result for every read
+type fakeEthClient struct {
+	result      []byte
+	blockNumber uint64
+	err         error
+}
+
+func (c *fakeEthClient) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return c.result, c.err
+}
+
+func (c *fakeEthClient) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return c.result, c.err
+}
+
+func (c *fakeEthClient) BlockNumber(context.Context) (uint64, error) {
+	return c.blockNumber, c.err
+}
+
+func (c *fakeEthClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil || number.Sign() < 0 {
+		number = new(big.Int).SetUint64(c.blockNumber)
+	}
+	return &types.Header{Number: number, Extra: c.result}, c.err
+}
+
+func newTestMultiRPCClient(mode RPCMode, quorum int, clients ...EthClient) EthClient {
+	names := []string{"a", "b", "c"}[:len(clients)]
+	return NewMultiRPCClient(names, clients, mode, quorum, log.New(), metrics.NoopMetrics)
+}
+
+func TestFailoverRPCs(t *testing.T) {
+	down := &fakeEthClient{err: errors.New("connection refused")}
+	up := &fakeEthClient{result: []byte{1}, blockNumber: 10}
+
+	c := newTestMultiRPCClient(FailoverRPCMode, 0, down, up)
+	result, err := c.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, result)
+
+	c = newTestMultiRPCClient(FailoverRPCMode, 0, down, down)
+	_, err = c.BlockNumber(context.Background())
+	require.ErrorContains(t, err, "connection refused")
+}
+
+func TestConsensusRPCs(t *testing.T) {
+	honest := &fakeEthClient{result: []byte{1}, blockNumber: 10}
+	lying := &fakeEthClient{result: []byte{2}, blockNumber: 10}
+	down := &fakeEthClient{err: errors.New("connection refused")}
+
+	t.Run("QuorumAgrees", func(t *testing.T) {
+		c := newTestMultiRPCClient(ConsensusRPCMode, 2, honest, lying, honest)
+		result, err := c.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1}, result)
+
+		c = newTestMultiRPCClient(ConsensusRPCMode, 2, down, honest, honest)
+		result, err = c.CodeAt(context.Background(), common.Address{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1}, result)
+	})
+
+	t.Run("Disagreement", func(t *testing.T) {
+		c := newTestMultiRPCClient(ConsensusRPCMode, 0, honest, lying, honest)
+		_, err := c.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.ErrorIs(t, err, ErrRPCDisagreement)
+
+		var disagreement *RPCDisagreementError
+		require.ErrorAs(t, err, &disagreement)
+		require.Equal(t, "CallContract", disagreement.Method)
+		require.Equal(t, 3, disagreement.Quorum)
+		require.Equal(t, []RPCResponse{
+			{Endpoint: "a", Result: "0x01"},
+			{Endpoint: "b", Result: "0x02"},
+			{Endpoint: "c", Result: "0x01"},
+		}, disagreement.Responses)
+
+		c = newTestMultiRPCClient(ConsensusRPCMode, 2, honest, down, lying)
+		_, err = c.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.ErrorIs(t, err, ErrRPCDisagreement)
+		require.ErrorContains(t, err, "b: error: connection refused")
+	})
+
+	t.Run("NumberedHeaders", func(t *testing.T) {
+		c := newTestMultiRPCClient(ConsensusRPCMode, 0, honest, lying)
+		_, err := c.HeaderByNumber(context.Background(), big.NewInt(5))
+		require.ErrorIs(t, err, ErrRPCDisagreement)
+
+		c = newTestMultiRPCClient(ConsensusRPCMode, 0, honest, honest)
+		header, err := c.HeaderByNumber(context.Background(), big.NewInt(5))
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), header.Number.Uint64())
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		c := newTestMultiRPCClient(ConsensusRPCMode, 2,
+			&fakeEthClient{blockNumber: 12}, &fakeEthClient{blockNumber: 10}, &fakeEthClient{blockNumber: 11})
+
+		// the highest block that two endpoints reached
+		blockNumber, err := c.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), blockNumber)
+
+		header, err := c.HeaderByNumber(context.Background(), big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), header.Number.Uint64())
+
+		c = newTestMultiRPCClient(ConsensusRPCMode, 2, &fakeEthClient{blockNumber: 12}, down, down)
+		_, err = c.BlockNumber(context.Background())
+		require.ErrorIs(t, err, ErrRPCDisagreement)
+	})
+}
+
+func TestCheckRPCs(t *testing.T) {
+	cfg := Config{
+		RPCURL:            "https://a.example",
+		AdditionalRPCURLs: []string{"https://b.example", "https://c.example"},
+		RPCMode:           ConsensusRPCMode,
+		RPCQuorum:         2,
+	}
+	require.NoError(t, cfg.CheckRPCs())
+
+	cfg.RPCQuorum = 1
+	require.ErrorContains(t, cfg.CheckRPCs(), "majority")
+
+	cfg.RPCQuorum = 4
+	require.Error(t, cfg.CheckRPCs())
+
+	cfg.RPCQuorum = 0
+	cfg.RPCMode = "quorum"
+	require.Error(t, cfg.CheckRPCs())
+}
+
+func TestRPCEndpointName(t *testing.T) {
+	require.Equal(t, "mainnet.infura.io", RPCEndpointName("https://mainnet.infura.io/v3/secret-key"))
+	require.Equal(t, "localhost:8545", RPCEndpointName("http://localhost:8545"))
+}
@@ -0,0 +1,219 @@
+package verify
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/Layr-Labs/eigenda/encoding/rs"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// CommitmentVerificationMode ... how blobs are checked against the kzg commitment of their cert
+type CommitmentVerificationMode string
+
+const (
+	// RecommitMode recomputes the commitment of the blob with a MultiExp over the SRS on every verification
+	RecommitMode CommitmentVerificationMode = "recommit"
+	// PointOpeningMode checks an opening proof of the blob polynomial at a Fiat-Shamir challenge
+	// against the commitment. The proof of a commitment is computed by the proxy the first time the
+	// commitment is verified and cached, so that later verifications don't need a MultiExp.
+	PointOpeningMode CommitmentVerificationMode = "point-opening"
+)
+
+func StringToCommitmentVerificationMode(s string) (CommitmentVerificationMode, error) {
+	switch CommitmentVerificationMode(s) {
+	case RecommitMode, PointOpeningMode:
+		return CommitmentVerificationMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown commitment verification mode %q, expected %q or %q", s, RecommitMode, PointOpeningMode)
+	}
+}
+
+// fiat-shamir domain separation tag of the opening challenge
+var openingChallengeDST = []byte("EIGENDA_PROXY_BLOB_OPENING_CHALLENGE")
+
+// opener holds the state needed to open blob polynomials and verify their openings
+type opener struct {
+	// [1]_1, [1]_2 and [tau]_2 of the SRS
+	g1    bn254.G1Affine
+	g2    bn254.G2Affine
+	tauG2 bn254.G2Affine
+	// opening proofs by commitment, computed by the proxy itself
+	proofs *lru.Cache[bn254.G1Affine, bn254.G1Affine]
+}
+
+func newOpener(cfg *kzg.KzgConfig, srs *g1SRS, proofCacheSize int) (*opener, error) {
+	g1, err := srs.G1(1)
+	if err != nil {
+		return nil, fmt.Errorf("no SRS G1 points loaded: %w", err)
+	}
+
+	// the first point of the power of 2 file is [tau^(2^0)]_2
+	tauG2, err := kzg.ReadG2PointOnPowerOf2(0, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read [tau]_2 from the power of 2 file: %w", err)
+	}
+
+	o := &opener{g1: g1[0], g2: kzg.GenG2, tauG2: tauG2}
+	if proofCacheSize > 0 {
+		o.proofs, err = lru.New[bn254.G1Affine, bn254.G1Affine](proofCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// challenge derives the evaluation point of the blob polynomial from its commitment and the blob
+func challenge(commitment *bn254.G1Affine, blob []byte) (fr.Element, error) {
+	commitmentBytes := commitment.Bytes()
+	msg := make([]byte, 0, len(commitmentBytes)+len(blob))
+	msg = append(msg, commitmentBytes[:]...)
+	msg = append(msg, blob...)
+
+	z, err := fr.Hash(msg, openingChallengeDST, 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return z[0], nil
+}
+
+// divide evaluates the polynomial with the given coefficients at z with Horner's method, and returns
+// the evaluation along with the coefficients of the quotient (p(X) - p(z)) / (X - z)
+func divide(coeffs []fr.Element, z fr.Element) (fr.Element, []fr.Element) {
+	if len(coeffs) == 0 {
+		return fr.Element{}, nil
+	}
+
+	quotient := make([]fr.Element, len(coeffs)-1)
+	y := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		quotient[i] = y
+		y.Mul(&y, &z).Add(&y, &coeffs[i])
+	}
+	return y, quotient
+}
+
+// ComputeOpeningProof opens the polynomial of the blob at the challenge derived from the commitment
+// and the blob. This costs a MultiExp of the size of the blob, like recomputing the commitment.
+func (v *Verifier) ComputeOpeningProof(commitment *bn254.G1Affine, blob []byte) (*bn254.G1Affine, error) {
+	coeffs, err := rs.ToFrArray(blob)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert bytes to field elements, %w", err)
+	}
+
+	g1, err := v.srs.G1(uint64(len(coeffs)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open commitment because %w", err)
+	}
+
+	z, err := challenge(commitment, blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive opening challenge: %w", err)
+	}
+
+	_, quotient := divide(coeffs, z)
+
+	var proof bn254.G1Affine
+	if len(quotient) == 0 {
+		// constant polynomials open to the point at infinity
+		return &proof, nil
+	}
+	_, err = proof.MultiExp(g1[:len(quotient)], quotient, ecc.MultiExpConfig{})
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// VerifyOpeningProof checks that the proof opens the commitment to the evaluation of the blob
+// polynomial at the challenge derived from the commitment and the blob, i.e that
+// e(C - [p(z)]_1, [1]_2) == e(proof, [tau - z]_2)
+func (v *Verifier) VerifyOpeningProof(commitment *bn254.G1Affine, blob []byte, proof *bn254.G1Affine) error {
+	if v.opener == nil {
+		return fmt.Errorf("point opening verification is not enabled")
+	}
+	if err := checkG1Point(commitment); err != nil {
+		return fmt.Errorf("%w: invalid commitment: %w", ErrInvalidCert, err)
+	}
+	if err := checkG1Point(proof); err != nil {
+		return fmt.Errorf("invalid opening proof: %w", err)
+	}
+
+	coeffs, err := rs.ToFrArray(blob)
+	if err != nil {
+		return fmt.Errorf("cannot convert bytes to field elements, %w", err)
+	}
+
+	z, err := challenge(commitment, blob)
+	if err != nil {
+		return fmt.Errorf("failed to derive opening challenge: %w", err)
+	}
+	y, _ := divide(coeffs, z)
+
+	// C - [y]_1
+	var yG1, lhsG1 bn254.G1Affine
+	yG1.ScalarMultiplication(&v.opener.g1, y.BigInt(new(big.Int)))
+	lhsG1.Sub(commitment, &yG1)
+
+	// [tau - z]_2
+	var zG2, rhsG2 bn254.G2Affine
+	zG2.ScalarMultiplication(&v.opener.g2, z.BigInt(new(big.Int)))
+	rhsG2.Sub(&v.opener.tauG2, &zG2)
+
+	var negG2 bn254.G2Affine
+	negG2.Neg(&v.opener.g2)
+
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{lhsG1, *proof}, []bn254.G2Affine{negG2, rhsG2})
+	if err != nil {
+		return fmt.Errorf("failed to compute pairing: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: opening proof doesn't match the commitment %x", ErrCommitmentMismatch, commitment.Bytes())
+	}
+	return nil
+}
+
+// verifyCommitmentByOpening checks the blob against the commitment with a cached opening proof of the
+// commitment. Without one, the proof is computed from the blob, and the pairing check then holds
+// iff the commitment of the blob equals the expected one.
+func (v *Verifier) verifyCommitmentByOpening(commitment *bn254.G1Affine, blob []byte) error {
+	// the commitment of the cert is untrusted, and pairings are only meaningful for points of G1
+	if err := checkG1Point(commitment); err != nil {
+		return fmt.Errorf("%w: invalid commitment: %w", ErrInvalidCert, err)
+	}
+
+	if v.opener.proofs != nil {
+		if proof, ok := v.opener.proofs.Get(*commitment); ok {
+			return v.VerifyOpeningProof(commitment, blob, &proof)
+		}
+	}
+
+	proof, err := v.ComputeOpeningProof(commitment, blob)
+	if err != nil {
+		return err
+	}
+	if err := v.VerifyOpeningProof(commitment, blob, proof); err != nil {
+		return err
+	}
+
+	if v.opener.proofs != nil {
+		v.opener.proofs.Add(*commitment, *proof)
+	}
+	return nil
+}
+
+// checkG1Point returns an error if the point isn't on the curve or isn't in the G1 subgroup
+func checkG1Point(p *bn254.G1Affine) error {
+	if !p.IsOnCurve() {
+		return fmt.Errorf("point (%x, %x) is not on the bn254 curve", p.X.Bytes(), p.Y.Bytes())
+	}
+	if !p.IsInSubGroup() {
+		return fmt.Errorf("point (%x, %x) is not in the G1 subgroup", p.X.Bytes(), p.Y.Bytes())
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package verify
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda/api/clients/codecs"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKzgConfig writes the g1 and g2 power of 2 points of a freshly generated SRS with the given
+// number of points, since opening proofs can only be verified against a consistent pair of them
+func newTestKzgConfig(t testing.TB, n uint64) *kzg.KzgConfig {
+	var tau fr.Element
+	_, err := tau.SetRandom()
+	require.NoError(t, err)
+
+	powers := make([]fr.Element, n)
+	powers[0].SetOne()
+	for i := uint64(1); i < n; i++ {
+		powers[i].Mul(&powers[i-1], &tau)
+	}
+
+	// [tau^(2^i)]_2 for every power of 2 below n
+	var g2Powers []fr.Element
+	for p := tau; uint64(1)<<len(g2Powers) < n; p.Square(&p) {
+		g2Powers = append(g2Powers, p)
+	}
+
+	dir := t.TempDir()
+	g1Path := filepath.Join(dir, "g1.point")
+	g2PowerOf2Path := filepath.Join(dir, "g2.point.powerOf2")
+
+	var g1Bytes []byte
+	for _, p := range bn254.BatchScalarMultiplicationG1(&kzg.GenG1, powers) {
+		b := p.Bytes()
+		g1Bytes = append(g1Bytes, b[:]...)
+	}
+	require.NoError(t, os.WriteFile(g1Path, g1Bytes, 0o600))
+
+	var g2Bytes []byte
+	for _, p := range bn254.BatchScalarMultiplicationG2(&kzg.GenG2, g2Powers) {
+		b := p.Bytes()
+		g2Bytes = append(g2Bytes, b[:]...)
+	}
+	require.NoError(t, os.WriteFile(g2PowerOf2Path, g2Bytes, 0o600))
+
+	return &kzg.KzgConfig{
+		G1Path:          g1Path,
+		G2PowerOf2Path:  g2PowerOf2Path,
+		CacheDir:        dir,
+		SRSOrder:        n,
+		SRSNumberToLoad: n,
+		NumWorker:       uint64(runtime.GOMAXPROCS(0)),
+	}
+}
+
+func newTestVerifier(t testing.TB, kzgConfig *kzg.KzgConfig, mode CommitmentVerificationMode, proofCacheSize int) *Verifier {
+	v, err := NewVerifier(&Config{
+		KzgConfig:              kzgConfig,
+		CommitmentVerification: mode,
+		OpeningProofCacheSize:  proofCacheSize,
+	}, nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+	return v
+}
+
+// encodeTestBlob encodes random data of the given size the way the EigenDA client does, and returns
+// it along with its commitment
+func encodeTestBlob(t testing.TB, v *Verifier, size int) ([]byte, *common.G1Commitment) {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	blob, err := codecs.NewIFFTCodec(codecs.NewDefaultBlobCodec()).EncodeBlob(data)
+	require.NoError(t, err)
+
+	commitment, err := v.Commit(blob)
+	require.NoError(t, err)
+	x, y := commitment.X.Bytes(), commitment.Y.Bytes()
+	return blob, &common.G1Commitment{X: x[:], Y: y[:]}
+}
+
+func TestPointOpeningVerification(t *testing.T) {
+	kzgConfig := newTestKzgConfig(t, 1024)
+	v := newTestVerifier(t, kzgConfig, PointOpeningMode, 10)
+
+	blob, commitment := encodeTestBlob(t, v, 10_000)
+	tampered := append([]byte{}, blob...)
+	tampered[100] ^= 1
+
+	// the first verification computes the proof, and later ones use the cached proof
+	require.NoError(t, v.VerifyCommitment(commitment, blob))
+	require.Equal(t, 1, v.opener.proofs.Len())
+	require.NoError(t, v.VerifyCommitment(commitment, blob))
+	require.Error(t, v.VerifyCommitment(commitment, tampered))
+
+	// without a cached proof, tampered blobs are rejected as well
+	uncached := newTestVerifier(t, kzgConfig, PointOpeningMode, 0)
+	require.NoError(t, uncached.VerifyCommitment(commitment, blob))
+	require.Error(t, uncached.VerifyCommitment(commitment, tampered))
+
+	// the recommit mode agrees
+	recommit := newTestVerifier(t, kzgConfig, RecommitMode, 0)
+	require.NoError(t, recommit.VerifyCommitment(commitment, blob))
+	require.Error(t, recommit.VerifyCommitment(commitment, tampered))
+
+	// a proof for one commitment doesn't open another
+	_, other := encodeTestBlob(t, v, 10_000)
+	require.Error(t, v.VerifyCommitment(other, blob))
+	require.Error(t, uncached.VerifyCommitment(other, blob))
+}
+
+func TestPointOpeningOffCurveCommitment(t *testing.T) {
+	v := newTestVerifier(t, newTestKzgConfig(t, 1024), PointOpeningMode, 10)
+	blob, commitment := encodeTestBlob(t, v, 1000)
+
+	// (1, 1) isn't on y^2 = x^3 + 3
+	var one fp.Element
+	one.SetOne()
+	b := one.Bytes()
+	offCurve := &common.G1Commitment{X: b[:], Y: b[:]}
+
+	err := v.VerifyCommitment(offCurve, blob)
+	require.ErrorIs(t, err, ErrInvalidCert)
+	require.ErrorContains(t, err, "not on the bn254 curve")
+	require.Zero(t, v.opener.proofs.Len())
+
+	// the opening proof of a valid commitment doesn't open the off-curve point either
+	require.NoError(t, v.VerifyCommitment(commitment, blob))
+	proof, err := v.ComputeOpeningProof(&bn254.G1Affine{X: one, Y: one}, blob)
+	require.NoError(t, err)
+	require.ErrorIs(t, v.VerifyOpeningProof(&bn254.G1Affine{X: one, Y: one}, blob, proof), ErrInvalidCert)
+}
+
+func TestOpeningProof(t *testing.T) {
+	v := newTestVerifier(t, newTestKzgConfig(t, 1024), PointOpeningMode, 0)
+	blob, _ := encodeTestBlob(t, v, 1000)
+
+	commitment, err := v.Commit(blob)
+	require.NoError(t, err)
+
+	proof, err := v.ComputeOpeningProof(commitment, blob)
+	require.NoError(t, err)
+	require.NoError(t, v.VerifyOpeningProof(commitment, blob, proof))
+
+	// proofs are bound to the challenge of the blob they were computed for
+	otherBlob, _ := encodeTestBlob(t, v, 1000)
+	otherCommitment, err := v.Commit(otherBlob)
+	require.NoError(t, err)
+	otherProof, err := v.ComputeOpeningProof(otherCommitment, otherBlob)
+	require.NoError(t, err)
+	require.Error(t, v.VerifyOpeningProof(commitment, blob, otherProof))
+}
+
+func TestStringToCommitmentVerificationMode(t *testing.T) {
+	mode, err := StringToCommitmentVerificationMode("point-opening")
+	require.NoError(t, err)
+	require.Equal(t, PointOpeningMode, mode)
+
+	_, err = StringToCommitmentVerificationMode("fast")
+	require.Error(t, err)
+}
+
+// BenchmarkVerifyCommitment compares recomputing the commitment of a blob with checking an opening
+// proof of it, both with the proof of the commitment already cached and computed on every read
+func BenchmarkVerifyCommitment(b *testing.B) {
+	sizes := []int{128 << 10, 1 << 20}
+
+	// the IFFT codec pads blobs to a power of 2 number of field elements
+	largest := uint64(1) << bits.Len64(uint64(sizes[len(sizes)-1]/31+1))
+	kzgConfig := newTestKzgConfig(b, largest)
+
+	verifiers := []struct {
+		name string
+		v    *Verifier
+	}{
+		{"recommit", newTestVerifier(b, kzgConfig, RecommitMode, 0)},
+		{"point-opening-cached", newTestVerifier(b, kzgConfig, PointOpeningMode, 10)},
+		{"point-opening-uncached", newTestVerifier(b, kzgConfig, PointOpeningMode, 0)},
+	}
+
+	for _, size := range sizes {
+		blob, commitment := encodeTestBlob(b, verifiers[0].v, size)
+		for _, tc := range verifiers {
+			b.Run(fmt.Sprintf("%s/%dKiB", tc.name, size>>10), func(b *testing.B) {
+				// warm up the proof cache
+				require.NoError(b, tc.v.VerifyCommitment(commitment, blob))
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if err := tc.v.VerifyCommitment(commitment, blob); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
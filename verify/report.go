@@ -0,0 +1,139 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StepStatus ... outcome of a single verification step of a Report
+type StepStatus string
+
+const (
+	StepPassed  StepStatus = "passed"
+	StepFailed  StepStatus = "failed"
+	StepSkipped StepStatus = "skipped"
+)
+
+// names of the verification steps of a Report, in the order they're run
+const (
+	MerkleProofStep    = "merkle_inclusion_proof"
+	KzgCommitmentStep  = "kzg_commitment"
+	BatchStep          = "batch_metadata_hash"
+	SecurityParamsStep = "security_params"
+)
+
+// ReportStep ... result of a single verification step
+type ReportStep struct {
+	Name   string     `json:"name"`
+	Status StepStatus `json:"status"`
+	// value the cert or blob is checked against, and the value found for them
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	// block the contract state was read at
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	// error of a failed step, or why the step was skipped
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report ... step by step result of verifying a cert and its blob. Valid is set if no step failed,
+// so certs whose steps were all skipped are valid as far as they could be checked.
+type Report struct {
+	Valid bool         `json:"valid"`
+	Steps []ReportStep `json:"steps"`
+}
+
+// CertReports ... reports of the certs of a commitment, which holds a single cert or the certs of the
+// parts of a chunked payload
+type CertReports struct {
+	// set if every report is valid
+	Valid   bool      `json:"valid"`
+	Reports []*Report `json:"reports"`
+}
+
+// add records the result of a step, whose values are set by the caller
+func (r *Report) add(step ReportStep, err error) {
+	step.Status = StepPassed
+	if err != nil {
+		r.Valid = false
+		step.Status = StepFailed
+		step.Detail = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+func (r *Report) skip(name, reason string) {
+	r.Steps = append(r.Steps, ReportStep{Name: name, Status: StepSkipped, Detail: reason})
+}
+
+// ReportCert runs the checks of VerifyCommitment and VerifyCert, and reports the result of each
+// of them along with the values compared. Unlike VerifyCert, every step runs even if an earlier one
+// failed. The kzg commitment step is skipped without an encoded blob or an SRS, and otherwise always
+// recomputes the commitment of the blob so that it can be reported. The batch and security params
+// steps are skipped when cert verification is disabled.
+func (v *Verifier) ReportCert(ctx context.Context, cert *Certificate, encodedBlob []byte) *Report {
+	r := &Report{Valid: true}
+
+	root, err := checkMerkleProof(cert.Proof().GetInclusionProof(), cert.BatchHeaderRoot(), cert.Proof().GetBlobIndex(), cert.ReadBlobHeader())
+	step := ReportStep{Name: MerkleProofStep, Expected: fmt.Sprintf("%#x", cert.BatchHeaderRoot())}
+	if root != (common.Hash{}) {
+		step.Actual = root.Hex()
+	}
+	r.add(step, err)
+
+	switch {
+	case encodedBlob == nil:
+		r.skip(KzgCommitmentStep, "no blob provided")
+	case v.srs == nil:
+		r.skip(KzgCommitmentStep, "no SRS loaded")
+	default:
+		r.add(v.reportCommitment(cert, encodedBlob))
+	}
+
+	if !v.verifyCerts {
+		r.skip(BatchStep, "cert verification is disabled")
+		r.skip(SecurityParamsStep, "cert verification is disabled")
+		return r
+	}
+
+	header := batchHeaderOf(cert)
+	check, err := v.cv.checkBatch(ctx, v.cv.readDepth, &header, cert.Proof().GetBatchId(),
+		[32]byte(cert.Proof().BatchMetadata.GetSignatoryRecordHash()), cert.Proof().BatchMetadata.GetConfirmationBlockNumber())
+	step = ReportStep{Name: BatchStep, BlockNumber: check.blockNumber}
+	if check.expected != [32]byte{} {
+		step.Expected = fmt.Sprintf("%#x", check.expected)
+	}
+	if check.actual != [32]byte{} {
+		step.Actual = fmt.Sprintf("%#x", check.actual)
+	}
+	r.add(step, err)
+
+	step = ReportStep{Name: SecurityParamsStep, Actual: fmt.Sprintf("confirmed quorums %v", header.QuorumNumbers)}
+	if required, err := v.cv.quorumNumbersRequired(); err == nil {
+		step.Expected = fmt.Sprintf("required quorums %v", required)
+	}
+	r.add(step, v.VerifySecurityParams(cert.ReadBlobHeader(), header, SecurityParams{}))
+	return r
+}
+
+func (v *Verifier) reportCommitment(cert *Certificate, encodedBlob []byte) (ReportStep, error) {
+	expected := cert.BlobHeader.GetCommitment()
+	step := ReportStep{Name: KzgCommitmentStep, Expected: fmt.Sprintf("(%#x, %#x)", expected.GetX(), expected.GetY())}
+
+	actual, err := v.Commit(encodedBlob)
+	if err != nil {
+		return step, err
+	}
+	x, y := actual.X.Bytes(), actual.Y.Bytes()
+	step.Actual = fmt.Sprintf("(%#x, %#x)", x, y)
+
+	var expectedX, expectedY fp.Element
+	expectedX.Unmarshal(expected.GetX())
+	expectedY.Unmarshal(expected.GetY())
+	if !actual.X.Equal(&expectedX) || !actual.Y.Equal(&expectedY) {
+		return step, ErrCommitmentMismatch
+	}
+	return step, nil
+}
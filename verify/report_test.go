@@ -0,0 +1,131 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCert returns a cert for the commitment, whose blob header is the first leaf of a batch of 2
+func newTestCert(t *testing.T, commitment *common.G1Commitment) *Certificate {
+	cert := &Certificate{
+		BlobHeader: &disperser.BlobHeader{
+			Commitment: commitment,
+			DataLength: 32,
+			BlobQuorumParams: []*disperser.BlobQuorumParam{
+				{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+				{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+			},
+		},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchId: 7,
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader: &disperser.BatchHeader{
+					QuorumNumbers:           []byte{0, 1},
+					QuorumSignedPercentages: []byte{100, 90},
+					ReferenceBlockNumber:    10,
+				},
+				SignatoryRecordHash:     make([]byte, 32),
+				ConfirmationBlockNumber: 12,
+			},
+			QuorumIndexes: []byte{0, 1},
+		},
+	}
+
+	leaf, err := HashEncodeBlobHeader(cert.ReadBlobHeader())
+	require.NoError(t, err)
+	sibling := crypto.Keccak256Hash([]byte("sibling"))
+	cert.BlobVerificationProof.InclusionProof = sibling.Bytes()
+	cert.BlobVerificationProof.BatchMetadata.BatchHeader.BatchRoot = crypto.Keccak256(leaf.Bytes(), sibling.Bytes())
+	return cert
+}
+
+func TestReportCert(t *testing.T) {
+	v := newTestVerifier(t, newTestKzgConfig(t, 1024), RecommitMode, 0)
+	blob, commitment := encodeTestBlob(t, v, 1000)
+	cert := newTestCert(t, commitment)
+
+	report := v.ReportCert(context.Background(), cert, blob)
+	require.True(t, report.Valid)
+	require.Len(t, report.Steps, 4)
+	root := hexutil.Encode(cert.BatchHeaderRoot())
+	require.Equal(t, ReportStep{Name: MerkleProofStep, Status: StepPassed, Expected: root, Actual: root}, report.Steps[0])
+	require.Equal(t, StepPassed, report.Steps[1].Status)
+	require.Equal(t, report.Steps[1].Expected, report.Steps[1].Actual)
+	require.Equal(t, []ReportStep{
+		{Name: BatchStep, Status: StepSkipped, Detail: "cert verification is disabled"},
+		{Name: SecurityParamsStep, Status: StepSkipped, Detail: "cert verification is disabled"},
+	}, report.Steps[2:])
+
+	// every step runs even when an earlier one fails
+	tampered := append([]byte{}, blob...)
+	tampered[100] ^= 1
+	cert.BlobVerificationProof.BlobIndex = 1
+	report = v.ReportCert(context.Background(), cert, tampered)
+	require.False(t, report.Valid)
+	require.Len(t, report.Steps, 4)
+	require.Equal(t, StepFailed, report.Steps[0].Status)
+	require.Contains(t, report.Steps[0].Detail, "root hash mismatch")
+	require.NotEqual(t, report.Steps[0].Expected, report.Steps[0].Actual)
+	require.Equal(t, StepFailed, report.Steps[1].Status)
+	require.NotEqual(t, report.Steps[1].Expected, report.Steps[1].Actual)
+}
+
+func TestReportCertWithoutSRS(t *testing.T) {
+	v, err := NewVerifier(&Config{}, nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	blob, commitment := encodeTestBlob(t, newTestVerifier(t, newTestKzgConfig(t, 1024), RecommitMode, 0), 1000)
+	cert := newTestCert(t, commitment)
+
+	report := v.ReportCert(context.Background(), cert, nil)
+	require.True(t, report.Valid)
+	require.Equal(t, ReportStep{Name: KzgCommitmentStep, Status: StepSkipped, Detail: "no blob provided"}, report.Steps[1])
+
+	report = v.ReportCert(context.Background(), cert, blob)
+	require.Equal(t, ReportStep{Name: KzgCommitmentStep, Status: StepSkipped, Detail: "no SRS loaded"}, report.Steps[1])
+	require.Error(t, v.VerifyCommitment(commitment, blob))
+}
+
+func TestDecodeCertificate(t *testing.T) {
+	cert := newTestCert(t, &common.G1Commitment{X: []byte{1, 2}, Y: []byte{3, 4}})
+	b, err := rlp.EncodeToBytes(cert)
+	require.NoError(t, err)
+
+	decoded, err := DecodeCertificate(b)
+	require.NoError(t, err)
+	require.Equal(t, cert.BatchHeaderRoot(), decoded.BatchHeaderRoot())
+
+	_, err = DecodeCertificate(b[1:])
+	require.Error(t, err)
+
+	// certs without batch metadata are rejected rather than dereferenced
+	cert.BlobVerificationProof.BatchMetadata.SignatoryRecordHash = nil
+	b, err = rlp.EncodeToBytes(cert)
+	require.NoError(t, err)
+	_, err = DecodeCertificate(b)
+	require.Error(t, err)
+}
+
+func TestCertInfo(t *testing.T) {
+	cert := newTestCert(t, &common.G1Commitment{X: []byte{1, 2}, Y: []byte{3, 4}})
+
+	b, err := json.Marshal(cert.Info())
+	require.NoError(t, err)
+
+	var info map[string]any
+	require.NoError(t, json.Unmarshal(b, &info))
+	header := info["blob_header"].(map[string]any)
+	require.Equal(t, map[string]any{"x": "0x0102", "y": "0x0304"}, header["commitment"])
+	metadata := info["blob_verification_proof"].(map[string]any)["batch_metadata"].(map[string]any)
+	require.Equal(t, []any{0.0, 1.0}, metadata["batch_header"].(map[string]any)["quorum_numbers"])
+	require.Equal(t, 12.0, metadata["confirmation_block_number"])
+}
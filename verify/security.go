@@ -0,0 +1,79 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+)
+
+// SecurityParams ... security that a put requires of its blob, on top of what the network requires
+type SecurityParams struct {
+	// quorums the blob is dispersed to in addition to the required and custom quorums
+	QuorumIDs []uint8
+	// minimum adversary threshold percentage of every quorum the blob is dispersed to
+	AdversaryThreshold uint8
+	// minimum percentage of stake that signed the batch in every quorum the blob is dispersed to
+	ConfirmationThreshold uint8
+}
+
+// IsZero ... returns true when nothing beyond the network's security is required
+func (p SecurityParams) IsZero() bool {
+	return len(p.QuorumIDs) == 0 && p.AdversaryThreshold == 0 && p.ConfirmationThreshold == 0
+}
+
+// Check ... verifies that the thresholds are percentages that can both be met
+func (p SecurityParams) Check() error {
+	if p.AdversaryThreshold > 100 || p.ConfirmationThreshold > 100 {
+		return fmt.Errorf("security thresholds must be percentages, got adversary threshold %d and confirmation threshold %d",
+			p.AdversaryThreshold, p.ConfirmationThreshold)
+	}
+	if p.AdversaryThreshold > 0 && p.ConfirmationThreshold > 0 && p.AdversaryThreshold >= p.ConfirmationThreshold {
+		return fmt.Errorf("adversary threshold %d must be lower than confirmation threshold %d", p.AdversaryThreshold, p.ConfirmationThreshold)
+	}
+	return nil
+}
+
+// verify checks that the blob was dispersed to the requested quorums, and that each of its quorums
+// meets the requested thresholds
+func (p SecurityParams) verify(blobHeader BlobHeader, batchHeader binding.IEigenDAServiceManagerBatchHeader) error {
+	if len(batchHeader.SignedStakeForQuorums) < len(blobHeader.QuorumBlobParams) {
+		return fmt.Errorf("batch header has signed stake for %d quorums, expected %d",
+			len(batchHeader.SignedStakeForQuorums), len(blobHeader.QuorumBlobParams))
+	}
+
+	quorums := make([]uint8, len(blobHeader.QuorumBlobParams))
+	for i, qp := range blobHeader.QuorumBlobParams {
+		quorums[i] = qp.QuorumNumber
+
+		if qp.AdversaryThresholdPercentage < p.AdversaryThreshold {
+			return fmt.Errorf("quorum %d adversary threshold %d is lower than the requested %d",
+				qp.QuorumNumber, qp.AdversaryThresholdPercentage, p.AdversaryThreshold)
+		}
+		if batchHeader.SignedStakeForQuorums[i] < p.ConfirmationThreshold {
+			return fmt.Errorf("quorum %d signed stake %d is lower than the requested confirmation threshold %d",
+				qp.QuorumNumber, batchHeader.SignedStakeForQuorums[i], p.ConfirmationThreshold)
+		}
+	}
+
+	for _, quorum := range p.QuorumIDs {
+		if !slices.Contains(quorums, quorum) {
+			return fmt.Errorf("quorum %d was requested but the blob wasn't dispersed to it", quorum)
+		}
+	}
+	return nil
+}
+
+type securityParamsKey struct{}
+
+// WithSecurityParams returns a context that makes stores disperse and verify blobs with the params
+func WithSecurityParams(ctx context.Context, p SecurityParams) context.Context {
+	return context.WithValue(ctx, securityParamsKey{}, p)
+}
+
+// SecurityParamsFromContext ... params attached to ctx, zero if none were
+func SecurityParamsFromContext(ctx context.Context) SecurityParams {
+	p, _ := ctx.Value(securityParamsKey{}).(SecurityParams)
+	return p
+}
@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"testing"
+
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestedSecurityParams(t *testing.T) {
+	blobHeader := BlobHeader{
+		QuorumBlobParams: []QuorumBlobParam{
+			{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55},
+			{QuorumNumber: 2, AdversaryThresholdPercentage: 40, ConfirmationThresholdPercentage: 60},
+		},
+	}
+	batchHeader := binding.IEigenDAServiceManagerBatchHeader{
+		QuorumNumbers:         []byte{0, 2},
+		SignedStakeForQuorums: []byte{80, 95},
+	}
+
+	tests := []struct {
+		name   string
+		params SecurityParams
+		err    bool
+	}{
+		{name: "None", params: SecurityParams{}},
+		{name: "DispersedToQuorum", params: SecurityParams{QuorumIDs: []uint8{2}}},
+		{name: "NotDispersedToQuorum", params: SecurityParams{QuorumIDs: []uint8{3}}, err: true},
+		{name: "AdversaryThresholdMet", params: SecurityParams{AdversaryThreshold: 33}},
+		// quorum 0 was dispersed with a 33% adversary threshold
+		{name: "AdversaryThresholdNotMet", params: SecurityParams{AdversaryThreshold: 34}, err: true},
+		{name: "ConfirmationThresholdMet", params: SecurityParams{ConfirmationThreshold: 80}},
+		// only 80% of quorum 0's stake signed
+		{name: "ConfirmationThresholdNotMet", params: SecurityParams{ConfirmationThreshold: 90}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.verify(blobHeader, batchHeader)
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package simulated
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethsim "github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+)
+
+const (
+	// setterGasLimit is plenty for writing a handful of storage slots
+	setterGasLimit = 1_000_000
+)
+
+var (
+	// ServiceManagerAddr is where the mock EigenDAServiceManager is placed in the simulated genesis
+	ServiceManagerAddr = common.HexToAddress("0x000000000000000000000000000000000000da00")
+
+	// DefaultQuorumNumbersRequired mirrors the quorums required by the EigenDA holesky deployment
+	DefaultQuorumNumbersRequired = []byte{0, 1}
+	// DefaultQuorumAdversaryThresholdPercentages mirrors the adversary thresholds of the EigenDA
+	// holesky deployment
+	DefaultQuorumAdversaryThresholdPercentages = []byte{33, 33}
+)
+
+/*
+Backend is an in-process Ethereum chain, built on go-ethereum's simulated backend, with a mock
+EigenDAServiceManager deployed at ServiceManagerAddr. The mock contract stores batch metadata
+hashes, required quorums and adversary thresholds so that certificates can be verified by
+verify.CertVerifier without access to a real Ethereum node.
+
+Blocks are only produced by Commit, by the setters (each write is mined into its own block) or,
+once Start has been called, on a fixed interval.
+*/
+type Backend struct {
+	// serializes transaction submission and block production
+	mu sync.Mutex
+
+	sim  *gethsim.Backend
+	abi  *abi.ABI
+	opts *bind.TransactOpts
+	// raw transactor against the mock contract
+	contract *bind.BoundContract
+	log      log.Logger
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBackend ... starts a simulated chain with the mock service manager configured with the
+// default required quorums and adversary thresholds
+func NewBackend(l log.Logger) (*Backend, error) {
+	code, err := serviceManagerCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble mock service manager: %w", err)
+	}
+
+	svcManagerABI, err := binding.ContractEigenDAServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service manager abi: %w", err)
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	sim := gethsim.NewBackend(types.GenesisAlloc{
+		from:               {Balance: new(big.Int).Lsh(big.NewInt(1), 128)},
+		ServiceManagerAddr: {Code: code, Balance: big.NewInt(0)},
+	})
+
+	b, err := newBackend(sim, svcManagerABI, key, l)
+	if err != nil {
+		_ = sim.Close()
+		return nil, err
+	}
+
+	if err := b.SetQuorumNumbersRequired(DefaultQuorumNumbersRequired); err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+	if err := b.SetQuorumAdversaryThresholdPercentages(DefaultQuorumAdversaryThresholdPercentages); err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func newBackend(sim *gethsim.Backend, svcManagerABI *abi.ABI, key *ecdsa.PrivateKey, l log.Logger) (*Backend, error) {
+	chainID, err := sim.Client().ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read simulated chain id: %w", err)
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.GasLimit = setterGasLimit
+
+	client := sim.Client()
+	return &Backend{
+		sim:      sim,
+		abi:      svcManagerABI,
+		opts:     opts,
+		contract: bind.NewBoundContract(ServiceManagerAddr, abi.ABI{}, client, client, client),
+		log:      l,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Client returns an RPC client to the simulated chain that can be passed to
+// verify.NewVerifierWithClient
+func (b *Backend) Client() verify.EthClient {
+	return b.sim.Client()
+}
+
+// Start mines an empty block every blockTime until Close is called, so that confirmation
+// depths elapse the same way they would on a live chain
+func (b *Backend) Start(blockTime time.Duration) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(blockTime)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.done:
+				return
+			case <-ticker.C:
+				b.Commit()
+			}
+		}
+	}()
+}
+
+// Commit mines a new block including any pending transactions and returns its number
+func (b *Backend) Commit() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.commit()
+}
+
+func (b *Backend) commit() uint64 {
+	hash := b.sim.Commit()
+	header, err := b.sim.Client().HeaderByHash(context.Background(), hash)
+	if err != nil {
+		// the block was just sealed by the simulated beacon so this can't happen
+		b.log.Error("failed to read simulated block header", "hash", hash, "err", err)
+		return 0
+	}
+	return header.Number.Uint64()
+}
+
+// Fork rewinds the chain to the given ancestor block so that the blocks mined next replace the
+// rewound ones, simulating a reorg
+func (b *Backend) Fork(parentHash common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sim.Fork(parentHash)
+}
+
+// BlockNumber returns the number of the latest mined block
+func (b *Backend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.sim.Client().BlockNumber(ctx)
+}
+
+// SetBatchMetadataHash stores the metadata hash of a confirmed batch, as EigenDAServiceManager.confirmBatch would
+func (b *Backend) SetBatchMetadataHash(ctx context.Context, batchID uint32, hash [32]byte) error {
+	return b.set(ctx, "batchIdToBatchMetadataHash", []interface{}{batchID}, hash)
+}
+
+// SetQuorumNumbersRequired overrides the quorums that every blob must be confirmed in
+func (b *Backend) SetQuorumNumbersRequired(quorums []byte) error {
+	return b.set(context.Background(), "quorumNumbersRequired", nil, quorums)
+}
+
+// SetQuorumAdversaryThresholdPercentages overrides the per quorum adversary thresholds
+func (b *Backend) SetQuorumAdversaryThresholdPercentages(percentages []byte) error {
+	return b.set(context.Background(), "quorumAdversaryThresholdPercentages", nil, percentages)
+}
+
+// ConfirmBatch stores the metadata hash of a batch confirmed in the next block, as
+// EigenDAServiceManager.confirmBatch would, and returns the number of that block. The hash is
+// built from the confirmation block number while block production is held, so that the write
+// can't be mined into a later block than the one the hash commits to.
+func (b *Backend) ConfirmBatch(ctx context.Context, batchID uint32, metadataHash func(confirmationBlock uint64) ([32]byte, error)) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	latest, err := b.sim.Client().BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest block number: %w", err)
+	}
+	hash, err := metadataHash(latest + 1)
+	if err != nil {
+		return 0, err
+	}
+
+	method := "batchIdToBatchMetadataHash"
+	calldata, err := b.setterCalldata(method, []interface{}{batchID}, hash)
+	if err != nil {
+		return 0, err
+	}
+	blockNum, err := b.transact(ctx, method, calldata)
+	if err != nil {
+		return 0, err
+	}
+	if blockNum != latest+1 {
+		// blocks are only mined under the lock, so this can't happen
+		return 0, fmt.Errorf("batch %d was confirmed in block %d rather than %d", batchID, blockNum, latest+1)
+	}
+	return blockNum, nil
+}
+
+// set stores the ABI encoded outputs as the response of the given view function and mines
+// the write into a new block
+func (b *Backend) set(ctx context.Context, method string, args []interface{}, outputs ...interface{}) error {
+	calldata, err := b.setterCalldata(method, args, outputs...)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err = b.transact(ctx, method, calldata)
+	return err
+}
+
+// setterCalldata ... calldata of the mock contract's setter for the response of the view function
+func (b *Backend) setterCalldata(method string, args []interface{}, outputs ...interface{}) ([]byte, error) {
+	input, err := b.abi.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	response, err := b.abi.Methods[method].Outputs.Pack(outputs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s response: %w", method, err)
+	}
+
+	calldata := append([]byte{}, setterSelector...)
+	calldata = append(calldata, crypto.Keccak256(input)...)
+	return append(calldata, response...), nil
+}
+
+// transact sends the setter calldata and mines it into a new block, whose number it returns.
+// b.mu must be held.
+func (b *Backend) transact(ctx context.Context, method string, calldata []byte) (uint64, error) {
+	opts := *b.opts
+	opts.Context = ctx
+	tx, err := b.contract.RawTransact(&opts, calldata)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send %s update: %w", method, err)
+	}
+
+	blockNum := b.commit()
+
+	receipt, err := b.sim.Client().TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s update receipt: %w", method, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return 0, fmt.Errorf("%s update reverted", method)
+	}
+
+	return blockNum, nil
+}
+
+// Close stops block production and shuts down the simulated chain
+func (b *Backend) Close() error {
+	close(b.done)
+	b.wg.Wait()
+	return b.sim.Close()
+}
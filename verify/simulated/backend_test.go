@@ -0,0 +1,477 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackend(t *testing.T) *Backend {
+	b, err := NewBackend(log.New())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, b.Close())
+	})
+	return b
+}
+
+func TestServiceManagerReads(t *testing.T) {
+	b := newTestBackend(t)
+
+	manager, err := binding.NewContractEigenDAServiceManagerCaller(ServiceManagerAddr, b.Client())
+	require.NoError(t, err)
+
+	quorums, err := manager.QuorumNumbersRequired(nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultQuorumNumbersRequired, quorums)
+
+	thresholds, err := manager.QuorumAdversaryThresholdPercentages(nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultQuorumAdversaryThresholdPercentages, thresholds)
+
+	// unknown batch ids read as a zero hash
+	hash, err := manager.BatchIdToBatchMetadataHash(nil, 7)
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{}, hash)
+
+	expected := [32]byte{1, 2, 3}
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 7, expected))
+
+	hash, err = manager.BatchIdToBatchMetadataHash(nil, 7)
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+
+	// other batch ids are unaffected
+	hash, err = manager.BatchIdToBatchMetadataHash(nil, 8)
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{}, hash)
+
+	// responses larger than a single word are stored across several slots
+	require.NoError(t, b.SetQuorumNumbersRequired([]byte{0, 1, 2}))
+	quorums, err = manager.QuorumNumbersRequired(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, quorums)
+}
+
+func TestConfirmBatch(t *testing.T) {
+	b := newTestBackend(t)
+	ctx := context.Background()
+
+	manager, err := binding.NewContractEigenDAServiceManagerCaller(ServiceManagerAddr, b.Client())
+	require.NoError(t, err)
+
+	latest, err := b.BlockNumber(ctx)
+	require.NoError(t, err)
+
+	// the hash is built for, and mined into, the next block
+	var hashedAt uint64
+	blockNum, err := b.ConfirmBatch(ctx, 3, func(confirmationBlock uint64) ([32]byte, error) {
+		hashedAt = confirmationBlock
+		return [32]byte{byte(confirmationBlock)}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, latest+1, blockNum)
+	require.Equal(t, blockNum, hashedAt)
+
+	hash, err := manager.BatchIdToBatchMetadataHash(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNum)}, 3)
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{byte(blockNum)}, hash)
+}
+
+func TestVerifyBatchConfirmationDepth(t *testing.T) {
+	const depth = 3
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:        ServiceManagerAddr.Hex(),
+		ReadConfirmationDepth: depth,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	header := &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte{0xaa},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  1,
+	}
+	signatoryRecordHash := [32]byte{0xbb}
+	confirmationBlock := uint32(2)
+
+	hash, err := verify.HashBatchMetadata(header, signatoryRecordHash, confirmationBlock)
+	require.NoError(t, err)
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, hash))
+
+	for i := 0; i < depth; i++ {
+		err = cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock)
+		require.ErrorIs(t, err, verify.ErrBatchMetadataHashNotFound)
+		b.Commit()
+	}
+
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock))
+
+	// tampered batch header fields no longer hash to the on-chain value
+	tampered := *header
+	tampered.SignedStakeForQuorums = []byte{100, 10}
+	require.Error(t, cv.VerifyBatch(&tampered, 1, signatoryRecordHash, confirmationBlock))
+}
+
+func TestReportCert(t *testing.T) {
+	b := newTestBackend(t)
+	require.NoError(t, b.SetQuorumNumbersRequired([]byte{0, 1}))
+
+	v, err := verify.NewVerifierWithClient(&verify.Config{
+		VerifyCerts:    true,
+		SvcManagerAddr: ServiceManagerAddr.Hex(),
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	batchHeader := &disperser.BatchHeader{
+		BatchRoot:               make([]byte, 32),
+		QuorumNumbers:           []byte{0, 1},
+		QuorumSignedPercentages: []byte{100, 100},
+		ReferenceBlockNumber:    1,
+	}
+	cert := &verify.Certificate{
+		BlobHeader: &disperser.BlobHeader{
+			Commitment: &common.G1Commitment{X: []byte{1}, Y: []byte{2}},
+			BlobQuorumParams: []*disperser.BlobQuorumParam{
+				{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55},
+				{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55},
+			},
+		},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchId: 1,
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:             batchHeader,
+				SignatoryRecordHash:     make([]byte, 32),
+				ConfirmationBlockNumber: 2,
+			},
+		},
+	}
+
+	hash, err := verify.HashBatchMetadata(&binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte(batchHeader.BatchRoot),
+		QuorumNumbers:         batchHeader.QuorumNumbers,
+		SignedStakeForQuorums: batchHeader.QuorumSignedPercentages,
+		ReferenceBlockNumber:  batchHeader.ReferenceBlockNumber,
+	}, [32]byte{}, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, hash))
+
+	// the cert has no inclusion proof, which doesn't stop the contract checks from running
+	report := v.ReportCert(context.Background(), cert, nil)
+	require.False(t, report.Valid)
+	require.Equal(t, verify.StepFailed, report.Steps[0].Status)
+	head, err := b.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, verify.ReportStep{
+		Name:        verify.BatchStep,
+		Status:      verify.StepPassed,
+		Expected:    hash.Hex(),
+		Actual:      hash.Hex(),
+		BlockNumber: head,
+	}, report.Steps[2])
+	require.Equal(t, verify.ReportStep{
+		Name:     verify.SecurityParamsStep,
+		Status:   verify.StepPassed,
+		Expected: "required quorums [0 1]",
+		Actual:   "confirmed quorums [0 1]",
+	}, report.Steps[3])
+
+	// a cert that isn't signed by enough stake no longer matches the on-chain batch either
+	batchHeader.QuorumSignedPercentages = []byte{100, 10}
+	report = v.ReportCert(context.Background(), cert, nil)
+	require.Equal(t, verify.StepFailed, report.Steps[2].Status)
+	require.Equal(t, hash.Hex(), report.Steps[2].Expected)
+	require.NotEqual(t, hash.Hex(), report.Steps[2].Actual)
+	require.Equal(t, verify.StepFailed, report.Steps[3].Status)
+}
+
+func TestSeparateWriteAndReadConfirmationDepths(t *testing.T) {
+	const writeDepth = 3
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:       ServiceManagerAddr.Hex(),
+		EthConfirmationDepth: writeDepth,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	header := &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte{0xaa},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  1,
+	}
+	signatoryRecordHash := [32]byte{0xbb}
+	hash, err := verify.HashBatchMetadata(header, signatoryRecordHash, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, hash))
+	confirmationBlock, err := b.BlockNumber(context.Background())
+	require.NoError(t, err)
+
+	// gets accept the cert right away, while puts wait for the write depth
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, 2))
+
+	done := waitForConfirmationDepth(cv, confirmationBlock)
+	for i := 0; i < writeDepth; i++ {
+		require.Never(t, func() bool { return len(done) > 0 }, 20*time.Millisecond, 5*time.Millisecond)
+		b.Commit()
+	}
+	require.NoError(t, <-done)
+}
+
+func TestVerifyBatchAtConfirmationBlock(t *testing.T) {
+	const depth = 2
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:            ServiceManagerAddr.Hex(),
+		ReadConfirmationDepth:     depth,
+		VerifyAtConfirmationBlock: true,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	header := &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte{0xaa},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  1,
+	}
+	signatoryRecordHash := [32]byte{0xbb}
+
+	// the hash is stored in the next block, which is the batch's confirmation block
+	head, err := b.BlockNumber(context.Background())
+	require.NoError(t, err)
+	confirmationBlock := uint32(head + 1)
+	hash, err := verify.HashBatchMetadata(header, signatoryRecordHash, confirmationBlock)
+	require.NoError(t, err)
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, hash))
+
+	// the confirmation block must still be deep enough
+	err = cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock)
+	require.ErrorIs(t, err, verify.ErrBatchMetadataHashNotFound)
+	for i := 0; i < depth; i++ {
+		b.Commit()
+	}
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock))
+
+	// the state is read at the confirmation block, so later changes to it don't matter
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, [32]byte{}))
+	for i := 0; i < depth; i++ {
+		b.Commit()
+	}
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock))
+
+	// a cert claiming an earlier confirmation block doesn't find its batch there
+	err = cv.VerifyBatch(header, 1, signatoryRecordHash, confirmationBlock-1)
+	require.ErrorIs(t, err, verify.ErrBatchMetadataHashNotFound)
+}
+
+func TestVerifyBatchRPCConsensus(t *testing.T) {
+	honest1, honest2, malicious := newTestBackend(t), newTestBackend(t), newTestBackend(t)
+
+	header := &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte{0xaa},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  1,
+	}
+	signatoryRecordHash := [32]byte{0xbb}
+	hash, err := verify.HashBatchMetadata(header, signatoryRecordHash, 2)
+	require.NoError(t, err)
+
+	// the malicious rpc serves a batch that was never confirmed on the actual chain
+	require.NoError(t, malicious.SetBatchMetadataHash(context.Background(), 1, hash))
+	honest1.Commit()
+	honest2.Commit()
+
+	newCertVerifier := func(client verify.EthClient) *verify.CertVerifier {
+		cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+			SvcManagerAddr: ServiceManagerAddr.Hex(),
+		}, client, log.New(), metrics.NoopMetrics)
+		require.NoError(t, err)
+		return cv
+	}
+
+	// trusting a single rpc lets the invalid cert pass
+	require.NoError(t, newCertVerifier(malicious.Client()).VerifyBatch(header, 1, signatoryRecordHash, 2))
+
+	clients := []verify.EthClient{honest1.Client(), malicious.Client(), honest2.Client()}
+	names := []string{"honest1", "malicious", "honest2"}
+
+	consensus := verify.NewMultiRPCClient(names, clients, verify.ConsensusRPCMode, 2, log.New(), metrics.NoopMetrics)
+	err = newCertVerifier(consensus).VerifyBatch(header, 1, signatoryRecordHash, 2)
+	require.ErrorIs(t, err, verify.ErrBatchMetadataHashNotFound)
+
+	unanimous := verify.NewMultiRPCClient(names, clients, verify.ConsensusRPCMode, 0, log.New(), metrics.NoopMetrics)
+	err = newCertVerifier(unanimous).VerifyBatch(header, 1, signatoryRecordHash, 2)
+	require.ErrorIs(t, err, verify.ErrRPCDisagreement)
+	require.ErrorContains(t, err, "malicious")
+}
+
+// cacheMetrics counts the contract cache lookups of a cert verifier
+type cacheMetrics struct {
+	metrics.Metricer
+	hits   map[string]int
+	misses map[string]int
+}
+
+func newCacheMetrics() *cacheMetrics {
+	return &cacheMetrics{Metricer: metrics.NoopMetrics, hits: map[string]int{}, misses: map[string]int{}}
+}
+
+func (m *cacheMetrics) RecordContractCacheLookup(cache string, hit bool) {
+	if hit {
+		m.hits[cache]++
+	} else {
+		m.misses[cache]++
+	}
+}
+
+func TestVerifyBatchCachesFinalizedBatches(t *testing.T) {
+	b := newTestBackend(t)
+	m := newCacheMetrics()
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr: ServiceManagerAddr.Hex(),
+		Cache:          verify.CacheConfig{BatchMetadataHashes: 10},
+	}, b.Client(), log.New(), m)
+	require.NoError(t, err)
+
+	header := &binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte{0xaa},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  1,
+	}
+	signatoryRecordHash := [32]byte{0xbb}
+	hash, err := verify.HashBatchMetadata(header, signatoryRecordHash, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, hash))
+
+	// the confirmation block isn't finalized yet, so the hash is read from the contract every time
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, 2))
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, 2))
+	require.Equal(t, 0, m.hits["batch_metadata_hash"])
+
+	for {
+		finalized, err := b.Client().HeaderByNumber(context.Background(), big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		require.NoError(t, err)
+		if finalized.Number.Uint64() >= 2 {
+			break
+		}
+		b.Commit()
+	}
+
+	// once finalized, the verified hash is cached and the contract isn't read again
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, 2))
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, [32]byte{}))
+	require.NoError(t, cv.VerifyBatch(header, 1, signatoryRecordHash, 2))
+	require.Equal(t, 1, m.hits["batch_metadata_hash"])
+
+	// cached hashes are still checked against the cert
+	tampered := *header
+	tampered.SignedStakeForQuorums = []byte{100, 10}
+	require.Error(t, cv.VerifyBatch(&tampered, 1, signatoryRecordHash, 2))
+}
+
+// confirmTestBatch stores the metadata hash of a batch, which is mined into a new block, and
+// returns that block as the batch's confirmation block
+func confirmTestBatch(t *testing.T, b *Backend) uint64 {
+	require.NoError(t, b.SetBatchMetadataHash(context.Background(), 1, [32]byte{0xcc}))
+	confirmationBlock, err := b.BlockNumber(context.Background())
+	require.NoError(t, err)
+	return confirmationBlock
+}
+
+func waitForConfirmationDepth(cv *verify.CertVerifier, confirmationBlock uint64) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		done <- cv.WaitForConfirmationDepth(ctx, confirmationBlock, 10*time.Millisecond)
+	}()
+	return done
+}
+
+func TestWaitForConfirmationDepth(t *testing.T) {
+	const depth = 3
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:       ServiceManagerAddr.Hex(),
+		EthConfirmationDepth: depth,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	done := waitForConfirmationDepth(cv, confirmTestBatch(t, b))
+	for i := 0; i < depth; i++ {
+		require.Never(t, func() bool { return len(done) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+		b.Commit()
+	}
+	require.NoError(t, <-done)
+}
+
+func TestWaitForConfirmationFinalized(t *testing.T) {
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:   ServiceManagerAddr.Hex(),
+		WaitForFinalized: true,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	confirmationBlock := confirmTestBatch(t, b)
+	done := waitForConfirmationDepth(cv, confirmationBlock)
+
+	// the simulated beacon finalizes blocks once per 32 block epoch
+	for {
+		finalized, err := b.Client().HeaderByNumber(context.Background(), big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		require.NoError(t, err)
+		if finalized.Number.Uint64() >= confirmationBlock {
+			break
+		}
+		require.Empty(t, done)
+		b.Commit()
+	}
+	require.NoError(t, <-done)
+}
+
+func TestWaitForConfirmationDetectsReorg(t *testing.T) {
+	const depth = 3
+	b := newTestBackend(t)
+
+	cv, err := verify.NewCertVerifierWithClient(&verify.Config{
+		SvcManagerAddr:       ServiceManagerAddr.Hex(),
+		EthConfirmationDepth: depth,
+	}, b.Client(), log.New(), metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	confirmationBlock := confirmTestBatch(t, b)
+	done := waitForConfirmationDepth(cv, confirmationBlock)
+	// let the confirmation block hash be observed before it's reorged out
+	require.Never(t, func() bool { return len(done) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+
+	parent, err := b.Client().HeaderByNumber(context.Background(), new(big.Int).SetUint64(confirmationBlock-1))
+	require.NoError(t, err)
+	require.NoError(t, b.Fork(parent.Hash()))
+
+	// the replacing blocks have different contents than the reorged out confirmation block
+	require.NoError(t, b.SetQuorumNumbersRequired(DefaultQuorumNumbersRequired))
+	for i := 0; i < depth; i++ {
+		b.Commit()
+	}
+	require.ErrorIs(t, <-done, verify.ErrConfirmationBlockReorged)
+}
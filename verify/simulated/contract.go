@@ -0,0 +1,146 @@
+package simulated
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// setterSelector is reserved by the mock service manager to write canned responses. No function
+// of the real EigenDAServiceManager ABI uses it.
+var setterSelector = []byte{0xff, 0xff, 0xff, 0xff}
+
+// EVM opcodes used by the mock service manager
+const (
+	opStop         = 0x00
+	opAdd          = 0x01
+	opSub          = 0x03
+	opLt           = 0x10
+	opEq           = 0x14
+	opIsZero       = 0x15
+	opShr          = 0x1c
+	opKeccak256    = 0x20
+	opCalldataLoad = 0x35
+	opCalldataSize = 0x36
+	opCalldataCopy = 0x37
+	opPop          = 0x50
+	opMstore       = 0x52
+	opSload        = 0x54
+	opSstore       = 0x55
+	opJump         = 0x56
+	opJumpi        = 0x57
+	opJumpDest     = 0x5b
+	opPush1        = 0x60
+	opPush2        = 0x61
+	opPush4        = 0x63
+	opDup1         = 0x80
+	opDup2         = 0x81
+	opDup3         = 0x82
+	opSwap1        = 0x90
+	opReturn       = 0xf3
+)
+
+// assembler ... minimal EVM assembler supporting forward and backward jump labels
+type assembler struct {
+	code   []byte
+	labels map[string]int
+	// offsets of the PUSH2 immediates that need to be patched with a label's position
+	fixups map[int]string
+}
+
+func newAssembler() *assembler {
+	return &assembler{
+		labels: make(map[string]int),
+		fixups: make(map[int]string),
+	}
+}
+
+func (a *assembler) op(ops ...byte) *assembler {
+	a.code = append(a.code, ops...)
+	return a
+}
+
+func (a *assembler) push1(v byte) *assembler {
+	return a.op(opPush1, v)
+}
+
+func (a *assembler) push4(v []byte) *assembler {
+	return a.op(opPush4).op(v...)
+}
+
+// pushLabel pushes the code offset of a label onto the stack
+func (a *assembler) pushLabel(name string) *assembler {
+	a.op(opPush2)
+	a.fixups[len(a.code)] = name
+	return a.op(0, 0)
+}
+
+// label marks the current position as a valid jump destination
+func (a *assembler) label(name string) *assembler {
+	a.labels[name] = len(a.code)
+	return a.op(opJumpDest)
+}
+
+func (a *assembler) assemble() ([]byte, error) {
+	for offset, name := range a.fixups {
+		pos, ok := a.labels[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined label: %s", name)
+		}
+		binary.BigEndian.PutUint16(a.code[offset:], uint16(pos)) // #nosec G115
+	}
+	return a.code, nil
+}
+
+/*
+serviceManagerCode returns the runtime bytecode of a generic mock contract which answers every
+call with a canned response previously written to its storage. This lets the simulated backend
+serve any view function of the EigenDAServiceManager binding (e.g, batchIdToBatchMetadataHash,
+quorumNumbersRequired, quorumAdversaryThresholdPercentages) without a solidity toolchain.
+
+  - setter: calldata = 0xffffffff || key (32 bytes) || response. The response length is stored
+    at slot `key` and its words at slots `key+1, key+2, ...`.
+  - getter: any other calldata is hashed with keccak256 to derive `key`, and the stored response
+    is returned. Unknown keys return a single zero word, which mirrors how an unset mapping
+    entry or empty value reads from the real contract.
+*/
+func serviceManagerCode() ([]byte, error) {
+	a := newAssembler()
+
+	// dispatch on the function selector
+	a.push1(0).op(opCalldataLoad).push1(224).op(opShr)
+	a.push4(setterSelector).op(opEq).pushLabel("setter").op(opJumpi)
+
+	// getter: key = keccak256(calldata)
+	a.op(opCalldataSize).push1(0).push1(0).op(opCalldataCopy)
+	a.op(opCalldataSize).push1(0).op(opKeccak256) // [key]
+	a.op(opDup1, opSload)                         // [key, len]
+	a.op(opDup1, opIsZero).pushLabel("empty").op(opJumpi)
+	a.op(opSwap1).push1(1).op(opAdd) // [len, slot]
+	a.push1(0)                       // [len, slot, offset]
+	a.label("read")
+	a.op(opDup3, opDup2, opLt, opIsZero).pushLabel("respond").op(opJumpi)
+	a.op(opDup2, opSload, opDup2, opMstore)          // mem[offset] = sload(slot)
+	a.push1(32).op(opAdd, opSwap1).push1(1).op(opAdd) // [len, offset+32, slot+1]
+	a.op(opSwap1).pushLabel("read").op(opJump)        // [len, slot+1, offset+32]
+	a.label("respond")
+	a.op(opPop, opPop).push1(0).op(opReturn) // return(0, len)
+	a.label("empty")
+	// memory past the copied calldata is still zeroed
+	a.push1(32).op(opCalldataSize, opReturn)
+
+	// setter: store the response words starting at slot key+1
+	a.label("setter")
+	a.push1(4).op(opCalldataLoad).push1(1).op(opAdd) // [slot]
+	a.push1(36)                                       // [slot, offset]
+	a.label("write")
+	a.op(opCalldataSize, opDup2, opLt, opIsZero).pushLabel("done").op(opJumpi)
+	a.op(opDup1, opCalldataLoad, opDup3, opSstore)    // sstore(slot, calldata[offset:offset+32])
+	a.push1(32).op(opAdd, opSwap1).push1(1).op(opAdd) // [offset+32, slot+1]
+	a.op(opSwap1).pushLabel("write").op(opJump)       // [slot+1, offset+32]
+	a.label("done")
+	// sstore(key, calldatasize - 36)
+	a.push1(36).op(opCalldataSize, opSub).push1(4).op(opCalldataLoad, opSstore)
+	a.op(opStop)
+
+	return a.assemble()
+}
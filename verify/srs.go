@@ -0,0 +1,243 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SRSLoadingMode ... when the G1 points of the SRS are decoded from the memory-mapped g1.point file
+type SRSLoadingMode string
+
+const (
+	// EagerSRSLoading decodes every G1 point that the max blob length needs at startup, in parallel
+	EagerSRSLoading SRSLoadingMode = "eager"
+	// LazySRSLoading decodes G1 points the first time a blob needs them, so that startup only maps the
+	// file and memory grows with the largest blob committed to
+	LazySRSLoading SRSLoadingMode = "lazy"
+)
+
+func StringToSRSLoadingMode(s string) (SRSLoadingMode, error) {
+	switch SRSLoadingMode(s) {
+	case EagerSRSLoading, LazySRSLoading:
+		return SRSLoadingMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown SRS loading mode %q, expected %q or %q", s, EagerSRSLoading, LazySRSLoading)
+	}
+}
+
+const (
+	// G1SHA256 ... sha256 checksum of the resources/g1.point file shipped with the proxy
+	G1SHA256 = "844e5451afcc256e4ee6d9583797af86dc0f8570490d8548264ceef722f93a56"
+	// G2PowerOf2SHA256 ... sha256 checksum of the resources/g2.point.powerOf2 file shipped with the proxy
+	G2PowerOf2SHA256 = "4d5ed827f742e1270f22b4a39129bf1d25445821b15824e2eb3a709a16f64518"
+)
+
+const (
+	// G1 points are decoded in batches of this many points, so that growing blobs don't decode a few
+	// points at a time
+	srsDecodeBatch = 1 << 16
+	// size of a decoded G1 point in memory, which holds two 32 byte field elements
+	g1AffineBytes = 64
+)
+
+// g1SRS ... G1 points of the SRS, memory-mapped from the g1.point file and decoded on demand
+type g1SRS struct {
+	m         metrics.Metricer
+	numWorker int
+
+	// mapped bytes of the points that can be loaded, which the decoded points are read from
+	data  []byte
+	unmap func() error
+
+	mu      sync.Mutex
+	decoded []bn254.G1Affine
+}
+
+// loadG1SRS checks the SRS files against their checksums, and maps the G1 points that cfg allows to load.
+// The points are all decoded before returning in EagerSRSLoading mode.
+func loadG1SRS(cfg *Config, l log.Logger, m metrics.Metricer) (*g1SRS, error) {
+	kzgCfg := cfg.KzgConfig
+	start := time.Now()
+
+	if kzgCfg.SRSNumberToLoad > kzgCfg.SRSOrder {
+		return nil, fmt.Errorf("SRS order %d is less than the number of SRS points to load %d", kzgCfg.SRSOrder, kzgCfg.SRSNumberToLoad)
+	}
+	if err := checkSRSChecksum(kzgCfg.G1Path, cfg.G1Checksum); err != nil {
+		return nil, err
+	}
+	if err := checkSRSChecksum(kzgCfg.G2PowerOf2Path, cfg.G2PowerOf2Checksum); err != nil {
+		return nil, err
+	}
+	if err := checkG2PowerOf2(kzgCfg); err != nil {
+		return nil, err
+	}
+
+	s := &g1SRS{m: m, numWorker: int(max(kzgCfg.NumWorker, 1))} // #nosec G115
+	if err := s.mapFile(kzgCfg.G1Path, kzgCfg.SRSNumberToLoad*kzg.G1PointBytes); err != nil {
+		return nil, err
+	}
+
+	if cfg.SRSLoading != LazySRSLoading {
+		if _, err := s.G1(kzgCfg.SRSNumberToLoad); err != nil {
+			_ = s.close()
+			return nil, err
+		}
+	}
+
+	elapsed := time.Since(start)
+	m.RecordSRSLoad(elapsed, uint64(len(s.data)))
+	if l == nil {
+		l = log.Root()
+	}
+	l.Info("Loaded SRS", "mode", cfg.SRSLoading, "duration", elapsed, "mapped_bytes", len(s.data),
+		"decoded_points", len(s.decoded), "decoded_bytes", len(s.decoded)*g1AffineBytes)
+	return s, nil
+}
+
+func (s *g1SRS) mapFile(path string, size uint64) error {
+	if size == 0 {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open g1 points file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat g1 points file: %w", err)
+	}
+	if uint64(info.Size()) < size { // #nosec G115
+		return fmt.Errorf("g1 points file %s holds %d points, but %d are needed", path, info.Size()/kzg.G1PointBytes, size/kzg.G1PointBytes)
+	}
+	if size > math.MaxInt {
+		return fmt.Errorf("g1 points file is too large to map")
+	}
+
+	s.data, s.unmap, err = mapFile(f, int(size))
+	if err != nil {
+		return fmt.Errorf("failed to map g1 points file: %w", err)
+	}
+	// the decoded points don't point into the mapping, which can be released with the SRS
+	runtime.SetFinalizer(s, func(s *g1SRS) { _ = s.close() })
+	return nil
+}
+
+func (s *g1SRS) close() error {
+	if s.unmap == nil {
+		return nil
+	}
+	err := s.unmap()
+	s.data, s.unmap = nil, nil
+	return err
+}
+
+// maxPoints ... number of points that can be decoded
+func (s *g1SRS) maxPoints() uint64 {
+	return uint64(len(s.data) / kzg.G1PointBytes)
+}
+
+// G1 returns the first n G1 points of the SRS, and decodes the ones that weren't decoded yet
+func (s *g1SRS) G1(n uint64) ([]bn254.G1Affine, error) {
+	if n > s.maxPoints() {
+		return nil, fmt.Errorf("the number of stored srs in the memory is insufficient, have %v need %v", s.maxPoints(), n)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from := uint64(len(s.decoded))
+	if n <= from {
+		return s.decoded[:n], nil
+	}
+	to := min(max(n, from+srsDecodeBatch), s.maxPoints())
+
+	// points below from may be read concurrently through slices returned earlier, and are never written again
+	decoded := append(s.decoded, make([]bn254.G1Affine, to-from)...)
+	if err := s.decode(decoded, from, to); err != nil {
+		return nil, err
+	}
+	s.decoded = decoded
+	s.m.RecordSRSDecodedPoints(uint64(len(decoded)), uint64(len(decoded))*g1AffineBytes)
+	return decoded[:n], nil
+}
+
+// decode decodes the points in [from, to) in parallel
+func (s *g1SRS) decode(out []bn254.G1Affine, from, to uint64) error {
+	workers := min(uint64(s.numWorker), to-from) // #nosec G115
+	size := (to - from + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for start := from; start < to; start += size {
+		end := min(start+size, to)
+		wg.Add(1)
+		go func(start, end uint64) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if _, err := out[i].SetBytes(s.data[i*kzg.G1PointBytes : (i+1)*kzg.G1PointBytes]); err != nil {
+					errs <- fmt.Errorf("failed to decode g1 point %d: %w", i, err)
+					return
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// checkG2PowerOf2 makes sure the power of 2 file holds the G2 points up to the SRS order, which the
+// point opening verification reads [tau]_2 from
+func checkG2PowerOf2(cfg *kzg.KzgConfig) error {
+	if cfg.G2PowerOf2Path == "" {
+		return fmt.Errorf("g2 power of 2 path is empty")
+	}
+	if cfg.SRSOrder == 0 {
+		return fmt.Errorf("SRS order cannot be 0")
+	}
+	maxPower := uint64(math.Log2(float64(cfg.SRSOrder)))
+	if _, err := kzg.ReadG2PointSection(cfg.G2PowerOf2Path, 0, maxPower, 1); err != nil {
+		return fmt.Errorf("g2 power of 2 file located at %v is invalid: %w", cfg.G2PowerOf2Path, err)
+	}
+	return nil
+}
+
+// checkSRSChecksum compares the sha256 checksum of an SRS file with the expected hex encoded one,
+// unless none is expected. The file is hashed as a stream so that it isn't held in memory.
+func checkSRSChecksum(path string, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open SRS file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to hash SRS file %s: %w", path, err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != strings.TrimPrefix(strings.ToLower(expected), "0x") {
+		return fmt.Errorf("SRS file %s has sha256 checksum %s, expected %s", path, actual, expected)
+	}
+	return nil
+}
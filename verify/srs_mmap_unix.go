@@ -0,0 +1,18 @@
+//go:build unix
+
+package verify
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the first size bytes of the file read-only, so that its pages are only read when the
+// points they hold are decoded
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+	"github.com/Layr-Labs/eigenda/encoding/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestG1Config writes a g1.point file of n points, which are all the generator since their decoding
+// is what's tested and generating an SRS of more than a decode batch is slow
+func newTestG1Config(t testing.TB, n uint64) *kzg.KzgConfig {
+	g1Path := filepath.Join(t.TempDir(), "g1.point")
+	g := kzg.GenG1.Bytes()
+	require.NoError(t, os.WriteFile(g1Path, bytes.Repeat(g[:], int(n)), 0o600))
+
+	return &kzg.KzgConfig{
+		G1Path:          g1Path,
+		G2PowerOf2Path:  "../resources/g2.point.powerOf2",
+		SRSOrder:        n,
+		SRSNumberToLoad: n,
+		NumWorker:       uint64(runtime.GOMAXPROCS(0)),
+	}
+}
+
+func TestSRSLoading(t *testing.T) {
+	const n = srsDecodeBatch + 1000
+	kzgConfig := newTestG1Config(t, n)
+	expected, err := kzg.ReadG1Points(kzgConfig.G1Path, n, kzgConfig.NumWorker)
+	require.NoError(t, err)
+
+	t.Run("Eager", func(t *testing.T) {
+		srs, err := loadG1SRS(&Config{KzgConfig: kzgConfig, SRSLoading: EagerSRSLoading}, nil, metrics.NoopMetrics)
+		require.NoError(t, err)
+		require.Len(t, srs.decoded, n)
+		require.Equal(t, expected, srs.decoded)
+	})
+
+	t.Run("Lazy", func(t *testing.T) {
+		srs, err := loadG1SRS(&Config{KzgConfig: kzgConfig, SRSLoading: LazySRSLoading}, nil, metrics.NoopMetrics)
+		require.NoError(t, err)
+		require.Empty(t, srs.decoded)
+
+		// points are decoded a batch at a time
+		g1, err := srs.G1(10)
+		require.NoError(t, err)
+		require.Equal(t, expected[:10], g1)
+		require.Len(t, srs.decoded, srsDecodeBatch)
+
+		g1, err = srs.G1(n)
+		require.NoError(t, err)
+		require.Equal(t, expected, g1)
+
+		_, err = srs.G1(n + 1)
+		require.ErrorContains(t, err, "insufficient")
+	})
+
+	t.Run("MissingPoints", func(t *testing.T) {
+		cfg := *kzgConfig
+		cfg.SRSNumberToLoad, cfg.SRSOrder = n+1, n+1
+		_, err := loadG1SRS(&Config{KzgConfig: &cfg, SRSLoading: LazySRSLoading}, nil, metrics.NoopMetrics)
+		require.ErrorContains(t, err, "are needed")
+	})
+}
+
+func TestSRSChecksums(t *testing.T) {
+	kzgConfig := newTestG1Config(t, 16)
+	g1, err := os.ReadFile(kzgConfig.G1Path)
+	require.NoError(t, err)
+	g1Checksum := sha256.Sum256(g1)
+
+	_, err = loadG1SRS(&Config{KzgConfig: kzgConfig, G1Checksum: hex.EncodeToString(g1Checksum[:])}, nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	_, err = loadG1SRS(&Config{KzgConfig: kzgConfig, G1Checksum: G2PowerOf2SHA256}, nil, metrics.NoopMetrics)
+	require.ErrorContains(t, err, "sha256 checksum")
+
+	// the power of 2 file shipped with the proxy matches its known checksum
+	_, err = loadG1SRS(&Config{KzgConfig: kzgConfig, G2PowerOf2Checksum: G2PowerOf2SHA256}, nil, metrics.NoopMetrics)
+	require.NoError(t, err)
+
+	_, err = loadG1SRS(&Config{KzgConfig: kzgConfig, G2PowerOf2Checksum: hex.EncodeToString(g1Checksum[:])}, nil, metrics.NoopMetrics)
+	require.ErrorContains(t, err, "sha256 checksum")
+
+	// the g1.point file shipped with the proxy matches its known checksum, when it was fetched
+	if _, err := os.Stat("../resources/g1.point"); err == nil {
+		require.NoError(t, checkSRSChecksum("../resources/g1.point", G1SHA256))
+	}
+}
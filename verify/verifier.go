@@ -1,89 +1,176 @@
 package verify
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
+
 	binding "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
 
 	"github.com/Layr-Labs/eigenda/api/grpc/common"
 	"github.com/Layr-Labs/eigenda/encoding/kzg"
-	kzgverifier "github.com/Layr-Labs/eigenda/encoding/kzg/verifier"
 	"github.com/Layr-Labs/eigenda/encoding/rs"
 )
 
 type Config struct {
+	// nil for verifiers that only check certs, which then can't verify kzg commitments
 	KzgConfig   *kzg.KzgConfig
 	VerifyCerts bool
 	// below 3 fields are only required if VerifyCerts is true
-	RPCURL               string
-	SvcManagerAddr       string
+	RPCURL         string
+	SvcManagerAddr string
+	// eth rpc endpoints used along with RPCURL, and how reads are spread across all of them
+	AdditionalRPCURLs []string
+	RPCMode           RPCMode
+	// number of endpoints that must agree on every read in ConsensusRPCMode (0 means all of them)
+	RPCQuorum uint
+	// number of blocks the block a batch was confirmed in must be below the head before a put returns
 	EthConfirmationDepth uint64
+	// wait for the block a batch was confirmed in to be finalized instead of EthConfirmationDepth blocks deep
+	WaitForFinalized bool
+	// same as EthConfirmationDepth and WaitForFinalized, but for certs to be accepted on gets
+	ReadConfirmationDepth uint64
+	ReadWaitForFinalized  bool
+	// read the batch metadata hash at the cert's confirmation block rather than at the block that's
+	// the confirmation depth below the head (which still must be at or above the confirmation block)
+	VerifyAtConfirmationBlock bool
+	// caching of the service manager contract reads
+	Cache CacheConfig
+	// how blobs are verified against their kzg commitment (defaults to RecommitMode)
+	CommitmentVerification CommitmentVerificationMode
+	// max number of opening proofs cached by commitment in PointOpeningMode
+	OpeningProofCacheSize int
+	// when the G1 points of the SRS are decoded (defaults to EagerSRSLoading)
+	SRSLoading SRSLoadingMode
+	// hex encoded sha256 checksums the SRS files must match, which aren't checked if empty
+	G1Checksum         string
+	G2PowerOf2Checksum string
 }
 
-// TODO: right now verification and confirmation depth are tightly coupled. we should decouple them
 type Verifier struct {
-	// kzgVerifier is needed to commit blobs to the memstore
-	kzgVerifier *kzgverifier.Verifier
+	// srs is needed to commit blobs to the memstore
+	srs *g1SRS
 	// cert verification is optional, and verifies certs retrieved from eigenDA when turned on
 	verifyCerts bool
 	cv          *CertVerifier
+	// set when commitments are verified with point openings
+	opener *opener
+}
+
+func NewVerifier(cfg *Config, l log.Logger, m metrics.Metricer) (*Verifier, error) {
+	var cv *CertVerifier
+	var err error
+
+	if cfg.VerifyCerts {
+		cv, err = NewCertVerifier(cfg, l, m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cert verifier: %w", err)
+		}
+	}
+
+	return newVerifier(cfg, cv, l, m)
 }
 
-func NewVerifier(cfg *Config, l log.Logger) (*Verifier, error) {
+// NewVerifierWithClient ... constructs a verifier whose cert verification reads the service manager
+// through the provided client rather than dialing cfg.RPCURL (e.g, a simulated Ethereum backend)
+func NewVerifierWithClient(cfg *Config, client EthClient, l log.Logger, m metrics.Metricer) (*Verifier, error) {
 	var cv *CertVerifier
 	var err error
 
 	if cfg.VerifyCerts {
-		cv, err = NewCertVerifier(cfg, l)
+		cv, err = NewCertVerifierWithClient(cfg, client, l, m)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create cert verifier: %w", err)
 		}
 	}
 
-	kzgVerifier, err := kzgverifier.NewVerifier(cfg.KzgConfig, false)
+	return newVerifier(cfg, cv, l, m)
+}
+
+func newVerifier(cfg *Config, cv *CertVerifier, l log.Logger, m metrics.Metricer) (*Verifier, error) {
+	if cfg.KzgConfig == nil {
+		if cfg.CommitmentVerification == PointOpeningMode {
+			return nil, fmt.Errorf("point opening verification requires a kzg config")
+		}
+		return &Verifier{verifyCerts: cfg.VerifyCerts, cv: cv}, nil
+	}
+
+	srs, err := loadG1SRS(cfg, l, m)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kzg verifier: %w", err)
+		return nil, fmt.Errorf("failed to load SRS: %w", err)
+	}
+
+	var o *opener
+	if cfg.CommitmentVerification == PointOpeningMode {
+		o, err = newOpener(cfg.KzgConfig, srs, cfg.OpeningProofCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up point opening verification: %w", err)
+		}
 	}
 
 	return &Verifier{
-		kzgVerifier: kzgVerifier,
+		srs:         srs,
 		verifyCerts: cfg.VerifyCerts,
 		cv:          cv,
+		opener:      o,
 	}, nil
 }
 
+// CheckRPCs ... verifies the eth rpc endpoint configuration used for cert verification
+func (cfg *Config) CheckRPCs() error {
+	if cfg.RPCURL == "" {
+		return fmt.Errorf("cert verification enabled but eth rpc is not set")
+	}
+	if cfg.RPCMode != "" {
+		if _, err := StringToRPCMode(string(cfg.RPCMode)); err != nil {
+			return err
+		}
+	}
+
+	endpoints := uint(1 + len(cfg.AdditionalRPCURLs))
+	if cfg.RPCQuorum > endpoints {
+		return fmt.Errorf("eth rpc quorum %d is larger than the number of eth rpc endpoints %d", cfg.RPCQuorum, endpoints)
+	}
+	// with a minority quorum, two conflicting results could both be accepted
+	if cfg.RPCMode == ConsensusRPCMode && cfg.RPCQuorum != 0 && cfg.RPCQuorum <= endpoints/2 {
+		return fmt.Errorf("eth rpc quorum %d must be a majority of the %d eth rpc endpoints", cfg.RPCQuorum, endpoints)
+	}
+	return nil
+}
+
 // verifies V0 eigenda certificate type
 func (v *Verifier) VerifyCert(cert *Certificate) error {
 	if !v.verifyCerts {
 		return nil
 	}
+	return v.verifyCert(context.Background(), cert, v.cv.readDepth, SecurityParams{})
+}
 
+func (v *Verifier) verifyCert(ctx context.Context, cert *Certificate, depth confirmationDepth, requested SecurityParams) error {
 	// 1 - verify batch
-	header := binding.IEigenDAServiceManagerBatchHeader{
-		BlobHeadersRoot:       [32]byte(cert.Proof().GetBatchMetadata().GetBatchHeader().GetBatchRoot()),
-		QuorumNumbers:         cert.Proof().GetBatchMetadata().GetBatchHeader().GetQuorumNumbers(),
-		ReferenceBlockNumber:  cert.Proof().GetBatchMetadata().GetBatchHeader().GetReferenceBlockNumber(),
-		SignedStakeForQuorums: cert.Proof().GetBatchMetadata().GetBatchHeader().GetQuorumSignedPercentages(),
-	}
+	header := batchHeaderOf(cert)
 
-	err := v.cv.VerifyBatch(&header, cert.Proof().GetBatchId(), [32]byte(cert.Proof().BatchMetadata.GetSignatoryRecordHash()), cert.Proof().BatchMetadata.GetConfirmationBlockNumber())
+	err := v.cv.verifyBatch(ctx, depth, &header, cert.Proof().GetBatchId(), [32]byte(cert.Proof().BatchMetadata.GetSignatoryRecordHash()), cert.Proof().BatchMetadata.GetConfirmationBlockNumber())
 	if err != nil {
 		return fmt.Errorf("failed to verify batch: %w", err)
 	}
 
 	// 2 - verify merkle inclusion proof
-	err = v.cv.VerifyMerkleProof(cert.Proof().GetInclusionProof(), cert.BatchHeaderRoot(), cert.Proof().GetBlobIndex(), cert.ReadBlobHeader())
+	err = verifyCertMerkleProof(cert)
 	if err != nil {
 		return fmt.Errorf("failed to verify merkle proof: %w", err)
 	}
 
 	// 3 - verify security parameters
-	err = v.VerifySecurityParams(cert.ReadBlobHeader(), header)
+	err = v.VerifySecurityParams(cert.ReadBlobHeader(), header, requested)
 	if err != nil {
 		return fmt.Errorf("failed to verify security parameters: %w", err)
 	}
@@ -91,6 +178,39 @@ func (v *Verifier) VerifyCert(cert *Certificate) error {
 	return nil
 }
 
+func verifyCertMerkleProof(cert *Certificate) error {
+	return verifyMerkleProof(cert.Proof().GetInclusionProof(), cert.BatchHeaderRoot(), cert.Proof().GetBlobIndex(), cert.ReadBlobHeader())
+}
+
+func batchHeaderOf(cert *Certificate) binding.IEigenDAServiceManagerBatchHeader {
+	return binding.IEigenDAServiceManagerBatchHeader{
+		BlobHeadersRoot:       [32]byte(cert.Proof().GetBatchMetadata().GetBatchHeader().GetBatchRoot()),
+		QuorumNumbers:         cert.Proof().GetBatchMetadata().GetBatchHeader().GetQuorumNumbers(),
+		ReferenceBlockNumber:  cert.Proof().GetBatchMetadata().GetBatchHeader().GetReferenceBlockNumber(),
+		SignedStakeForQuorums: cert.Proof().GetBatchMetadata().GetBatchHeader().GetQuorumSignedPercentages(),
+	}
+}
+
+// WaitForConfirmation blocks until the block the cert's batch was confirmed in reaches the confirmation
+// depth (or is finalized), then verifies the cert against the contract state at that depth and the
+// security params requested for the blob. It returns ErrConfirmationBlockReorged if the confirmation
+// block is reorged out while waiting, in which case the cert should be fetched again from the disperser.
+// When cert verification is disabled, only the requested security params are checked against the cert.
+func (v *Verifier) WaitForConfirmation(ctx context.Context, cert *Certificate, requested SecurityParams, pollInterval time.Duration) error {
+	if !v.verifyCerts {
+		if err := requested.verify(cert.ReadBlobHeader(), batchHeaderOf(cert)); err != nil {
+			return fmt.Errorf("failed to verify security parameters: %w: %w", ErrInvalidCert, err)
+		}
+		return nil
+	}
+
+	confirmationBlock := uint64(cert.Proof().GetBatchMetadata().GetConfirmationBlockNumber())
+	if err := v.cv.WaitForConfirmationDepth(ctx, confirmationBlock, pollInterval); err != nil {
+		return err
+	}
+	return v.verifyCert(ctx, cert, v.cv.writeDepth, requested)
+}
+
 // compute kzg-bn254 commitment of raw blob data using SRS
 func (v *Verifier) Commit(blob []byte) (*bn254.G1Affine, error) {
 	inputFr, err := rs.ToFrArray(blob)
@@ -98,13 +218,17 @@ func (v *Verifier) Commit(blob []byte) (*bn254.G1Affine, error) {
 		return nil, fmt.Errorf("cannot convert bytes to field elements, %w", err)
 	}
 
-	if len(v.kzgVerifier.Srs.G1) < len(inputFr) {
-		return nil, fmt.Errorf("cannot verify commitment because the number of stored srs in the memory is insufficient, have %v need %v", len(v.kzgVerifier.Srs.G1), len(inputFr))
+	if v.srs == nil {
+		return nil, fmt.Errorf("cannot commit to blob because no SRS is loaded")
+	}
+	g1, err := v.srs.G1(uint64(len(inputFr)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify commitment because %w", err)
 	}
 
 	config := ecc.MultiExpConfig{}
 	var commitment bn254.G1Affine
-	_, err = commitment.MultiExp(v.kzgVerifier.Srs.G1[:len(inputFr)], inputFr, config)
+	_, err = commitment.MultiExp(g1, inputFr, config)
 	if err != nil {
 		return nil, err
 	}
@@ -113,34 +237,55 @@ func (v *Verifier) Commit(blob []byte) (*bn254.G1Affine, error) {
 }
 
 // Verify regenerates a commitment from the blob and asserts equivalence
-// to the commitment in the certificate
-// TODO: Optimize implementation by opening a point on the commitment instead
+// to the commitment in the certificate. In PointOpeningMode, an opening proof
+// of the blob polynomial is checked against the commitment instead.
 func (v *Verifier) VerifyCommitment(expectedCommit *common.G1Commitment, blob []byte) error {
-	actualCommit, err := v.Commit(blob)
-	if err != nil {
-		return err
-	}
-
 	expectedX := &fp.Element{}
 	expectedX.Unmarshal(expectedCommit.X)
 	expectedY := &fp.Element{}
 	expectedY.Unmarshal(expectedCommit.Y)
 
+	if v.opener != nil {
+		return v.verifyCommitmentByOpening(&bn254.G1Affine{X: *expectedX, Y: *expectedY}, blob)
+	}
+
+	actualCommit, err := v.Commit(blob)
+	if err != nil {
+		return err
+	}
+
 	errMsg := ""
 	if !actualCommit.X.Equal(expectedX) || !actualCommit.Y.Equal(expectedY) {
 		errMsg += fmt.Sprintf("field elements do not match, x actual commit: %x, x expected commit: %x, ", actualCommit.X.Marshal(), expectedX.Marshal())
 		errMsg += fmt.Sprintf("y actual commit: %x, y expected commit: %x", actualCommit.Y.Marshal(), expectedY.Marshal())
-		return fmt.Errorf("%s", errMsg)
+		return fmt.Errorf("%w: %s", ErrCommitmentMismatch, errMsg)
 	}
 
 	return nil
 }
 
-// VerifySecurityParams ensures that returned security parameters are valid
-func (v *Verifier) VerifySecurityParams(blobHeader BlobHeader, batchHeader binding.IEigenDAServiceManagerBatchHeader) error {
+// VerifySecurityParams ensures that returned security parameters are valid, and that they meet the
+// ones requested for the blob (if any). Certs that fail the checks are reported as ErrInvalidCert.
+func (v *Verifier) VerifySecurityParams(blobHeader BlobHeader, batchHeader binding.IEigenDAServiceManagerBatchHeader, requested SecurityParams) error {
+	err := v.verifySecurityParams(blobHeader, batchHeader, requested)
+	if err != nil && !errors.Is(err, ErrEthRPCUnavailable) {
+		return fmt.Errorf("%w: %w", ErrInvalidCert, err)
+	}
+	return err
+}
+
+func (v *Verifier) verifySecurityParams(blobHeader BlobHeader, batchHeader binding.IEigenDAServiceManagerBatchHeader, requested SecurityParams) error {
+	if err := requested.verify(blobHeader, batchHeader); err != nil {
+		return err
+	}
+
 	confirmedQuorums := make(map[uint8]bool)
 
 	// require that the security param in each blob is met
+	if len(batchHeader.QuorumNumbers) < len(blobHeader.QuorumBlobParams) || len(batchHeader.SignedStakeForQuorums) < len(blobHeader.QuorumBlobParams) {
+		return fmt.Errorf("batch header has fewer quorums than the blob header")
+	}
+
 	for i := 0; i < len(blobHeader.QuorumBlobParams); i++ {
 		if batchHeader.QuorumNumbers[i] != blobHeader.QuorumBlobParams[i].QuorumNumber {
 			return fmt.Errorf("quorum number mismatch, expected: %d, got: %d", batchHeader.QuorumNumbers[i], blobHeader.QuorumBlobParams[i].QuorumNumber)
@@ -151,6 +296,9 @@ func (v *Verifier) VerifySecurityParams(blobHeader BlobHeader, batchHeader bindi
 		}
 
 		quorumAdversaryThreshold, err := v.getQuorumAdversaryThreshold(blobHeader.QuorumBlobParams[i].QuorumNumber)
+		if errors.Is(err, ErrRPCDisagreement) {
+			return fmt.Errorf("%w: failed to get quorum adversary threshold: %w", ErrEthRPCUnavailable, err)
+		}
 		if err != nil {
 			log.Warn("failed to get quorum adversary threshold", "err", err)
 		}
@@ -166,7 +314,10 @@ func (v *Verifier) VerifySecurityParams(blobHeader BlobHeader, batchHeader bindi
 		confirmedQuorums[blobHeader.QuorumBlobParams[i].QuorumNumber] = true
 	}
 
-	requiredQuorums, err := v.cv.manager.QuorumNumbersRequired(nil)
+	requiredQuorums, err := v.cv.quorumNumbersRequired()
+	if errors.Is(err, ErrRPCDisagreement) {
+		return fmt.Errorf("%w: failed to get required quorum numbers: %w", ErrEthRPCUnavailable, err)
+	}
 	if err != nil {
 		log.Warn("failed to get required quorum numbers", "err", err)
 	}
@@ -184,7 +335,7 @@ func (v *Verifier) VerifySecurityParams(blobHeader BlobHeader, batchHeader bindi
 // getQuorumAdversaryThreshold reads the adversarial threshold percentage for a given quorum number
 // returns 0 if DNE
 func (v *Verifier) getQuorumAdversaryThreshold(quorumNum uint8) (uint8, error) {
-	percentages, err := v.cv.manager.QuorumAdversaryThresholdPercentages(nil)
+	percentages, err := v.cv.quorumAdversaryThresholdPercentages()
 	if err != nil {
 		return 0, err
 	}
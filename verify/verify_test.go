@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda/api/clients/codecs"
 	"github.com/Layr-Labs/eigenda/api/grpc/common"
 	"github.com/Layr-Labs/eigenda/encoding/kzg"
@@ -44,7 +45,7 @@ func TestCommitmentVerification(t *testing.T) {
 		KzgConfig:   kzgConfig,
 	}
 
-	v, err := NewVerifier(cfg, nil)
+	v, err := NewVerifier(cfg, nil, metrics.NoopMetrics)
 	require.NoError(t, err)
 
 	// Happy path verification
@@ -82,7 +83,7 @@ func TestCommitmentWithTooLargeBlob(t *testing.T) {
 		KzgConfig:   kzgConfig,
 	}
 
-	v, err := NewVerifier(cfg, nil)
+	v, err := NewVerifier(cfg, nil, metrics.NoopMetrics)
 	require.NoError(t, err)
 
 	// Some wrong commitment just to pass in function